@@ -1,12 +1,180 @@
 package corporation
 
-import "github.com/luisya22/galactic-exchange/internal/world"
+import (
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/maputils"
+	"github.com/luisya22/galactic-exchange/internal/world"
+)
 
 type Base struct {
 	ID                 uint64
 	Name               string
 	Location           world.Coordinates
 	ResourceProduction map[string]int
+	ResourceUpkeep     map[string]int
+	CreditUpkeep       float64
 	StorageCapacity    float64
 	StoredResources    map[string]int
+	Facilities         []Facility
+	Constructions      []FacilityType
+	Jobs               []ProductionJob
+}
+
+func (b *Base) copy() gamecomm.Base {
+	facilities := make([]gamecomm.Facility, 0, len(b.Facilities))
+	for _, f := range b.Facilities {
+		facilities = append(facilities, gamecomm.Facility{Type: string(f.Type), Level: f.Level})
+	}
+
+	constructions := make([]string, 0, len(b.Constructions))
+	for _, ft := range b.Constructions {
+		constructions = append(constructions, string(ft))
+	}
+
+	jobs := make([]gamecomm.ProductionJob, 0, len(b.Jobs))
+	for _, j := range b.Jobs {
+		jobs = append(jobs, gamecomm.ProductionJob{Recipe: j.Recipe, Batches: j.Batches})
+	}
+
+	return gamecomm.Base{
+		ID:                 b.ID,
+		Name:               b.Name,
+		Location:           gamecomm.Coordinates{X: b.Location.X, Y: b.Location.Y},
+		ResourceProduction: maputils.CopyMap(b.ResourceProduction),
+		ResourceUpkeep:     maputils.CopyMap(b.ResourceUpkeep),
+		CreditUpkeep:       b.CreditUpkeep,
+		StorageCapacity:    b.StorageCapacity,
+		StoredResources:    maputils.CopyMap(b.StoredResources),
+		Facilities:         facilities,
+		Constructions:      constructions,
+		Jobs:               jobs,
+	}
+}
+
+// facility returns the installed facility of the type or nil if the base doesn't have it
+func (b *Base) facility(facilityType FacilityType) *Facility {
+	for i := range b.Facilities {
+		if b.Facilities[i].Type == facilityType {
+			return &b.Facilities[i]
+		}
+	}
+
+	return nil
+}
+
+func (b *Base) isUnderConstruction(facilityType FacilityType) bool {
+	for _, ft := range b.Constructions {
+		if ft == facilityType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Base) removeConstruction(facilityType FacilityType) {
+	constructions := []FacilityType{}
+	for _, ft := range b.Constructions {
+		if ft != facilityType {
+			constructions = append(constructions, ft)
+		}
+	}
+	b.Constructions = constructions
+}
+
+// UpdateProduction calculates the base daily production and upkeep from its installed facilities
+func (b *Base) UpdateProduction() {
+	b.ResourceProduction = make(map[string]int)
+	b.ResourceUpkeep = make(map[string]int)
+	b.CreditUpkeep = 0
+
+	for _, f := range b.Facilities {
+		definition, ok := facilityDefinitions[f.Type]
+		if !ok {
+			continue
+		}
+
+		for r, amount := range definition.production {
+			b.ResourceProduction[r] += amount * f.Level
+		}
+
+		for r, amount := range definition.upkeep {
+			b.ResourceUpkeep[r] += amount * f.Level
+		}
+
+		b.CreditUpkeep += definition.creditUpkeep * float64(f.Level)
+	}
+}
+
+func (b *Base) storedAmount() int {
+	total := 0
+	for _, amount := range b.StoredResources {
+		total += amount
+	}
+
+	return total
+}
+
+// freeStorage returns the storage left once the outputs of the running jobs are stored, it's negative when the
+// base holds more than it should
+func (b *Base) freeStorage() int {
+	reserved := 0
+	for _, j := range b.Jobs {
+		reserved += j.Reserved
+	}
+
+	return int(b.StorageCapacity) - b.storedAmount() - reserved
+}
+
+// efficiency returns the fraction of the upkeep the base can pay with its stored resources and the credits
+func (b *Base) efficiency(credits float64) float64 {
+	efficiency := 1.0
+
+	for r, needed := range b.ResourceUpkeep {
+		if needed == 0 {
+			continue
+		}
+
+		efficiency = min(efficiency, float64(b.StoredResources[r])/float64(needed))
+	}
+
+	if b.CreditUpkeep > 0 {
+		efficiency = min(efficiency, max(credits, 0)/b.CreditUpkeep)
+	}
+
+	return efficiency
+}
+
+// produce runs one day of production. Facilities work as much as the upkeep paid allows and the production
+// is stored until the base storage is full. Returns the credits spent on upkeep.
+func (b *Base) produce(credits float64) float64 {
+	if b.StoredResources == nil {
+		b.StoredResources = make(map[string]int)
+	}
+
+	efficiency := b.efficiency(credits)
+	if efficiency <= 0 {
+		return 0
+	}
+
+	for r, needed := range b.ResourceUpkeep {
+		b.StoredResources[r] -= int(float64(needed) * efficiency)
+	}
+
+	resources := make([]string, 0, len(b.ResourceProduction))
+	for r := range b.ResourceProduction {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+
+	freeStorage := max(b.freeStorage(), 0)
+	for _, r := range resources {
+		produced := min(int(float64(b.ResourceProduction[r])*efficiency), freeStorage)
+		b.StoredResources[r] += produced
+		freeStorage -= produced
+	}
+
+	return b.CreditUpkeep * efficiency
 }
@@ -0,0 +1,249 @@
+package corporation
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/maputils"
+	"github.com/luisya22/galactic-exchange/internal/world"
+)
+
+const baseStorageCapacity = 50_000
+
+// constructionCost is what a construction takes from the corporation and the time it takes to build
+type constructionCost struct {
+	credits   float64
+	materials map[string]int
+	buildTime gameclock.GameTimeDuration
+}
+
+var baseConstructionCost = constructionCost{
+	credits:   5_000,
+	materials: map[string]int{"iron": 3_000, "water": 1_000, "food": 1_000},
+	buildTime: 5 * gameclock.Day,
+}
+
+// forLevel returns the cost of building the level, every level costs and takes as much as the first one
+// times the level
+func (cc constructionCost) forLevel(level int) constructionCost {
+	materials := make(map[string]int, len(cc.materials))
+	for r, amount := range cc.materials {
+		materials[r] = amount * level
+	}
+
+	return constructionCost{
+		credits:   cc.credits * float64(level),
+		materials: materials,
+		buildTime: cc.buildTime * gameclock.GameTimeDuration(level),
+	}
+}
+
+func (cc constructionCost) copy() gamecomm.ConstructionCost {
+	return gamecomm.ConstructionCost{
+		Credits:   cc.credits,
+		Materials: maputils.CopyMap(cc.materials),
+		BuildTime: cc.buildTime,
+	}
+}
+
+// pay takes the credits and materials of the construction from the corporation and the base storage. Nothing
+// is taken if the corporation can't afford all of it.
+func (c *Corporation) pay(cost constructionCost, base *Base) error {
+	if cost.credits > c.Credits {
+		return fmt.Errorf("error: not enough credits")
+	}
+
+	for r, amount := range cost.materials {
+		if base.StoredResources[r] < amount {
+			return fmt.Errorf("error: not enough %v on base %v", r, base.Name)
+		}
+	}
+
+	c.Credits -= cost.credits
+	for r, amount := range cost.materials {
+		base.StoredResources[r] -= amount
+	}
+
+	return nil
+}
+
+// refund gives back the credits and materials paid for a construction that didn't go ahead
+func (c *Corporation) refund(cost constructionCost, base *Base) {
+	if base.StoredResources == nil {
+		base.StoredResources = make(map[string]int)
+	}
+
+	c.Credits += cost.credits
+	for r, amount := range cost.materials {
+		base.StoredResources[r] += amount
+	}
+}
+
+func (c *Corporation) GetBaseConstructionCost() gamecomm.ConstructionCost {
+	return baseConstructionCost.copy()
+}
+
+// StartBaseConstruction pays for a new base. The materials leave the corporation main base so squads can
+// deliver them to the construction site.
+func (c *Corporation) StartBaseConstruction() (gamecomm.ConstructionCost, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if len(c.Bases) == 0 {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: corporation has no base to send materials from")
+	}
+
+	err := c.pay(baseConstructionCost, c.Bases[0])
+	if err != nil {
+		return gamecomm.ConstructionCost{}, err
+	}
+
+	c.BaseConstructions++
+
+	return baseConstructionCost.copy(), nil
+}
+
+// CancelBaseConstruction refunds a base construction whose squads never left, the materials go back to the
+// corporation main base
+func (c *Corporation) CancelBaseConstruction() (gamecomm.ConstructionCost, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if len(c.Bases) == 0 {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: corporation has no base to return materials to")
+	}
+
+	if c.BaseConstructions == 0 {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: corporation has no base under construction")
+	}
+
+	c.BaseConstructions--
+	c.refund(baseConstructionCost, c.Bases[0])
+
+	return baseConstructionCost.copy(), nil
+}
+
+// FoundBase adds a new empty base on the location once its construction is done
+func (c *Corporation) FoundBase(name string, location world.Coordinates) (gamecomm.Base, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if name == "" {
+		return gamecomm.Base{}, fmt.Errorf("error: base should have a name")
+	}
+
+	id := uint64(0)
+	for _, b := range c.Bases {
+		id = max(id, b.ID)
+	}
+
+	base := &Base{
+		ID:              id + 1,
+		Name:            name,
+		Location:        location,
+		StorageCapacity: baseStorageCapacity,
+		StoredResources: make(map[string]int),
+	}
+	base.UpdateProduction()
+
+	c.Bases = append(c.Bases, base)
+	c.BaseConstructions = max(c.BaseConstructions-1, 0)
+
+	return base.copy(), nil
+}
+
+// StartFacilityConstruction pays for the facility with the base materials and marks it as under construction.
+// Building a facility the base already has upgrades it to the next level.
+func (c *Corporation) StartFacilityConstruction(baseIndex int, facilityType FacilityType) (gamecomm.ConstructionCost, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	definition, ok := facilityDefinitions[facilityType]
+	if !ok {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: invalid facility %v", facilityType)
+	}
+
+	if baseIndex < 0 || baseIndex >= len(c.Bases) {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := c.Bases[baseIndex]
+
+	if base.isUnderConstruction(facilityType) {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: %v already under construction on base %v", facilityType, base.Name)
+	}
+
+	level := 1
+	if f := base.facility(facilityType); f != nil {
+		level = f.Level + 1
+	}
+
+	cost := definition.cost.forLevel(level)
+
+	err := c.pay(cost, base)
+	if err != nil {
+		return gamecomm.ConstructionCost{}, err
+	}
+
+	base.Constructions = append(base.Constructions, facilityType)
+
+	return cost.copy(), nil
+}
+
+// CancelFacilityConstruction stops the construction of the facility and refunds what was paid for it
+func (c *Corporation) CancelFacilityConstruction(baseIndex int, facilityType FacilityType) (gamecomm.ConstructionCost, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if baseIndex < 0 || baseIndex >= len(c.Bases) {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := c.Bases[baseIndex]
+
+	if !base.isUnderConstruction(facilityType) {
+		return gamecomm.ConstructionCost{}, fmt.Errorf("error: %v is not under construction on base %v", facilityType, base.Name)
+	}
+
+	base.removeConstruction(facilityType)
+
+	level := 1
+	if f := base.facility(facilityType); f != nil {
+		level = f.Level + 1
+	}
+
+	cost := facilityDefinitions[facilityType].cost.forLevel(level)
+	c.refund(cost, base)
+
+	return cost.copy(), nil
+}
+
+// CompleteFacilityConstruction installs the facility, or its next level, on the base
+func (c *Corporation) CompleteFacilityConstruction(baseIndex int, facilityType FacilityType) (gamecomm.Base, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if baseIndex < 0 || baseIndex >= len(c.Bases) {
+		return gamecomm.Base{}, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := c.Bases[baseIndex]
+
+	if !base.isUnderConstruction(facilityType) {
+		return gamecomm.Base{}, fmt.Errorf("error: %v is not under construction on base %v", facilityType, base.Name)
+	}
+
+	base.removeConstruction(facilityType)
+
+	if f := base.facility(facilityType); f != nil {
+		f.Level++
+	} else {
+		base.Facilities = append(base.Facilities, Facility{Type: facilityType, Level: 1})
+	}
+
+	base.StorageCapacity += facilityDefinitions[facilityType].storage
+	base.UpdateProduction()
+
+	return base.copy(), nil
+}
@@ -4,15 +4,24 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
 	"github.com/luisya22/galactic-exchange/internal/maputils"
+	"github.com/luisya22/galactic-exchange/internal/resource"
 )
 
 type CorpGroup struct {
-	Corporations map[uint64]*Corporation
-	RW           sync.RWMutex
-	Workers      int
-	CorpChan     chan gamecomm.CorpCommand
+	Corporations     map[uint64]*Corporation
+	RW               sync.RWMutex
+	Workers          int
+	CorpChan         chan gamecomm.CorpCommand
+	Recipes          map[string]resource.Recipe
+	gameClock        *gameclock.GameClock
+	newDayChan       chan gameclock.GameTime
+	economyChan      chan gamecomm.EconomyCommand
+	eventSubscribers []chan gamecomm.CorporationEvent
+	ledger           *ledger.Ledger
 }
 
 type Corporation struct {
@@ -25,19 +34,68 @@ type Corporation struct {
 	Squads                          []*Squad
 	IsPlayer                        bool
 	ReputationWithOtherCorporations map[string]int
-	Rw                              sync.RWMutex
+	Status                          gamecomm.CorporationStatus
+	InsolventSince                  gameclock.GameTime
+	// BaseConstructions is the number of paid base constructions whose base isn't founded yet
+	BaseConstructions int
+	Rw                sync.RWMutex
 }
 
-func NewCorpGroup(gameChannels *gamecomm.GameChannels) *CorpGroup {
+func NewCorpGroup(gameChannels *gamecomm.GameChannels, gc *gameclock.GameClock, l *ledger.Ledger) *CorpGroup {
+	newDayChan := make(chan gameclock.GameTime)
+	gc.Subscribe(newDayChan)
+
 	return &CorpGroup{
 		Corporations: make(map[uint64]*Corporation, 50),
 		Workers:      100,
 		CorpChan:     gameChannels.CorpChannel,
+		Recipes:      resource.LoadRecipes(),
+		gameClock:    gc,
+		newDayChan:   newDayChan,
+		economyChan:  gameChannels.EconomyChannel,
+		ledger:       l,
 	}
 }
 
 func (cg *CorpGroup) Run() {
 	cg.Listen()
+	go cg.simulateProduction()
+}
+
+func (cg *CorpGroup) simulateProduction() {
+	for now := range cg.newDayChan {
+		cg.RunBaseProduction()
+		cg.RunPayroll()
+		cg.CheckSolvency(now)
+	}
+}
+
+// RunBaseProduction runs one day of production on every base of every corporation
+func (cg *CorpGroup) RunBaseProduction() {
+	for _, c := range cg.corporations() {
+		cg.post("base production", c.runBaseProduction())
+	}
+}
+
+// runBaseProduction returns the postings of the goods made and used by the bases and the credits paid for upkeep
+func (c *Corporation) runBaseProduction() []gamecomm.Posting {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	postings := []gamecomm.Posting{}
+	for i, b := range c.Bases {
+		before := maputils.CopyMap(b.StoredResources)
+		upkeep := b.produce(c.Credits)
+		c.charge(upkeep)
+
+		postings = append(postings, stockChanges(gamecomm.NewBaseAccount(c.ID, i), before, b.StoredResources, productionAccount)...)
+		postings = append(postings,
+			gamecomm.Posting{Account: gamecomm.NewCorporationAccount(c.ID), Asset: gamecomm.CreditsAsset, Amount: -upkeep},
+			gamecomm.Posting{Account: upkeepAccount, Asset: gamecomm.CreditsAsset, Amount: upkeep},
+		)
+	}
+
+	return postings
 }
 
 func (c *CorpGroup) FindCorporation(corporationId uint64) (Corporation, error) {
@@ -79,11 +137,23 @@ func (c *CorpGroup) findCorporation(corporationId uint64) (gamecomm.Corporation,
 		return gamecomm.Corporation{}, fmt.Errorf("Corporation not found: %v", corporationId)
 	}
 
+	corporation.Rw.RLock()
+	defer corporation.Rw.RUnlock()
+
+	bases := make([]*gamecomm.Base, 0, len(corporation.Bases))
+	for _, b := range corporation.Bases {
+		base := b.copy()
+		bases = append(bases, &base)
+	}
+
 	corpCopy := gamecomm.Corporation{
 		ID:         corporation.ID,
 		Name:       corporation.Name,
 		Reputation: corporation.Reputation,
 		Credits:    corporation.Credits,
+		Bases:      bases,
+		IsPlayer:   corporation.IsPlayer,
+		Status:     corporation.Status,
 	}
 
 	return corpCopy, nil
@@ -106,6 +176,8 @@ func (c *Corporation) copy() Corporation {
 		CrewMembers:                     crewMembersCopy,
 		IsPlayer:                        c.IsPlayer,
 		ReputationWithOtherCorporations: maputils.CopyMap(c.ReputationWithOtherCorporations),
+		Status:                          c.Status,
+		InsolventSince:                  c.InsolventSince,
 	}
 }
 
@@ -150,7 +222,7 @@ func (c *CorpGroup) RemoveCredits(corporationId uint64, amount float64) (float64
 	return corporation.Credits, nil
 }
 
-func (c *CorpGroup) RemoveResources(corporationId uint64, resource string, amount int) (int, error) {
+func (c *CorpGroup) RemoveResources(corporationId uint64, baseIndex int, resource string, amount int) (int, error) {
 
 	if amount < 0 {
 		return 0, fmt.Errorf("error: amount should be greater than zero")
@@ -163,17 +235,22 @@ func (c *CorpGroup) RemoveResources(corporationId uint64, resource string, amoun
 	corporation.Rw.Lock()
 	defer corporation.Rw.Unlock()
 
-	// TODO: Select the correct base
-	if resourcesAmount, ok := corporation.Bases[0].StoredResources[resource]; !ok || resourcesAmount < amount {
+	if baseIndex < 0 || baseIndex >= len(corporation.Bases) {
+		return 0, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := corporation.Bases[baseIndex]
+
+	if resourcesAmount, ok := base.StoredResources[resource]; !ok || resourcesAmount < amount {
 		return 0, fmt.Errorf("error: not enough resources on base")
 	}
 
-	corporation.Bases[0].StoredResources[resource] -= amount
+	base.StoredResources[resource] -= amount
 
-	return corporation.Bases[0].StoredResources[resource], nil
+	return base.StoredResources[resource], nil
 }
 
-func (c *CorpGroup) AddResources(corporationId uint64, resource string, amount int) (int, error) {
+func (c *CorpGroup) AddResources(corporationId uint64, baseIndex int, resource string, amount int) (int, error) {
 
 	if amount < 0 {
 		return 0, fmt.Errorf("error: amount should be greater than zero")
@@ -186,12 +263,17 @@ func (c *CorpGroup) AddResources(corporationId uint64, resource string, amount i
 	corporation.Rw.Lock()
 	defer corporation.Rw.Unlock()
 
-	// TODO: Select the correct base
-	if _, ok := corporation.Bases[0].StoredResources[resource]; !ok {
-		corporation.Bases[0].StoredResources[resource] = 0
+	if baseIndex < 0 || baseIndex >= len(corporation.Bases) {
+		return 0, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := corporation.Bases[baseIndex]
+
+	if _, ok := base.StoredResources[resource]; !ok {
+		base.StoredResources[resource] = 0
 	}
 
-	corporation.Bases[0].StoredResources[resource] += amount
+	base.StoredResources[resource] += amount
 
-	return corporation.Bases[0].StoredResources[resource], nil
+	return base.StoredResources[resource], nil
 }
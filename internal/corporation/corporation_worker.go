@@ -2,6 +2,7 @@ package corporation
 
 import (
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/world"
 )
 
 func (cg *CorpGroup) Listen() {
@@ -42,22 +43,26 @@ func (cg *CorpGroup) worker(ch <-chan gamecomm.CorpCommand) {
 
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: corp}
 		case gamecomm.AddResourcesToBase:
-			amount, err := cg.AddResources(command.CorporationId, command.Resource, command.Amount)
+			amount, err := cg.AddResources(command.CorporationId, command.BaseIndex, command.Resource, command.Amount)
 			if err != nil {
 				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
 				close(command.ResponseChannel)
 				continue
 			}
 
+			cg.recordIn(command, gamecomm.NewBaseAccount(command.CorporationId, command.BaseIndex), command.Resource, float64(command.Amount))
+
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: amount}
 		case gamecomm.RemoveResourcesFromBase:
-			amount, err := cg.RemoveResources(command.CorporationId, command.Resource, command.Amount)
+			amount, err := cg.RemoveResources(command.CorporationId, command.BaseIndex, command.Resource, command.Amount)
 			if err != nil {
 				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
 				close(command.ResponseChannel)
 				continue
 			}
 
+			cg.recordOut(command, gamecomm.NewBaseAccount(command.CorporationId, command.BaseIndex), command.Resource, float64(command.Amount))
+
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: amount}
 		case gamecomm.AddResourcesToSquad:
 			corp, err := cg.findCorporationReference(command.CorporationId)
@@ -74,6 +79,8 @@ func (cg *CorpGroup) worker(ch <-chan gamecomm.CorpCommand) {
 				continue
 			}
 
+			cg.recordIn(command, gamecomm.NewSquadAccount(command.CorporationId, command.SquadIndex), command.Resource, float64(command.Amount))
+
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: amount}
 		case gamecomm.RemoveResourcesFromSquad:
 			corp, err := cg.findCorporationReference(command.CorporationId)
@@ -90,6 +97,8 @@ func (cg *CorpGroup) worker(ch <-chan gamecomm.CorpCommand) {
 				continue
 			}
 
+			cg.recordOut(command, gamecomm.NewSquadAccount(command.CorporationId, command.SquadIndex), command.Resource, float64(command.Amount))
+
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: amount}
 		case gamecomm.RemoveAllResourcesFromSquad:
 			corp, err := cg.findCorporationReference(command.CorporationId)
@@ -106,6 +115,8 @@ func (cg *CorpGroup) worker(ch <-chan gamecomm.CorpCommand) {
 				continue
 			}
 
+			cg.recordOut(command, gamecomm.NewSquadAccount(command.CorporationId, command.SquadIndex), command.Resource, float64(amount))
+
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: amount}
 		case gamecomm.AddCredits:
 			credits, err := cg.AddCredits(command.CorporationId, command.AmountDecimal)
@@ -115,6 +126,8 @@ func (cg *CorpGroup) worker(ch <-chan gamecomm.CorpCommand) {
 				continue
 			}
 
+			cg.recordIn(command, gamecomm.NewCorporationAccount(command.CorporationId), gamecomm.CreditsAsset, command.AmountDecimal)
+
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: credits}
 		case gamecomm.RemoveCredits:
 			credits, err := cg.RemoveCredits(command.CorporationId, command.AmountDecimal)
@@ -124,7 +137,298 @@ func (cg *CorpGroup) worker(ch <-chan gamecomm.CorpCommand) {
 				continue
 			}
 
+			cg.recordOut(command, gamecomm.NewCorporationAccount(command.CorporationId), gamecomm.CreditsAsset, command.AmountDecimal)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: credits}
+		case gamecomm.ChargeCredits:
+			credits, err := cg.ChargeCredits(command.CorporationId, command.AmountDecimal)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordOut(command, gamecomm.NewCorporationAccount(command.CorporationId), gamecomm.CreditsAsset, command.AmountDecimal)
+
 			command.ResponseChannel <- gamecomm.ChanResponse{Val: credits}
+		case gamecomm.TrainSquadCrew:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			records, err := corp.TrainSquadCrew(command.SquadIndex, command.MissionId, command.Skill, command.Amount, command.DangerLevel)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			// The squad is back at the base, the officers lost on the mission don't help with the repairs
+			repairs, err := corp.RepairSquadShip(command.SquadIndex, command.DangerLevel)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.ledger.Transfer("ship repairs", gamecomm.NewCorporationAccount(command.CorporationId), repairsAccount, gamecomm.CreditsAsset, repairs)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: records}
+		case gamecomm.GetCrewMember:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			crewMember, err := corp.GetCrewMember(command.CrewId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: crewMember}
+		case gamecomm.AssignOfficer:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			squad, err := corp.AssignOfficer(command.SquadIndex, command.CrewId, command.OfficerRole)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: squad}
+		case gamecomm.RemoveOfficer:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			squad, err := corp.RemoveOfficer(command.SquadIndex, command.OfficerRole)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: squad}
+		case gamecomm.GetBaseConstructionCost:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: corp.GetBaseConstructionCost()}
+		case gamecomm.StartBaseConstruction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cost, err := corp.StartBaseConstruction()
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordCost("base construction", command.CorporationId, 0, cost, gamecomm.BaseConstructionEscrow(command.CorporationId))
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: cost}
+		case gamecomm.FoundBase:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			base, err := corp.FoundBase(command.Name, world.Coordinates{X: command.Location.X, Y: command.Location.Y})
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: base}
+		case gamecomm.StartFacilityConstruction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cost, err := corp.StartFacilityConstruction(command.BaseIndex, FacilityType(command.Facility))
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordCost("facility construction", command.CorporationId, command.BaseIndex, cost, constructionAccount)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: cost}
+		case gamecomm.CompleteFacilityConstruction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			base, err := corp.CompleteFacilityConstruction(command.BaseIndex, FacilityType(command.Facility))
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: base}
+		case gamecomm.StartProduction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			recipe, err := cg.findRecipe(command.Recipe)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			job, err := corp.StartProduction(command.BaseIndex, recipe, command.Amount, cg.Recipes)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordProduction("production inputs", command.CorporationId, command.BaseIndex, recipe.Inputs, -float64(command.Amount))
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: job}
+		case gamecomm.CompleteProduction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			recipe, err := cg.findRecipe(command.Recipe)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			produced, err := corp.CompleteProduction(command.BaseIndex, recipe, command.Amount)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordProduction("production outputs", command.CorporationId, command.BaseIndex, produced, 1)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: produced}
+		case gamecomm.CancelBaseConstruction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cost, err := corp.CancelBaseConstruction()
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordRefund("base construction cancelled", command.CorporationId, 0, cost, gamecomm.BaseConstructionEscrow(command.CorporationId))
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: cost}
+		case gamecomm.CancelFacilityConstruction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cost, err := corp.CancelFacilityConstruction(command.BaseIndex, FacilityType(command.Facility))
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordRefund("facility construction cancelled", command.CorporationId, command.BaseIndex, cost, constructionAccount)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: cost}
+		case gamecomm.CancelProduction:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			recipe, err := cg.findRecipe(command.Recipe)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			returned, err := corp.CancelProduction(command.BaseIndex, recipe, command.Amount)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			cg.recordProduction("production cancelled", command.CorporationId, command.BaseIndex, returned, 1)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: returned}
+		case gamecomm.SeizeShip:
+			corp, err := cg.findCorporationReference(command.CorporationId)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			ship, err := corp.SeizeShip(command.SquadIndex)
+			if err != nil {
+				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+				close(command.ResponseChannel)
+				continue
+			}
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: ship}
 
 		default:
 			// TODO: Handle
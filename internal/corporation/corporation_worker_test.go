@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/corporation"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
 )
 
@@ -1183,3 +1185,1316 @@ func TestRemoveCredits(t *testing.T) {
 		assert.Equal(t, finalResourceAmount, expectedFinalAmount)
 	})
 }
+
+func TestTrainSquadCrew(t *testing.T) {
+	type testResult struct {
+		skillLevel  int
+		experience  int
+		records     int
+		shouldError bool
+	}
+
+	tests := []struct {
+		name          string
+		corporationId uint64
+		squadIndex    int
+		experience    int
+		wants         testResult
+	}{
+		{
+			name:          "Experience Without Level Up",
+			corporationId: corporationID,
+			squadIndex:    0,
+			experience:    50,
+			wants: testResult{
+				skillLevel:  0,
+				experience:  50,
+				records:     1,
+				shouldError: false,
+			},
+		},
+		{
+			name:          "Experience With Level Up",
+			corporationId: corporationID,
+			squadIndex:    0,
+			experience:    350,
+			wants: testResult{
+				skillLevel:  2,
+				experience:  50,
+				records:     1,
+				shouldError: false,
+			},
+		},
+		{
+			name:          "Negative Experience",
+			corporationId: corporationID,
+			squadIndex:    0,
+			experience:    -50,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Zero Experience",
+			corporationId: corporationID,
+			squadIndex:    0,
+			experience:    0,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Squad Index",
+			corporationId: corporationID,
+			squadIndex:    999,
+			experience:    50,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Corporation Id",
+			corporationId: 999,
+			squadIndex:    0,
+			experience:    50,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+			cg.Listen()
+
+			resChan := make(chan gamecomm.ChanResponse)
+			command := gamecomm.CorpCommand{
+				CorporationId:   tt.corporationId,
+				ResponseChannel: resChan,
+				Action:          gamecomm.TrainSquadCrew,
+				SquadIndex:      tt.squadIndex,
+				Skill:           gamecomm.HarvestingSkill,
+				Amount:          tt.experience,
+				MissionId:       "Mission-1",
+			}
+
+			gameChannels.CorpChannel <- command
+
+			res := <-resChan
+			if tt.wants.shouldError {
+				assert.Error(t, res.Err)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+
+			records, ok := res.Val.([]gamecomm.CrewRecord)
+			if !ok {
+				t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "[]gamecomm.CrewRecord")
+			}
+
+			assert.Equal(t, len(records), tt.wants.records)
+
+			cm := cg.Corporations[corporationID].CrewMembers[0]
+			assert.Equal(t, cm.Skills[gamecomm.HarvestingSkill], tt.wants.skillLevel)
+			assert.Equal(t, cm.Experience[gamecomm.HarvestingSkill], tt.wants.experience)
+			assert.Equal(t, cm.Status, gamecomm.CrewActive)
+			assert.Equal(t, len(cm.History), 1)
+			assert.Equal(t, cm.History[0].MissionId, "Mission-1")
+		})
+	}
+
+	t.Run("Injured Crew Recovers", func(t *testing.T) {
+		gameChannels := &gamecomm.GameChannels{
+			CorpChannel: make(chan gamecomm.CorpCommand, 10),
+		}
+
+		cg := createTestCorpGroup(t, gameChannels)
+		cg.Listen()
+
+		cm := cg.Corporations[corporationID].CrewMembers[0]
+		cm.Status = gamecomm.CrewInjured
+
+		resChan := make(chan gamecomm.ChanResponse)
+		gameChannels.CorpChannel <- gamecomm.CorpCommand{
+			CorporationId:   corporationID,
+			ResponseChannel: resChan,
+			Action:          gamecomm.TrainSquadCrew,
+			Skill:           gamecomm.HarvestingSkill,
+			Amount:          50,
+			DangerLevel:     100,
+		}
+
+		res := <-resChan
+		assert.NilError(t, res.Err)
+
+		assert.Equal(t, cm.Status, gamecomm.CrewActive)
+		assert.Equal(t, cm.Experience[gamecomm.HarvestingSkill], 0)
+	})
+}
+
+func TestGetCrewMember(t *testing.T) {
+	type testResult struct {
+		response    string
+		shouldError bool
+	}
+
+	tests := []struct {
+		name          string
+		corporationId uint64
+		crewId        uint64
+		wants         testResult
+	}{
+		{
+			name:          "Valid Crew Id",
+			corporationId: corporationID,
+			crewId:        1,
+			wants: testResult{
+				response:    "Galios Trek",
+				shouldError: false,
+			},
+		},
+		{
+			name:          "Invalid Crew Id",
+			corporationId: corporationID,
+			crewId:        999,
+			wants: testResult{
+				response:    "",
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Corporation Id",
+			corporationId: 999,
+			crewId:        1,
+			wants: testResult{
+				response:    "",
+				shouldError: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+			cg.Listen()
+
+			resChan := make(chan gamecomm.ChanResponse)
+			command := gamecomm.CorpCommand{
+				CorporationId:   tt.corporationId,
+				ResponseChannel: resChan,
+				Action:          gamecomm.GetCrewMember,
+				CrewId:          tt.crewId,
+			}
+
+			gameChannels.CorpChannel <- command
+
+			res := <-resChan
+			if !tt.wants.shouldError {
+				assert.NilError(t, res.Err)
+			} else {
+				assert.Error(t, res.Err)
+			}
+
+			resCrew, ok := res.Val.(gamecomm.CrewMember)
+			if !tt.wants.shouldError && !ok {
+				t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "gamecomm.CrewMember")
+			}
+
+			assert.Equal(t, resCrew.Name, tt.wants.response)
+		})
+	}
+}
+
+func TestAssignOfficer(t *testing.T) {
+	type testResult struct {
+		officers     int
+		harvestYield float64
+		shouldError  bool
+	}
+
+	tests := []struct {
+		name          string
+		corporationId uint64
+		squadIndex    int
+		crewId        uint64
+		role          gamecomm.OfficerRole
+		wants         testResult
+	}{
+		{
+			name:          "Valid Officer",
+			corporationId: corporationID,
+			squadIndex:    0,
+			crewId:        1,
+			role:          gamecomm.Quartermaster,
+			wants: testResult{
+				officers:     1,
+				harvestYield: 0.1,
+				shouldError:  false,
+			},
+		},
+		{
+			name:          "Invalid Role",
+			corporationId: corporationID,
+			squadIndex:    0,
+			crewId:        1,
+			role:          "cook",
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Crew Id",
+			corporationId: corporationID,
+			squadIndex:    0,
+			crewId:        999,
+			role:          gamecomm.Captain,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Squad Index",
+			corporationId: corporationID,
+			squadIndex:    999,
+			crewId:        1,
+			role:          gamecomm.Captain,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Crew Member Not On Squad",
+			corporationId: corporationID,
+			squadIndex:    0,
+			crewId:        2,
+			role:          gamecomm.Captain,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+			cg.Corporations[corporationID].CrewMembers = append(cg.Corporations[corporationID].CrewMembers, &corporation.CrewMember{ID: 2, Name: "Deckhand"})
+			cg.Listen()
+
+			resChan := make(chan gamecomm.ChanResponse)
+			command := gamecomm.CorpCommand{
+				CorporationId:   tt.corporationId,
+				ResponseChannel: resChan,
+				Action:          gamecomm.AssignOfficer,
+				SquadIndex:      tt.squadIndex,
+				CrewId:          tt.crewId,
+				OfficerRole:     tt.role,
+			}
+
+			gameChannels.CorpChannel <- command
+
+			res := <-resChan
+			if tt.wants.shouldError {
+				assert.Error(t, res.Err)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+
+			squad, ok := res.Val.(gamecomm.Squad)
+			if !ok {
+				t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "gamecomm.Squad")
+			}
+
+			assert.Equal(t, len(squad.Officers), tt.wants.officers)
+			assert.Equal(t, squad.Bonuses.HarvestYield, tt.wants.harvestYield)
+		})
+	}
+
+	t.Run("One Officer Per Role", func(t *testing.T) {
+		gameChannels := &gamecomm.GameChannels{
+			CorpChannel: make(chan gamecomm.CorpCommand, 10),
+		}
+
+		cg := createTestCorpGroup(t, gameChannels)
+		cg.Listen()
+
+		corp := cg.Corporations[corporationID]
+		secondOfficer := &corporation.CrewMember{ID: 2, Name: "Second Officer"}
+		corp.CrewMembers = append(corp.CrewMembers, secondOfficer)
+		corp.Squads[0].CrewMembers = append(corp.Squads[0].CrewMembers, secondOfficer)
+
+		_, err := corp.AssignOfficer(0, 1, gamecomm.Navigator)
+		assert.NilError(t, err)
+
+		_, err = corp.AssignOfficer(0, 2, gamecomm.Navigator)
+		assert.Error(t, err)
+
+		_, err = corp.AssignOfficer(0, 1, gamecomm.Captain)
+		assert.Error(t, err)
+
+		squad, err := corp.AssignOfficer(0, 2, gamecomm.Captain)
+		assert.NilError(t, err)
+		assert.Equal(t, len(squad.Officers), 2)
+		assert.Equal(t, squad.Bonuses.TravelSpeed, 0.1)
+		assert.Equal(t, squad.Bonuses.Experience, 0.1)
+	})
+}
+
+func TestRemoveOfficer(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand, 10),
+	}
+
+	cg := createTestCorpGroup(t, gameChannels)
+	cg.Listen()
+
+	_, err := cg.Corporations[corporationID].AssignOfficer(0, 1, gamecomm.Engineer)
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name        string
+		role        gamecomm.OfficerRole
+		shouldError bool
+	}{
+		{
+			name:        "Assigned Role",
+			role:        gamecomm.Engineer,
+			shouldError: false,
+		},
+		{
+			name:        "Empty Role",
+			role:        gamecomm.Captain,
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resChan := make(chan gamecomm.ChanResponse)
+			gameChannels.CorpChannel <- gamecomm.CorpCommand{
+				CorporationId:   corporationID,
+				ResponseChannel: resChan,
+				Action:          gamecomm.RemoveOfficer,
+				OfficerRole:     tt.role,
+			}
+
+			res := <-resChan
+			if tt.shouldError {
+				assert.Error(t, res.Err)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+
+			squad := res.Val.(gamecomm.Squad)
+			assert.Equal(t, len(squad.Officers), 0)
+			assert.Equal(t, squad.Bonuses.RepairCost, 0.0)
+		})
+	}
+}
+
+func TestRepairSquadShip(t *testing.T) {
+	type testResult struct {
+		cost        float64
+		shouldError bool
+	}
+
+	tests := []struct {
+		name          string
+		squadIndex    int
+		dangerLevel   int
+		engineer      bool
+		engineerSkill int
+		wants         testResult
+	}{
+		{
+			name:        "No Engineer",
+			squadIndex:  0,
+			dangerLevel: 50,
+			wants:       testResult{cost: 500},
+		},
+		{
+			name:        "Engineer",
+			squadIndex:  0,
+			dangerLevel: 50,
+			engineer:    true,
+			wants:       testResult{cost: 450},
+		},
+		{
+			name:          "Skilled Engineer",
+			squadIndex:    0,
+			dangerLevel:   50,
+			engineer:      true,
+			engineerSkill: 10,
+			wants:         testResult{cost: 400},
+		},
+		{
+			name:        "Safe Mission",
+			squadIndex:  0,
+			dangerLevel: 0,
+			wants:       testResult{cost: 0},
+		},
+		{
+			name:        "Wrecked Ship",
+			squadIndex:  0,
+			dangerLevel: 500,
+			wants:       testResult{cost: 2_000},
+		},
+		{
+			name:        "Invalid Squad Index",
+			squadIndex:  999,
+			dangerLevel: 50,
+			wants:       testResult{shouldError: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corp := createTestCorporation(t)
+
+			if tt.engineer {
+				corp.CrewMembers[0].Skills = map[string]int{gamecomm.PilotingSkill: tt.engineerSkill}
+
+				_, err := corp.AssignOfficer(0, 1, gamecomm.Engineer)
+				assert.NilError(t, err)
+			}
+
+			cost, err := corp.RepairSquadShip(tt.squadIndex, tt.dangerLevel)
+			if tt.wants.shouldError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, cost, tt.wants.cost)
+			assert.Equal(t, corp.Credits, initialCorporationCredits-tt.wants.cost)
+			assert.Equal(t, corp.Squads[0].Ships.ActualHealth, corp.Squads[0].Ships.MaxHealth)
+		})
+	}
+}
+
+func TestRunBaseProduction(t *testing.T) {
+	type testResult struct {
+		storedResources map[string]int
+		credits         float64
+	}
+
+	tests := []struct {
+		name            string
+		facilities      []corporation.Facility
+		storedResources map[string]int
+		storageCapacity float64
+		credits         float64
+		wants           testResult
+	}{
+		{
+			name:            "Facilities produce with upkeep paid",
+			facilities:      []corporation.Facility{{Type: corporation.WaterExtractor, Level: 1}},
+			storedResources: map[string]int{"energy": 1000},
+			storageCapacity: 50_000,
+			credits:         initialCorporationCredits,
+			wants: testResult{
+				storedResources: map[string]int{"energy": 900, "water": 400},
+				credits:         initialCorporationCredits - 10,
+			},
+		},
+		{
+			name:            "Facility level multiplies production and upkeep",
+			facilities:      []corporation.Facility{{Type: corporation.WaterExtractor, Level: 2}},
+			storedResources: map[string]int{"energy": 1000},
+			storageCapacity: 50_000,
+			credits:         initialCorporationCredits,
+			wants: testResult{
+				storedResources: map[string]int{"energy": 800, "water": 800},
+				credits:         initialCorporationCredits - 20,
+			},
+		},
+		{
+			name:            "Production capped by storage capacity",
+			facilities:      []corporation.Facility{{Type: corporation.WaterExtractor, Level: 1}},
+			storedResources: map[string]int{"energy": 1000},
+			storageCapacity: 1000,
+			credits:         initialCorporationCredits,
+			wants: testResult{
+				storedResources: map[string]int{"energy": 900, "water": 100},
+				credits:         initialCorporationCredits - 10,
+			},
+		},
+		{
+			name:            "Missing upkeep resources lower production",
+			facilities:      []corporation.Facility{{Type: corporation.WaterExtractor, Level: 1}},
+			storedResources: map[string]int{"energy": 50},
+			storageCapacity: 50_000,
+			credits:         initialCorporationCredits,
+			wants: testResult{
+				storedResources: map[string]int{"energy": 0, "water": 200},
+				credits:         initialCorporationCredits - 5,
+			},
+		},
+		{
+			name:            "Missing credits lower production",
+			facilities:      []corporation.Facility{{Type: corporation.WaterExtractor, Level: 1}},
+			storedResources: map[string]int{"energy": 1000},
+			storageCapacity: 50_000,
+			credits:         5,
+			wants: testResult{
+				storedResources: map[string]int{"energy": 950, "water": 200},
+				credits:         0,
+			},
+		},
+		{
+			name:            "No upkeep no production",
+			facilities:      []corporation.Facility{{Type: corporation.WaterExtractor, Level: 1}},
+			storedResources: map[string]int{},
+			storageCapacity: 50_000,
+			credits:         initialCorporationCredits,
+			wants: testResult{
+				storedResources: map[string]int{},
+				credits:         initialCorporationCredits,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+
+			corp := cg.Corporations[corporationID]
+			corp.Credits = tt.credits
+
+			base := corp.Bases[0]
+			base.Facilities = tt.facilities
+			base.StoredResources = tt.storedResources
+			base.StorageCapacity = tt.storageCapacity
+			base.UpdateProduction()
+
+			cg.RunBaseProduction()
+
+			assert.Equal(t, corp.Credits, tt.wants.credits)
+
+			if !reflect.DeepEqual(base.StoredResources, tt.wants.storedResources) {
+				t.Errorf("got: %v; want: %v", base.StoredResources, tt.wants.storedResources)
+			}
+		})
+	}
+}
+
+func TestStartFacilityConstruction(t *testing.T) {
+	type testResult struct {
+		credits     float64
+		iron        int
+		buildTime   gameclock.GameTimeDuration
+		shouldError bool
+	}
+
+	tests := []struct {
+		name          string
+		corporationId uint64
+		baseIndex     int
+		facility      string
+		wants         testResult
+	}{
+		{
+			name:          "Valid Facility",
+			corporationId: corporationID,
+			baseIndex:     0,
+			facility:      string(corporation.WaterExtractor),
+			wants: testResult{
+				credits:     initialCorporationCredits - 1_000,
+				iron:        initialIronQuantity - 300,
+				buildTime:   2 * gameclock.Day,
+				shouldError: false,
+			},
+		},
+		{
+			name:          "Not Enough Materials",
+			corporationId: corporationID,
+			baseIndex:     0,
+			facility:      string(corporation.Shipyard),
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Facility",
+			corporationId: corporationID,
+			baseIndex:     0,
+			facility:      "casino",
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Base Index",
+			corporationId: corporationID,
+			baseIndex:     999,
+			facility:      string(corporation.WaterExtractor),
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:          "Invalid Corporation Id",
+			corporationId: 999,
+			baseIndex:     0,
+			facility:      string(corporation.WaterExtractor),
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+			cg.Listen()
+
+			resChan := make(chan gamecomm.ChanResponse)
+			command := gamecomm.CorpCommand{
+				CorporationId:   tt.corporationId,
+				ResponseChannel: resChan,
+				Action:          gamecomm.StartFacilityConstruction,
+				BaseIndex:       tt.baseIndex,
+				Facility:        tt.facility,
+			}
+
+			gameChannels.CorpChannel <- command
+
+			res := <-resChan
+
+			corp := cg.Corporations[corporationID]
+
+			if tt.wants.shouldError {
+				assert.Error(t, res.Err)
+				assert.Equal(t, corp.Credits, float64(initialCorporationCredits))
+				assert.Equal(t, corp.Bases[0].StoredResources["iron"], initialIronQuantity)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+
+			cost, ok := res.Val.(gamecomm.ConstructionCost)
+			if !ok {
+				t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "gamecomm.ConstructionCost")
+			}
+
+			assert.Equal(t, cost.BuildTime, tt.wants.buildTime)
+			assert.Equal(t, corp.Credits, tt.wants.credits)
+			assert.Equal(t, corp.Bases[0].StoredResources["iron"], tt.wants.iron)
+			assert.Equal(t, len(corp.Bases[0].Constructions), 1)
+		})
+	}
+
+	t.Run("One Construction Per Facility", func(t *testing.T) {
+		gameChannels := &gamecomm.GameChannels{
+			CorpChannel: make(chan gamecomm.CorpCommand, 10),
+		}
+
+		cg := createTestCorpGroup(t, gameChannels)
+		corp := cg.Corporations[corporationID]
+
+		_, err := corp.StartFacilityConstruction(0, corporation.WaterExtractor)
+		assert.NilError(t, err)
+
+		_, err = corp.StartFacilityConstruction(0, corporation.WaterExtractor)
+		assert.Error(t, err)
+	})
+}
+
+func TestCompleteFacilityConstruction(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand, 10),
+	}
+
+	cg := createTestCorpGroup(t, gameChannels)
+	cg.Listen()
+
+	corp := cg.Corporations[corporationID]
+
+	complete := func(facility corporation.FacilityType) gamecomm.ChanResponse {
+		resChan := make(chan gamecomm.ChanResponse)
+		gameChannels.CorpChannel <- gamecomm.CorpCommand{
+			CorporationId:   corporationID,
+			ResponseChannel: resChan,
+			Action:          gamecomm.CompleteFacilityConstruction,
+			BaseIndex:       0,
+			Facility:        string(facility),
+		}
+
+		return <-resChan
+	}
+
+	// Not under construction
+	res := complete(corporation.Storage)
+	assert.Error(t, res.Err)
+
+	_, err := corp.StartFacilityConstruction(0, corporation.Storage)
+	assert.NilError(t, err)
+
+	res = complete(corporation.Storage)
+	assert.NilError(t, res.Err)
+
+	base, ok := res.Val.(gamecomm.Base)
+	if !ok {
+		t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "gamecomm.Base")
+	}
+
+	assert.Equal(t, len(base.Facilities), 1)
+	assert.Equal(t, base.Facilities[0].Level, 1)
+	assert.Equal(t, len(base.Constructions), 0)
+	assert.Equal(t, base.StorageCapacity, float64(100_000))
+	assert.Equal(t, base.ResourceUpkeep["energy"], 20)
+
+	// Building it again upgrades the facility, the next level costs twice as much
+	corp.Bases[0].StoredResources["iron"] = 2_000
+
+	_, err = corp.StartFacilityConstruction(0, corporation.Storage)
+	assert.NilError(t, err)
+
+	res = complete(corporation.Storage)
+	assert.NilError(t, res.Err)
+
+	base = res.Val.(gamecomm.Base)
+	assert.Equal(t, len(base.Facilities), 1)
+	assert.Equal(t, base.Facilities[0].Level, 2)
+	assert.Equal(t, base.StorageCapacity, float64(150_000))
+	assert.Equal(t, base.ResourceUpkeep["energy"], 40)
+}
+
+func TestCancelConstruction(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand, 10),
+	}
+
+	cg := createTestCorpGroup(t, gameChannels)
+	cg.Listen()
+
+	corp := cg.Corporations[corporationID]
+
+	cancel := func(action gamecomm.CommandType, facility corporation.FacilityType) gamecomm.ChanResponse {
+		resChan := make(chan gamecomm.ChanResponse)
+		gameChannels.CorpChannel <- gamecomm.CorpCommand{
+			CorporationId:   corporationID,
+			ResponseChannel: resChan,
+			Action:          action,
+			BaseIndex:       0,
+			Facility:        string(facility),
+		}
+
+		return <-resChan
+	}
+
+	// Not under construction
+	res := cancel(gamecomm.CancelFacilityConstruction, corporation.WaterExtractor)
+	assert.Error(t, res.Err)
+
+	_, err := corp.StartFacilityConstruction(0, corporation.WaterExtractor)
+	assert.NilError(t, err)
+
+	res = cancel(gamecomm.CancelFacilityConstruction, corporation.WaterExtractor)
+	assert.NilError(t, res.Err)
+	assert.Equal(t, corp.Credits, float64(initialCorporationCredits))
+	assert.Equal(t, corp.Bases[0].StoredResources["iron"], initialIronQuantity)
+	assert.Equal(t, len(corp.Bases[0].Constructions), 0)
+
+	// No base under construction
+	res = cancel(gamecomm.CancelBaseConstruction, "")
+	assert.Error(t, res.Err)
+
+	corp.Bases[0].StoredResources = map[string]int{"iron": 3_000, "water": 1_000, "food": 1_000}
+
+	_, err = corp.StartBaseConstruction()
+	assert.NilError(t, err)
+
+	res = cancel(gamecomm.CancelBaseConstruction, "")
+	assert.NilError(t, res.Err)
+	assert.Equal(t, corp.Credits, float64(initialCorporationCredits))
+	assert.Equal(t, corp.Bases[0].StoredResources["iron"], 3_000)
+	assert.Equal(t, corp.BaseConstructions, 0)
+}
+
+func TestFoundBase(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand, 10),
+	}
+
+	cg := createTestCorpGroup(t, gameChannels)
+	cg.Listen()
+
+	corp := cg.Corporations[corporationID]
+	corp.Bases[0].StoredResources["water"] = 1_000
+	corp.Bases[0].StoredResources["food"] = 1_000
+
+	// Not enough iron for the base
+	_, err := corp.StartBaseConstruction()
+	assert.Error(t, err)
+	assert.Equal(t, corp.Credits, float64(initialCorporationCredits))
+
+	corp.Bases[0].StoredResources["iron"] = 3_000
+
+	cost, err := corp.StartBaseConstruction()
+	assert.NilError(t, err)
+	assert.Equal(t, corp.Credits, initialCorporationCredits-cost.Credits)
+	assert.Equal(t, corp.Bases[0].StoredResources["iron"], 0)
+	assert.Equal(t, corp.Bases[0].StoredResources["water"], 0)
+
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		CorporationId:   corporationID,
+		ResponseChannel: resChan,
+		Action:          gamecomm.FoundBase,
+		Name:            "Outpost",
+		Location:        gamecomm.Coordinates{X: 10, Y: 5},
+	}
+
+	res := <-resChan
+	assert.NilError(t, res.Err)
+
+	base, ok := res.Val.(gamecomm.Base)
+	if !ok {
+		t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "gamecomm.Base")
+	}
+
+	assert.Equal(t, base.ID, uint64(2))
+	assert.Equal(t, base.Name, "Outpost")
+	assert.Equal(t, base.Location, gamecomm.Coordinates{X: 10, Y: 5})
+	assert.Equal(t, len(corp.Bases), 2)
+}
+
+func TestStartProduction(t *testing.T) {
+	type testResult struct {
+		iron        int
+		duration    gameclock.GameTimeDuration
+		shouldError bool
+	}
+
+	tests := []struct {
+		name       string
+		recipe     string
+		batches    int
+		facilities []corporation.Facility
+		jobs       []corporation.ProductionJob
+		wants      testResult
+	}{
+		{
+			name:       "Valid Recipe",
+			recipe:     "alloys",
+			batches:    2,
+			facilities: []corporation.Facility{{Type: corporation.Refinery, Level: 1}},
+			wants: testResult{
+				iron:        initialIronQuantity - 400,
+				duration:    48,
+				shouldError: false,
+			},
+		},
+		{
+			name:    "Missing Facility",
+			recipe:  "alloys",
+			batches: 1,
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:       "Facility Busy",
+			recipe:     "alloys",
+			batches:    1,
+			facilities: []corporation.Facility{{Type: corporation.Refinery, Level: 1}},
+			jobs:       []corporation.ProductionJob{{Recipe: "electronics", Batches: 1}},
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:       "Not Enough Inputs",
+			recipe:     "alloys",
+			batches:    10,
+			facilities: []corporation.Facility{{Type: corporation.Refinery, Level: 1}},
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:       "Not Enough Storage",
+			recipe:     "alloys",
+			batches:    2,
+			facilities: []corporation.Facility{{Type: corporation.Refinery, Level: 2}},
+			jobs:       []corporation.ProductionJob{{Recipe: "electronics", Batches: 1, Reserved: 49_500}},
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:       "Invalid Batches",
+			recipe:     "alloys",
+			batches:    0,
+			facilities: []corporation.Facility{{Type: corporation.Refinery, Level: 1}},
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+		{
+			name:       "Invalid Recipe",
+			recipe:     "cake",
+			batches:    1,
+			facilities: []corporation.Facility{{Type: corporation.Refinery, Level: 1}},
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+			cg.Listen()
+
+			base := cg.Corporations[corporationID].Bases[0]
+			base.Facilities = tt.facilities
+			base.Jobs = tt.jobs
+			base.StoredResources["gold"] = 100
+			base.StoredResources["energy"] = 500
+
+			resChan := make(chan gamecomm.ChanResponse)
+			command := gamecomm.CorpCommand{
+				CorporationId:   corporationID,
+				ResponseChannel: resChan,
+				Action:          gamecomm.StartProduction,
+				BaseIndex:       0,
+				Recipe:          tt.recipe,
+				Amount:          tt.batches,
+			}
+
+			gameChannels.CorpChannel <- command
+
+			res := <-resChan
+			if tt.wants.shouldError {
+				assert.Error(t, res.Err)
+				assert.Equal(t, base.StoredResources["iron"], initialIronQuantity)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+
+			job, ok := res.Val.(gamecomm.ProductionJob)
+			if !ok {
+				t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "gamecomm.ProductionJob")
+			}
+
+			assert.Equal(t, job.Duration, tt.wants.duration)
+			assert.Equal(t, base.StoredResources["iron"], tt.wants.iron)
+			assert.Equal(t, len(base.Jobs), len(tt.jobs)+1)
+		})
+	}
+}
+
+func TestCompleteProduction(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand, 10),
+	}
+
+	cg := createTestCorpGroup(t, gameChannels)
+	cg.Listen()
+
+	base := cg.Corporations[corporationID].Bases[0]
+	base.Jobs = []corporation.ProductionJob{{Recipe: "alloys", Batches: 2, Reserved: 200}}
+	base.StorageCapacity = initialIronQuantity + 200
+
+	complete := func(batches int) gamecomm.ChanResponse {
+		resChan := make(chan gamecomm.ChanResponse)
+		gameChannels.CorpChannel <- gamecomm.CorpCommand{
+			CorporationId:   corporationID,
+			ResponseChannel: resChan,
+			Action:          gamecomm.CompleteProduction,
+			BaseIndex:       0,
+			Recipe:          "alloys",
+			Amount:          batches,
+		}
+
+		return <-resChan
+	}
+
+	// No job with that many batches
+	res := complete(1)
+	assert.Error(t, res.Err)
+
+	// The outputs go to the storage reserved when the job started, even if the base filled up meanwhile
+	base.StoredResources["iron"] += 500
+
+	res = complete(2)
+	assert.NilError(t, res.Err)
+
+	produced, ok := res.Val.(map[string]int)
+	if !ok {
+		t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "map[string]int")
+	}
+
+	assert.Equal(t, produced["alloys"], 200)
+	assert.Equal(t, base.StoredResources["alloys"], 200)
+	assert.Equal(t, len(base.Jobs), 0)
+}
+
+func TestCancelProduction(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand, 10),
+	}
+
+	cg := createTestCorpGroup(t, gameChannels)
+	cg.Listen()
+
+	base := cg.Corporations[corporationID].Bases[0]
+	base.Facilities = []corporation.Facility{{Type: corporation.Refinery, Level: 1}}
+	base.StoredResources["gold"] = 100
+	base.StoredResources["energy"] = 500
+
+	_, err := cg.Corporations[corporationID].StartProduction(0, cg.Recipes["alloys"], 2, cg.Recipes)
+	assert.NilError(t, err)
+
+	cancel := func(batches int) gamecomm.ChanResponse {
+		resChan := make(chan gamecomm.ChanResponse)
+		gameChannels.CorpChannel <- gamecomm.CorpCommand{
+			CorporationId:   corporationID,
+			ResponseChannel: resChan,
+			Action:          gamecomm.CancelProduction,
+			BaseIndex:       0,
+			Recipe:          "alloys",
+			Amount:          batches,
+		}
+
+		return <-resChan
+	}
+
+	// No job with that many batches
+	res := cancel(1)
+	assert.Error(t, res.Err)
+
+	res = cancel(2)
+	assert.NilError(t, res.Err)
+	assert.Equal(t, base.StoredResources["iron"], initialIronQuantity)
+	assert.Equal(t, base.StoredResources["gold"], 100)
+	assert.Equal(t, base.StoredResources["energy"], 500)
+	assert.Equal(t, len(base.Jobs), 0)
+}
+
+func TestSeizeShip(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand, 10),
+	}
+
+	cg := createTestCorpGroup(t, gameChannels)
+	cg.Listen()
+
+	tests := []struct {
+		name        string
+		squadIndex  int
+		shouldError bool
+	}{
+		{
+			name:        "Squad With Ship",
+			squadIndex:  0,
+			shouldError: false,
+		},
+		{
+			name:        "Ship Already Seized",
+			squadIndex:  0,
+			shouldError: true,
+		},
+		{
+			name:        "Squad Not Found",
+			squadIndex:  5,
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resChan := make(chan gamecomm.ChanResponse)
+			gameChannels.CorpChannel <- gamecomm.CorpCommand{
+				CorporationId:   corporationID,
+				ResponseChannel: resChan,
+				Action:          gamecomm.SeizeShip,
+				SquadIndex:      tt.squadIndex,
+			}
+
+			res := <-resChan
+			if tt.shouldError {
+				assert.Error(t, res.Err)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+
+			ship := res.Val.(gamecomm.Ship)
+			assert.Equal(t, ship.Name, "MF")
+
+			squad, err := cg.Corporations[corporationID].GetSquad(tt.squadIndex)
+			assert.NilError(t, err)
+			assert.Equal(t, squad.Ships, gamecomm.Ship{})
+		})
+	}
+}
+
+func TestChargeCredits(t *testing.T) {
+	tests := []struct {
+		name          string
+		corporationId uint64
+		amount        float64
+		wants         float64
+		shouldError   bool
+	}{
+		{
+			name:          "Charge More Credits Than Available",
+			corporationId: corporationID,
+			amount:        initialCorporationCredits + 50,
+			wants:         -50,
+			shouldError:   false,
+		},
+		{
+			name:          "Negative Amount",
+			corporationId: corporationID,
+			amount:        -50,
+			shouldError:   true,
+		},
+		{
+			name:          "Invalid Corporation Id",
+			corporationId: 999,
+			amount:        50,
+			shouldError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+			cg.Listen()
+
+			resChan := make(chan gamecomm.ChanResponse)
+			gameChannels.CorpChannel <- gamecomm.CorpCommand{
+				CorporationId:   tt.corporationId,
+				ResponseChannel: resChan,
+				Action:          gamecomm.ChargeCredits,
+				AmountDecimal:   tt.amount,
+			}
+
+			res := <-resChan
+			if tt.shouldError {
+				assert.Error(t, res.Err)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+			assert.Equal(t, res.Val.(float64), tt.wants)
+		})
+	}
+}
+
+func TestCheckSolvency(t *testing.T) {
+	const npcCorporationID = 2
+
+	tests := []struct {
+		name          string
+		corporationId uint64
+		wants         []gamecomm.CorporationEventType
+		removed       bool
+	}{
+		{
+			name:          "Player Is Restructured",
+			corporationId: corporationID,
+			wants: []gamecomm.CorporationEventType{
+				gamecomm.CorporationInsolvent,
+				gamecomm.CorporationLiquidated,
+				gamecomm.CorporationRestructured,
+			},
+			removed: false,
+		},
+		{
+			name:          "NPC Goes Bankrupt",
+			corporationId: npcCorporationID,
+			wants: []gamecomm.CorporationEventType{
+				gamecomm.CorporationInsolvent,
+				gamecomm.CorporationLiquidated,
+				gamecomm.CorporationBankrupt,
+			},
+			removed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			cg := createTestCorpGroup(t, gameChannels)
+			cg.Corporations[npcCorporationID] = &corporation.Corporation{
+				ID:      npcCorporationID,
+				Name:    "NPC Corporation",
+				Credits: initialCorporationCredits,
+			}
+
+			events := make(chan gamecomm.CorporationEvent, 10)
+			cg.SubscribeEvents(events)
+
+			_, err := cg.ChargeCredits(tt.corporationId, initialCorporationCredits+100)
+			assert.NilError(t, err)
+
+			cg.CheckSolvency(0)
+			cg.CheckSolvency(gameclock.Day)
+			cg.CheckSolvency(7 * gameclock.Day)
+
+			for _, want := range tt.wants {
+				event := <-events
+				assert.Equal(t, event.CorporationId, tt.corporationId)
+				assert.Equal(t, event.Type, want)
+			}
+
+			assert.Equal(t, len(events), 0)
+
+			corp, err := cg.FindCorporation(tt.corporationId)
+			if tt.removed {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, corp.Credits, 0.0)
+			assert.Equal(t, corp.Status, gamecomm.Solvent)
+			assert.Equal(t, corp.Reputation, -50)
+		})
+	}
+}
@@ -1,21 +1,173 @@
 package corporation
 
-import "github.com/luisya22/galactic-exchange/internal/gamecomm"
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/maputils"
+)
+
+const (
+	maxSkillLevel         = 10
+	experiencePerLevel    = 100
+	baseInjuryProbability = 0.5
+	deathProbability      = 0.1
+	riskReductionPerLevel = 0.05
+	pilotingExperience    = 10
+)
 
 type CrewMember struct {
 	ID         uint64
 	Name       string
 	Species    string
 	Skills     map[string]int
+	Experience map[string]int
+	Status     gamecomm.CrewStatus
 	AssignedTo uint64
+	History    []gamecomm.CrewRecord
 }
 
 func (cm *CrewMember) Copy() gamecomm.CrewMember {
+	history := make([]gamecomm.CrewRecord, len(cm.History))
+	copy(history, cm.History)
+
 	return gamecomm.CrewMember{
 		ID:         cm.ID,
 		Name:       cm.Name,
 		Species:    cm.Species,
-		Skills:     cm.Skills,
+		Skills:     maputils.CopyMap(cm.Skills),
+		Experience: maputils.CopyMap(cm.Experience),
+		Status:     cm.Status,
 		AssignedTo: cm.AssignedTo,
+		History:    history,
+	}
+}
+
+// experienceToNextLevel returns the experience needed to go from level to level + 1
+func experienceToNextLevel(level int) int {
+	return (level + 1) * experiencePerLevel
+}
+
+// addExperience adds experience to the skill and levels it up as many times as the experience allows.
+// Returns true if the skill leveled up.
+func (cm *CrewMember) addExperience(skill string, amount int) bool {
+	if cm.Skills == nil {
+		cm.Skills = make(map[string]int)
+	}
+
+	if cm.Experience == nil {
+		cm.Experience = make(map[string]int)
+	}
+
+	cm.Experience[skill] += amount
+
+	levelUp := false
+	for cm.Skills[skill] < maxSkillLevel && cm.Experience[skill] >= experienceToNextLevel(cm.Skills[skill]) {
+		cm.Experience[skill] -= experienceToNextLevel(cm.Skills[skill])
+		cm.Skills[skill]++
+		levelUp = true
+	}
+
+	return levelUp
+}
+
+// injuryProbability returns the chance of the crew member getting hurt on a mission with the danger level.
+// Combat and piloting skills lower the risk.
+func (cm *CrewMember) injuryProbability(dangerLevel int) float64 {
+	risk := float64(dangerLevel) / 100 * baseInjuryProbability
+
+	reduction := float64(cm.Skills[gamecomm.CombatSkill]+cm.Skills[gamecomm.PilotingSkill]) * riskReductionPerLevel
+	risk *= max(1-reduction, 0)
+
+	return min(risk, 1)
+}
+
+// rollMissionRisk decides if the crew member is injured or killed
+func (cm *CrewMember) rollMissionRisk(dangerLevel int) gamecomm.CrewStatus {
+	risk := cm.injuryProbability(dangerLevel)
+	if risk == 0 {
+		return cm.Status
+	}
+
+	roll := rand.Float64()
+
+	switch {
+	case roll < risk*deathProbability:
+		return gamecomm.CrewDead
+	case roll < risk:
+		return gamecomm.CrewInjured
+	default:
+		return cm.Status
+	}
+}
+
+// TrainSquadCrew gives experience to the squad crew and rolls the mission risk, dead crew leaves the squad
+func (c *Corporation) TrainSquadCrew(squadIndex int, missionId string, skill string, experience int, dangerLevel int) ([]gamecomm.CrewRecord, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if experience <= 0 {
+		return nil, fmt.Errorf("error: experience should be greater than zero")
+	}
+
+	if squadIndex < 0 || squadIndex >= len(c.Squads) {
+		return nil, fmt.Errorf("error: squad not found %v", squadIndex)
 	}
+
+	squad := c.Squads[squadIndex]
+	experience = int(float64(experience) * (1 + squad.Bonuses().Experience))
+
+	records := []gamecomm.CrewRecord{}
+	survivors := []*CrewMember{}
+	for _, cm := range squad.CrewMembers {
+		record := gamecomm.CrewRecord{
+			CrewId:    cm.ID,
+			MissionId: missionId,
+			Skill:     skill,
+		}
+
+		if cm.Status == gamecomm.CrewInjured {
+			cm.Status = gamecomm.CrewActive
+		} else {
+			record.Experience = experience
+			record.LevelUp = cm.addExperience(skill, experience)
+			record.LevelUp = cm.addExperience(gamecomm.PilotingSkill, pilotingExperience) || record.LevelUp
+			record.LevelUp = cm.addExperience(gamecomm.CombatSkill, dangerLevel) || record.LevelUp
+			cm.Status = cm.rollMissionRisk(dangerLevel)
+		}
+
+		record.Status = cm.Status
+		cm.History = append(cm.History, record)
+		records = append(records, record)
+
+		if cm.Status == gamecomm.CrewDead {
+			cm.AssignedTo = 0
+			continue
+		}
+
+		survivors = append(survivors, cm)
+	}
+
+	squad.CrewMembers = survivors
+
+	for role, officer := range squad.Officers {
+		if officer.Status == gamecomm.CrewDead {
+			delete(squad.Officers, role)
+		}
+	}
+
+	return records, nil
+}
+
+func (c *Corporation) GetCrewMember(crewId uint64) (gamecomm.CrewMember, error) {
+	c.Rw.RLock()
+	defer c.Rw.RUnlock()
+
+	crewMember, err := c.findCrewMember(crewId)
+	if err != nil {
+		return gamecomm.CrewMember{}, err
+	}
+
+	return crewMember.Copy(), nil
 }
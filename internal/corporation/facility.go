@@ -0,0 +1,111 @@
+package corporation
+
+import "github.com/luisya22/galactic-exchange/internal/gameclock"
+
+type FacilityType string
+
+const (
+	PowerPlant     FacilityType = "power_plant"
+	Farm           FacilityType = "farm"
+	WaterExtractor FacilityType = "water_extractor"
+	Mine           FacilityType = "mine"
+	Storage        FacilityType = "storage"
+	Refinery       FacilityType = "refinery"
+	Shipyard       FacilityType = "shipyard"
+	Hangar         FacilityType = "hangar"
+)
+
+type Facility struct {
+	Type  FacilityType
+	Level int
+}
+
+// facilityDefinition is the daily production and upkeep of a level 1 facility, each level multiplies them.
+// Storage is the capacity each level adds to the base.
+type facilityDefinition struct {
+	production   map[string]int
+	upkeep       map[string]int
+	creditUpkeep float64
+	storage      float64
+	cost         constructionCost
+}
+
+var facilityDefinitions = map[FacilityType]facilityDefinition{
+	PowerPlant: {
+		production:   map[string]int{"energy": 500},
+		upkeep:       map[string]int{"water": 50},
+		creditUpkeep: 20,
+		cost: constructionCost{
+			credits:   2_000,
+			materials: map[string]int{"iron": 500},
+			buildTime: 3 * gameclock.Day,
+		},
+	},
+	Farm: {
+		production:   map[string]int{"food": 400},
+		upkeep:       map[string]int{"energy": 100, "water": 100},
+		creditUpkeep: 10,
+		cost: constructionCost{
+			credits:   1_000,
+			materials: map[string]int{"iron": 200, "water": 500},
+			buildTime: 2 * gameclock.Day,
+		},
+	},
+	WaterExtractor: {
+		production:   map[string]int{"water": 400},
+		upkeep:       map[string]int{"energy": 100},
+		creditUpkeep: 10,
+		cost: constructionCost{
+			credits:   1_000,
+			materials: map[string]int{"iron": 300},
+			buildTime: 2 * gameclock.Day,
+		},
+	},
+	Mine: {
+		production:   map[string]int{"iron": 200},
+		upkeep:       map[string]int{"energy": 200, "food": 50, "water": 50},
+		creditUpkeep: 30,
+		cost: constructionCost{
+			credits:   3_000,
+			materials: map[string]int{"iron": 800},
+			buildTime: 4 * gameclock.Day,
+		},
+	},
+	Storage: {
+		upkeep:       map[string]int{"energy": 20},
+		creditUpkeep: 5,
+		storage:      50_000,
+		cost: constructionCost{
+			credits:   1_500,
+			materials: map[string]int{"iron": 1_000},
+			buildTime: 2 * gameclock.Day,
+		},
+	},
+	Refinery: {
+		upkeep:       map[string]int{"energy": 300, "water": 100},
+		creditUpkeep: 40,
+		cost: constructionCost{
+			credits:   5_000,
+			materials: map[string]int{"iron": 1_500, "gold": 100},
+			buildTime: 5 * gameclock.Day,
+		},
+	},
+	Shipyard: {
+		upkeep:       map[string]int{"energy": 400},
+		creditUpkeep: 60,
+		cost: constructionCost{
+			credits:   8_000,
+			materials: map[string]int{"iron": 3_000, "gold": 200},
+			buildTime: 7 * gameclock.Day,
+		},
+	},
+	Hangar: {
+		upkeep:       map[string]int{"energy": 100},
+		creditUpkeep: 15,
+		cost: constructionCost{
+			credits:   2_500,
+			materials: map[string]int{"iron": 1_200},
+			buildTime: 3 * gameclock.Day,
+		},
+	},
+}
@@ -0,0 +1,195 @@
+package corporation
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+var (
+	openingAccount       = gamecomm.NewExternalAccount("opening")
+	productionAccount    = gamecomm.NewExternalAccount("production")
+	upkeepAccount        = gamecomm.NewExternalAccount("upkeep")
+	wagesAccount         = gamecomm.NewExternalAccount("wages")
+	constructionAccount  = gamecomm.NewExternalAccount("construction")
+	repairsAccount       = gamecomm.NewExternalAccount("repairs")
+	liquidationAccount   = gamecomm.NewExternalAccount("liquidation")
+	restructuringAccount = gamecomm.NewExternalAccount("restructuring")
+	bankruptcyAccount    = gamecomm.NewExternalAccount("bankruptcy")
+)
+
+var commandMemos = map[gamecomm.CommandType]string{
+	gamecomm.AddResourcesToSquad:         "add resources to squad",
+	gamecomm.RemoveResourcesFromSquad:    "remove resources from squad",
+	gamecomm.RemoveAllResourcesFromSquad: "unload squad",
+	gamecomm.AddResourcesToBase:          "add resources to base",
+	gamecomm.RemoveResourcesFromBase:     "remove resources from base",
+	gamecomm.AddCredits:                  "add credits",
+	gamecomm.RemoveCredits:               "remove credits",
+	gamecomm.ChargeCredits:               "charge credits",
+}
+
+func commandMemo(command gamecomm.CorpCommand) string {
+	if command.Memo != "" {
+		return command.Memo
+	}
+
+	return commandMemos[command.Action]
+}
+
+// recordIn posts the amount moving from the counterparty of the command to the account
+func (cg *CorpGroup) recordIn(command gamecomm.CorpCommand, account gamecomm.Account, asset string, amount float64) {
+	cg.ledger.Transfer(commandMemo(command), command.Counterparty, account, asset, amount)
+}
+
+// recordOut posts the amount moving from the account to the counterparty of the command
+func (cg *CorpGroup) recordOut(command gamecomm.CorpCommand, account gamecomm.Account, asset string, amount float64) {
+	cg.ledger.Transfer(commandMemo(command), account, command.Counterparty, asset, amount)
+}
+
+// post records the entry, the postings come from the corporation code so an unbalanced entry is a bug
+func (cg *CorpGroup) post(memo string, postings []gamecomm.Posting) {
+	err := cg.ledger.Post(memo, postings...)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+// OpenAccounts posts the credits and goods the corporation starts with
+func (cg *CorpGroup) OpenAccounts(corporationId uint64) error {
+	c, err := cg.findCorporationReference(corporationId)
+	if err != nil {
+		return err
+	}
+
+	c.Rw.RLock()
+	postings := settle(c.holdings(), openingAccount, 1)
+	c.Rw.RUnlock()
+
+	return cg.ledger.Post(fmt.Sprintf("opening balance of %v", c.Name), postings...)
+}
+
+// Holdings returns what every corporation actually holds on each of its ledger accounts
+func (cg *CorpGroup) Holdings() map[gamecomm.Account]map[string]float64 {
+	holdings := make(map[gamecomm.Account]map[string]float64)
+	for _, c := range cg.corporations() {
+		c.Rw.RLock()
+		for account, assets := range c.holdings() {
+			holdings[account] = assets
+		}
+		c.Rw.RUnlock()
+	}
+
+	return holdings
+}
+
+// holdings returns the credits of the corporation, the goods stored on its bases and the cargo of its squads. The
+// caller must hold the corporation lock.
+func (c *Corporation) holdings() map[gamecomm.Account]map[string]float64 {
+	holdings := map[gamecomm.Account]map[string]float64{
+		gamecomm.NewCorporationAccount(c.ID): {gamecomm.CreditsAsset: c.Credits},
+	}
+
+	for i, b := range c.Bases {
+		holdings[gamecomm.NewBaseAccount(c.ID, i)] = goods(b.StoredResources)
+	}
+
+	for i, s := range c.Squads {
+		holdings[gamecomm.NewSquadAccount(c.ID, i)] = goods(s.Cargo)
+	}
+
+	return holdings
+}
+
+func goods(resources map[string]int) map[string]float64 {
+	assets := make(map[string]float64, len(resources))
+	for r, amount := range resources {
+		assets[r] = float64(amount)
+	}
+
+	return assets
+}
+
+// settle moves every holding from (sign 1) or to (sign -1) the counterparty
+func settle(holdings map[gamecomm.Account]map[string]float64, counterparty gamecomm.Account, sign float64) []gamecomm.Posting {
+	postings := []gamecomm.Posting{}
+	for account, assets := range holdings {
+		for asset, amount := range assets {
+			postings = append(postings,
+				gamecomm.Posting{Account: account, Asset: asset, Amount: sign * amount},
+				gamecomm.Posting{Account: counterparty, Asset: asset, Amount: -sign * amount},
+			)
+		}
+	}
+
+	return postings
+}
+
+// stockChanges posts the difference between the stock of the base before and after against the counterparty
+func stockChanges(account gamecomm.Account, before map[string]int, after map[string]int, counterparty gamecomm.Account) []gamecomm.Posting {
+	changes := make(map[string]int, len(after))
+	for r, amount := range after {
+		changes[r] += amount
+	}
+	for r, amount := range before {
+		changes[r] -= amount
+	}
+
+	postings := []gamecomm.Posting{}
+	for r, change := range changes {
+		postings = append(postings,
+			gamecomm.Posting{Account: account, Asset: r, Amount: float64(change)},
+			gamecomm.Posting{Account: counterparty, Asset: r, Amount: -float64(change)},
+		)
+	}
+
+	return postings
+}
+
+// recordCost posts the credits and materials paid for a construction, the materials go to the destination
+func (cg *CorpGroup) recordCost(memo string, corporationId uint64, baseIndex int, cost gamecomm.ConstructionCost, destination gamecomm.Account) {
+	cg.post(memo, costPostings(corporationId, baseIndex, cost, destination))
+}
+
+// recordRefund posts the credits and materials of a cancelled construction coming back from where they went
+func (cg *CorpGroup) recordRefund(memo string, corporationId uint64, baseIndex int, cost gamecomm.ConstructionCost, source gamecomm.Account) {
+	postings := costPostings(corporationId, baseIndex, cost, source)
+	for i := range postings {
+		postings[i].Amount = -postings[i].Amount
+	}
+
+	cg.post(memo, postings)
+}
+
+func costPostings(corporationId uint64, baseIndex int, cost gamecomm.ConstructionCost, destination gamecomm.Account) []gamecomm.Posting {
+	postings := []gamecomm.Posting{
+		{Account: gamecomm.NewCorporationAccount(corporationId), Asset: gamecomm.CreditsAsset, Amount: -cost.Credits},
+		{Account: constructionAccount, Asset: gamecomm.CreditsAsset, Amount: cost.Credits},
+	}
+
+	base := gamecomm.NewBaseAccount(corporationId, baseIndex)
+	for r, amount := range cost.Materials {
+		postings = append(postings,
+			gamecomm.Posting{Account: base, Asset: r, Amount: -float64(amount)},
+			gamecomm.Posting{Account: destination, Asset: r, Amount: float64(amount)},
+		)
+	}
+
+	return postings
+}
+
+// recordProduction posts the goods stored on the base by a production job times the factor, a negative factor
+// takes them out
+func (cg *CorpGroup) recordProduction(memo string, corporationId uint64, baseIndex int, resources map[string]int, factor float64) {
+	base := gamecomm.NewBaseAccount(corporationId, baseIndex)
+
+	postings := []gamecomm.Posting{}
+	for r, amount := range resources {
+		postings = append(postings,
+			gamecomm.Posting{Account: base, Asset: r, Amount: factor * float64(amount)},
+			gamecomm.Posting{Account: productionAccount, Asset: r, Amount: -factor * float64(amount)},
+		)
+	}
+
+	cg.post(memo, postings)
+}
@@ -0,0 +1,94 @@
+package corporation_test
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/corporation"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
+)
+
+func TestLedger(t *testing.T) {
+	sales := gamecomm.NewExternalAccount("sales")
+
+	tests := []struct {
+		name         string
+		commands     []gamecomm.CorpCommand
+		account      gamecomm.Account
+		asset        string
+		wants        float64
+		unattributed bool
+	}{
+		{
+			name: "Credits From Counterparty",
+			commands: []gamecomm.CorpCommand{
+				{Action: gamecomm.AddCredits, AmountDecimal: 500, Counterparty: sales},
+				{Action: gamecomm.RemoveCredits, AmountDecimal: 200, Counterparty: sales},
+			},
+			account: sales,
+			asset:   gamecomm.CreditsAsset,
+			wants:   -300,
+		},
+		{
+			name: "Goods Between Squad And Base",
+			commands: []gamecomm.CorpCommand{
+				{Action: gamecomm.RemoveAllResourcesFromSquad, SquadIndex: 0, Resource: "iron", Counterparty: gamecomm.NewEscrowAccount("mission/1")},
+				{Action: gamecomm.AddResourcesToBase, Resource: "iron", Amount: initialIronQuantity, Counterparty: gamecomm.NewEscrowAccount("mission/1")},
+			},
+			account: gamecomm.NewBaseAccount(corporationID, 0),
+			asset:   "iron",
+			wants:   2 * initialIronQuantity,
+		},
+		{
+			name: "Failed Command",
+			commands: []gamecomm.CorpCommand{
+				{Action: gamecomm.RemoveCredits, AmountDecimal: initialCorporationCredits + 1, Counterparty: sales},
+			},
+			account: gamecomm.NewCorporationAccount(corporationID),
+			asset:   gamecomm.CreditsAsset,
+			wants:   initialCorporationCredits,
+		},
+		{
+			name: "Without Counterparty",
+			commands: []gamecomm.CorpCommand{
+				{Action: gamecomm.AddResourcesToSquad, SquadIndex: 0, Resource: "iron", Amount: 10},
+			},
+			account:      gamecomm.NewSquadAccount(corporationID, 0),
+			asset:        "iron",
+			wants:        initialIronQuantity + 10,
+			unattributed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand, 10),
+			}
+
+			gc := gameclock.NewGameClock(0, 1)
+			l := ledger.New(gc)
+
+			cg := corporation.NewCorpGroup(gameChannels, gc, l)
+			cg.Corporations[corporationID] = createTestCorporation(t)
+			assert.NilError(t, cg.OpenAccounts(corporationID))
+			cg.Listen()
+
+			for _, command := range tt.commands {
+				resChan := make(chan gamecomm.ChanResponse)
+				command.CorporationId = corporationID
+				command.ResponseChannel = resChan
+				gameChannels.CorpChannel <- command
+				<-resChan
+			}
+
+			assert.Equal(t, l.Balance(tt.account)[tt.asset], tt.wants)
+
+			report := l.Check(cg.Holdings())
+			assert.Equal(t, len(report.Mismatches), 0)
+			assert.Equal(t, report.Consistent(), !tt.unattributed)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package corporation
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	baseOfficerBonus     = 0.1
+	officerBonusPerLevel = 0.01
+)
+
+// officerSkills is the crew skill that improves the bonus of each officer role
+var officerSkills = map[gamecomm.OfficerRole]string{
+	gamecomm.Captain:       gamecomm.CombatSkill,
+	gamecomm.Engineer:      gamecomm.PilotingSkill,
+	gamecomm.Quartermaster: gamecomm.HarvestingSkill,
+	gamecomm.Navigator:     gamecomm.PilotingSkill,
+}
+
+func officerBonus(role gamecomm.OfficerRole, officer *CrewMember) float64 {
+	return baseOfficerBonus + float64(officer.Skills[officerSkills[role]])*officerBonusPerLevel
+}
+
+// Bonuses returns the squad-wide bonuses of the officers assigned to the squad
+func (s *Squad) Bonuses() gamecomm.SquadBonuses {
+	bonuses := gamecomm.SquadBonuses{}
+
+	for role, officer := range s.Officers {
+		if officer.Status != gamecomm.CrewActive {
+			continue
+		}
+
+		bonus := officerBonus(role, officer)
+
+		switch role {
+		case gamecomm.Captain:
+			bonuses.Experience += bonus
+		case gamecomm.Engineer:
+			bonuses.RepairCost += bonus
+		case gamecomm.Quartermaster:
+			bonuses.HarvestYield += bonus
+		case gamecomm.Navigator:
+			bonuses.TravelSpeed += bonus
+		}
+	}
+
+	return bonuses
+}
+
+func (c *Corporation) findCrewMember(crewId uint64) (*CrewMember, error) {
+	for _, cm := range c.CrewMembers {
+		if cm.ID == crewId {
+			return cm, nil
+		}
+	}
+
+	return nil, fmt.Errorf("error: crew member not found %v", crewId)
+}
+
+// isOfficer returns true if the crew member is an officer on any squad of the corporation
+func (c *Corporation) isOfficer(crewId uint64) bool {
+	for _, s := range c.Squads {
+		for _, o := range s.Officers {
+			if o.ID == crewId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *Squad) hasCrewMember(crewId uint64) bool {
+	for _, cm := range s.CrewMembers {
+		if cm.ID == crewId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AssignOfficer makes the crew member the squad officer for the role. A squad can only have one officer per
+// role and a crew member can only hold one officer role.
+func (c *Corporation) AssignOfficer(squadIndex int, crewId uint64, role gamecomm.OfficerRole) (gamecomm.Squad, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if _, ok := officerSkills[role]; !ok {
+		return gamecomm.Squad{}, fmt.Errorf("error: invalid officer role %v", role)
+	}
+
+	if squadIndex < 0 || squadIndex >= len(c.Squads) {
+		return gamecomm.Squad{}, fmt.Errorf("error: squad not found %v", squadIndex)
+	}
+
+	squad := c.Squads[squadIndex]
+
+	if _, ok := squad.Officers[role]; ok {
+		return gamecomm.Squad{}, fmt.Errorf("error: squad %v already has a %v", squadIndex, role)
+	}
+
+	crewMember, err := c.findCrewMember(crewId)
+	if err != nil {
+		return gamecomm.Squad{}, err
+	}
+
+	if !squad.hasCrewMember(crewId) {
+		return gamecomm.Squad{}, fmt.Errorf("error: crew member %v is not on squad %v", crewId, squadIndex)
+	}
+
+	if crewMember.Status == gamecomm.CrewDead {
+		return gamecomm.Squad{}, fmt.Errorf("error: crew member %v is dead", crewId)
+	}
+
+	if c.isOfficer(crewId) {
+		return gamecomm.Squad{}, fmt.Errorf("error: crew member %v is already an officer", crewId)
+	}
+
+	if squad.Officers == nil {
+		squad.Officers = make(map[gamecomm.OfficerRole]*CrewMember)
+	}
+
+	squad.Officers[role] = crewMember
+
+	return squad.copy(), nil
+}
+
+func (c *Corporation) RemoveOfficer(squadIndex int, role gamecomm.OfficerRole) (gamecomm.Squad, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if squadIndex < 0 || squadIndex >= len(c.Squads) {
+		return gamecomm.Squad{}, fmt.Errorf("error: squad not found %v", squadIndex)
+	}
+
+	squad := c.Squads[squadIndex]
+
+	if _, ok := squad.Officers[role]; !ok {
+		return gamecomm.Squad{}, fmt.Errorf("error: squad %v has no %v", squadIndex, role)
+	}
+
+	delete(squad.Officers, role)
+
+	return squad.copy(), nil
+}
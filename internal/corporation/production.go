@@ -0,0 +1,168 @@
+package corporation
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+// ProductionJob is a recipe running on a base facility. Reserved is the base storage kept for its outputs.
+type ProductionJob struct {
+	Recipe   string
+	Batches  int
+	Reserved int
+}
+
+// runningJobs returns the number of jobs using the facility
+func (b *Base) runningJobs(facility string, recipes map[string]resource.Recipe) int {
+	jobs := 0
+	for _, j := range b.Jobs {
+		if recipes[j.Recipe].Facility == facility {
+			jobs++
+		}
+	}
+
+	return jobs
+}
+
+// StartProduction takes the recipe inputs for the batches out of the base storage and starts the job. Each
+// level of the required facility can run one job at a time, and the base storage must have room for the outputs.
+func (c *Corporation) StartProduction(baseIndex int, recipe resource.Recipe, batches int, recipes map[string]resource.Recipe) (gamecomm.ProductionJob, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if batches <= 0 {
+		return gamecomm.ProductionJob{}, fmt.Errorf("error: batches should be greater than zero")
+	}
+
+	if baseIndex < 0 || baseIndex >= len(c.Bases) {
+		return gamecomm.ProductionJob{}, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := c.Bases[baseIndex]
+
+	if recipe.Facility != "" {
+		f := base.facility(FacilityType(recipe.Facility))
+		if f == nil {
+			return gamecomm.ProductionJob{}, fmt.Errorf("error: base %v needs a %v to make %v", base.Name, recipe.Facility, recipe.Name)
+		}
+
+		if base.runningJobs(recipe.Facility, recipes) >= f.Level {
+			return gamecomm.ProductionJob{}, fmt.Errorf("error: every %v on base %v is busy", recipe.Facility, base.Name)
+		}
+	}
+
+	for r, amount := range recipe.Inputs {
+		if base.StoredResources[r] < amount*batches {
+			return gamecomm.ProductionJob{}, fmt.Errorf("error: not enough %v on base %v", r, base.Name)
+		}
+	}
+
+	inputs := 0
+	for _, amount := range recipe.Inputs {
+		inputs += amount * batches
+	}
+
+	outputs := 0
+	for _, amount := range recipe.Outputs {
+		outputs += amount * batches
+	}
+
+	if outputs > base.freeStorage()+inputs {
+		return gamecomm.ProductionJob{}, fmt.Errorf("error: not enough storage on base %v for %v", base.Name, recipe.Name)
+	}
+
+	for r, amount := range recipe.Inputs {
+		base.StoredResources[r] -= amount * batches
+	}
+
+	base.Jobs = append(base.Jobs, ProductionJob{Recipe: recipe.Name, Batches: batches, Reserved: outputs})
+
+	return gamecomm.ProductionJob{
+		Recipe:   recipe.Name,
+		Batches:  batches,
+		Duration: recipe.Duration * gameclock.GameTimeDuration(batches),
+	}, nil
+}
+
+// CompleteProduction finishes the job and stores the recipe outputs on the storage reserved for them. Returns
+// the amount stored of each output.
+func (c *Corporation) CompleteProduction(baseIndex int, recipe resource.Recipe, batches int) (map[string]int, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if baseIndex < 0 || baseIndex >= len(c.Bases) {
+		return nil, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := c.Bases[baseIndex]
+
+	err := base.removeJob(recipe.Name, batches)
+	if err != nil {
+		return nil, err
+	}
+
+	if base.StoredResources == nil {
+		base.StoredResources = make(map[string]int)
+	}
+
+	produced := make(map[string]int, len(recipe.Outputs))
+	for r, amount := range recipe.Outputs {
+		produced[r] = amount * batches
+		base.StoredResources[r] += produced[r]
+	}
+
+	return produced, nil
+}
+
+// CancelProduction stops the job and gives its inputs back to the base. Returns the amount returned of each
+// input.
+func (c *Corporation) CancelProduction(baseIndex int, recipe resource.Recipe, batches int) (map[string]int, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if baseIndex < 0 || baseIndex >= len(c.Bases) {
+		return nil, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	base := c.Bases[baseIndex]
+
+	err := base.removeJob(recipe.Name, batches)
+	if err != nil {
+		return nil, err
+	}
+
+	if base.StoredResources == nil {
+		base.StoredResources = make(map[string]int)
+	}
+
+	returned := make(map[string]int, len(recipe.Inputs))
+	for r, amount := range recipe.Inputs {
+		returned[r] = amount * batches
+		base.StoredResources[r] += returned[r]
+	}
+
+	return returned, nil
+}
+
+func (b *Base) removeJob(recipe string, batches int) error {
+	for i, j := range b.Jobs {
+		if j.Recipe == recipe && j.Batches == batches {
+			b.Jobs = append(b.Jobs[:i], b.Jobs[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error: base %v is not making %v", b.Name, recipe)
+}
+
+func (cg *CorpGroup) findRecipe(name string) (resource.Recipe, error) {
+	recipe, ok := cg.Recipes[name]
+	if !ok {
+		return resource.Recipe{}, fmt.Errorf("error: recipe not found %v", name)
+	}
+
+	return recipe, nil
+}
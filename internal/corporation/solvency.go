@@ -0,0 +1,246 @@
+package corporation
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/maputils"
+)
+
+const (
+	// crewDailyWage is what every crew member that isn't dead is paid every day
+	crewDailyWage = 5.0
+	// gracePeriod is how long a corporation can have negative credits before it's liquidated
+	gracePeriod gameclock.GameTimeDuration = 7 * gameclock.Day
+	// restructuringReputationLoss is the reputation the player loses when its debts are written off
+	restructuringReputationLoss = 50
+	minReputation               = -100
+)
+
+// SubscribeEvents sends the solvency events of every corporation to the subscriber. Subscribers must keep reading,
+// events are sent in order and the daily simulation waits for them.
+func (cg *CorpGroup) SubscribeEvents(subscriber chan gamecomm.CorporationEvent) {
+	cg.RW.Lock()
+	defer cg.RW.Unlock()
+
+	cg.eventSubscribers = append(cg.eventSubscribers, subscriber)
+}
+
+func (cg *CorpGroup) publish(event gamecomm.CorporationEvent) {
+	cg.RW.RLock()
+	subscribers := cg.eventSubscribers
+	cg.RW.RUnlock()
+
+	for _, subscriber := range subscribers {
+		subscriber <- event
+	}
+}
+
+// ChargeCredits takes a cost the corporation can't refuse, like wages or losses, the credits can go negative
+func (c *CorpGroup) ChargeCredits(corporationId uint64, amount float64) (float64, error) {
+	if amount < 0 {
+		return 0, fmt.Errorf("error: amount should be greater than zero")
+	}
+
+	corporation, err := c.findCorporationReference(corporationId)
+	if err != nil {
+		return 0, err
+	}
+
+	corporation.Rw.Lock()
+	defer corporation.Rw.Unlock()
+
+	return corporation.charge(amount), nil
+}
+
+// charge is where every cost the corporation can't refuse is taken, the caller holds the lock
+func (c *Corporation) charge(amount float64) float64 {
+	c.Credits -= amount
+
+	return c.Credits
+}
+
+// RunPayroll pays one day of wages of every corporation crew
+func (cg *CorpGroup) RunPayroll() {
+	for _, c := range cg.corporations() {
+		c.Rw.Lock()
+		wages := c.payroll()
+		c.charge(wages)
+		c.Rw.Unlock()
+
+		cg.ledger.Transfer("payroll", gamecomm.NewCorporationAccount(c.ID), wagesAccount, gamecomm.CreditsAsset, wages)
+	}
+}
+
+func (c *Corporation) payroll() float64 {
+	wages := 0.0
+	for _, cm := range c.CrewMembers {
+		if cm.Status != gamecomm.CrewDead {
+			wages += crewDailyWage
+		}
+	}
+
+	return wages
+}
+
+// CheckSolvency follows the corporations with negative credits. They become insolvent, have the grace period to
+// recover and are then liquidated. If the liquidation doesn't cover the debts NPC corporations go bankrupt and the
+// player is restructured.
+func (cg *CorpGroup) CheckSolvency(now gameclock.GameTime) {
+	for _, c := range cg.corporations() {
+		c.Rw.Lock()
+		credits := c.Credits
+		status := c.Status
+		insolventSince := c.InsolventSince
+
+		if credits >= 0 && status == gamecomm.Insolvent {
+			c.Status = gamecomm.Solvent
+		}
+
+		if credits < 0 && status == gamecomm.Solvent {
+			c.Status = gamecomm.Insolvent
+			c.InsolventSince = now
+		}
+		c.Rw.Unlock()
+
+		switch {
+		case credits >= 0 && status == gamecomm.Insolvent:
+			cg.publish(c.event(gamecomm.CorporationRecovered, now))
+		case credits < 0 && status == gamecomm.Solvent:
+			cg.publish(c.event(gamecomm.CorporationInsolvent, now))
+		case credits < 0 && status == gamecomm.Insolvent && !now.Before(insolventSince.Add(gracePeriod)):
+			cg.liquidate(c, now)
+		}
+	}
+}
+
+// liquidate sells every good stored on the bases and listed on the markets by the corporation at a discount
+func (cg *CorpGroup) liquidate(c *Corporation, now gameclock.GameTime) {
+	c.Rw.RLock()
+	assets := make(map[string]int)
+	for _, b := range c.Bases {
+		for r, amount := range b.StoredResources {
+			if amount > 0 {
+				assets[r] += amount
+			}
+		}
+	}
+	c.Rw.RUnlock()
+
+	credits, err := cg.liquidationValue(c.ID, assets)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+
+	c.Rw.Lock()
+	postings := []gamecomm.Posting{
+		{Account: gamecomm.NewCorporationAccount(c.ID), Asset: gamecomm.CreditsAsset, Amount: credits},
+		{Account: liquidationAccount, Asset: gamecomm.CreditsAsset, Amount: -credits},
+	}
+
+	for i, b := range c.Bases {
+		before := maputils.CopyMap(b.StoredResources)
+		for r, amount := range b.StoredResources {
+			if amount > 0 {
+				b.StoredResources[r] = 0
+			}
+		}
+
+		postings = append(postings, stockChanges(gamecomm.NewBaseAccount(c.ID, i), before, b.StoredResources, liquidationAccount)...)
+	}
+
+	c.Credits += credits
+	solvent := c.Credits >= 0
+	if solvent {
+		c.Status = gamecomm.Solvent
+	}
+	c.Rw.Unlock()
+
+	cg.post("liquidation", postings)
+	cg.publish(c.event(gamecomm.CorporationLiquidated, now))
+
+	if solvent {
+		cg.publish(c.event(gamecomm.CorporationRecovered, now))
+		return
+	}
+
+	cg.bankrupt(c, now)
+}
+
+// liquidationValue asks the economy what the goods are worth in a forced sale
+func (cg *CorpGroup) liquidationValue(corporationId uint64, assets map[string]int) (float64, error) {
+	if cg.economyChan == nil {
+		return 0, nil
+	}
+
+	resChan := make(chan gamecomm.ChanResponse)
+	cg.economyChan <- gamecomm.EconomyCommand{
+		Action:          gamecomm.LiquidateAssets,
+		CorporationId:   corporationId,
+		Assets:          assets,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return 0, res.Err
+	}
+
+	return res.Val.(float64), nil
+}
+
+// bankrupt removes NPC corporations. The player can't be removed, its debts are written off and it loses reputation.
+func (cg *CorpGroup) bankrupt(c *Corporation, now gameclock.GameTime) {
+	c.Rw.Lock()
+	if c.IsPlayer {
+		writtenOff := -c.Credits
+		c.Credits = 0
+		c.Reputation = max(c.Reputation-restructuringReputationLoss, minReputation)
+		c.Status = gamecomm.Solvent
+		c.Rw.Unlock()
+
+		cg.ledger.Transfer("restructuring", restructuringAccount, gamecomm.NewCorporationAccount(c.ID), gamecomm.CreditsAsset, writtenOff)
+
+		cg.publish(c.event(gamecomm.CorporationRestructured, now))
+		return
+	}
+
+	c.Status = gamecomm.Bankrupt
+	postings := settle(c.holdings(), bankruptcyAccount, -1)
+	c.Rw.Unlock()
+
+	cg.post("bankruptcy", postings)
+
+	cg.RW.Lock()
+	delete(cg.Corporations, c.ID)
+	cg.RW.Unlock()
+
+	cg.publish(c.event(gamecomm.CorporationBankrupt, now))
+}
+
+func (c *Corporation) event(eventType gamecomm.CorporationEventType, now gameclock.GameTime) gamecomm.CorporationEvent {
+	c.Rw.RLock()
+	defer c.Rw.RUnlock()
+
+	return gamecomm.CorporationEvent{
+		Type:          eventType,
+		CorporationId: c.ID,
+		Name:          c.Name,
+		IsPlayer:      c.IsPlayer,
+		Credits:       c.Credits,
+		Time:          now,
+	}
+}
+
+func (cg *CorpGroup) corporations() []*Corporation {
+	cg.RW.RLock()
+	defer cg.RW.RUnlock()
+
+	corporations := make([]*Corporation, 0, len(cg.Corporations))
+	for _, c := range cg.Corporations {
+		corporations = append(corporations, c)
+	}
+
+	return corporations
+}
@@ -8,13 +8,20 @@ import (
 	"github.com/luisya22/galactic-exchange/internal/world"
 )
 
+const (
+	// shipWearPerDanger is the health a ship loses on a mission for every danger level of the destination
+	shipWearPerDanger = 5
+	// repairCostPerHealth is what the base charges to repair one point of ship health
+	repairCostPerHealth = 2.0
+)
+
 type Squad struct {
 	Id          uint64
 	Ships       *ship.Ship
 	CrewMembers []*CrewMember
 	Cargo       map[string]int
 	Location    world.Coordinates
-	// Officers []Officers   coming soon...
+	Officers    map[gamecomm.OfficerRole]*CrewMember
 }
 
 func (s Squad) GetHarvestingBonus() int {
@@ -92,6 +99,7 @@ func (c *Corporation) RemoveResourcesFromSquad(squadIndex int, resource string,
 	return squad.Cargo[resource], nil
 }
 
+// RemoveAllResourcesFromSquad unloads the resource from the squad cargo and returns the amount unloaded
 func (c *Corporation) RemoveAllResourcesFromSquad(squadIndex int, resource string) (int, error) {
 	var squad *Squad
 
@@ -104,12 +112,59 @@ func (c *Corporation) RemoveAllResourcesFromSquad(squadIndex int, resource strin
 
 	squad = c.Squads[squadIndex]
 
+	removed := squad.Cargo[resource]
 	squad.Cargo[resource] = 0
 
-	return squad.Cargo[resource], nil
+	return removed, nil
 
 }
 
+// SeizeShip takes the ship of the squad away, the squad can't go on missions until it gets a new one
+func (c *Corporation) SeizeShip(squadIndex int) (gamecomm.Ship, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if squadIndex < 0 || squadIndex >= len(c.Squads) {
+		return gamecomm.Ship{}, fmt.Errorf("error: squad not found %v", squadIndex)
+	}
+
+	squad := c.Squads[squadIndex]
+	if squad.Ships == nil {
+		return gamecomm.Ship{}, fmt.Errorf("error: squad %v has no ship", squadIndex)
+	}
+
+	ship := squad.Ships.Copy()
+	squad.Ships = nil
+
+	return ship, nil
+}
+
+// RepairSquadShip wears the squad ship down with the danger of the mission it came back from and repairs it at the
+// base. Repairs can't be refused so they are charged even if the credits go negative, an engineer makes them
+// cheaper. Returns the credits charged.
+func (c *Corporation) RepairSquadShip(squadIndex int, dangerLevel int) (float64, error) {
+	c.Rw.Lock()
+	defer c.Rw.Unlock()
+
+	if squadIndex < 0 || squadIndex >= len(c.Squads) {
+		return 0, fmt.Errorf("error: squad not found %v", squadIndex)
+	}
+
+	squad := c.Squads[squadIndex]
+	if squad.Ships == nil {
+		return 0, nil
+	}
+
+	ship := squad.Ships
+	ship.ActualHealth = max(ship.ActualHealth-dangerLevel*shipWearPerDanger, 0)
+
+	cost := float64(ship.MaxHealth-ship.ActualHealth) * repairCostPerHealth * max(1-squad.Bonuses().RepairCost, 0)
+	ship.ActualHealth = ship.MaxHealth
+	c.charge(cost)
+
+	return cost, nil
+}
+
 func (s *Squad) copy() gamecomm.Squad {
 
 	crew := []gamecomm.CrewMember{}
@@ -122,13 +177,25 @@ func (s *Squad) copy() gamecomm.Squad {
 		cargo[string(r)] = c
 	}
 
+	officers := make(map[gamecomm.OfficerRole]gamecomm.CrewMember, len(s.Officers))
+	for role, o := range s.Officers {
+		officers[role] = o.Copy()
+	}
+
 	coordinates := gamecomm.Coordinates{X: s.Location.X, Y: s.Location.Y}
 
+	ships := gamecomm.Ship{}
+	if s.Ships != nil {
+		ships = s.Ships.Copy()
+	}
+
 	return gamecomm.Squad{
 		Id:          s.Id,
-		Ships:       s.Ships.Copy(),
+		Ships:       ships,
 		CrewMembers: crew,
 		Cargo:       cargo,
 		Location:    coordinates,
+		Officers:    officers,
+		Bonuses:     s.Bonuses(),
 	}
 }
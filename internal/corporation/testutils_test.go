@@ -5,6 +5,7 @@ import (
 
 	"github.com/luisya22/galactic-exchange/internal/corporation"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/resource"
 	"github.com/luisya22/galactic-exchange/internal/ship"
 	"github.com/luisya22/galactic-exchange/internal/world"
 )
@@ -31,6 +32,7 @@ func createTestCorpGroup(t *testing.T, gameChannels *gamecomm.GameChannels) *cor
 		Corporations: corporations,
 		Workers:      10,
 		CorpChan:     gameChannels.CorpChannel,
+		Recipes:      resource.LoadRecipes(),
 	}
 }
 
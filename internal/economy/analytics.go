@@ -1,13 +1,10 @@
 package economy
 
 import (
-	"sync"
-
 	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/resource"
 )
 
-type zoneAnalytics map[string]*analytics
 type resourceVolume map[string]int
 type transactionFrequency map[string]int
 type avgListingDuration map[string]float64
@@ -19,7 +16,6 @@ type analytics struct {
 	avgListingDuration map[gameclock.GameTime]avgListingDuration
 	listingVolume      map[gameclock.GameTime]resourceVolume
 	listingAmount      map[gameclock.GameTime]transactionFrequency
-	rw                 sync.RWMutex
 	historicPrices     map[string]dailyPrices
 }
 
@@ -34,69 +30,49 @@ func newAnalytics() *analytics {
 	}
 }
 
-// func (a *analytics) updateListingVolume(resource string, quantity int, listingTime gameclock.GameTime) {
-// 	a.rw.Lock()
-// 	defer a.rw.Unlock()
-//
-// 	if _, ok := a.listingVolume[listingTime.StartOfDay()]; !ok {
-// 		a.listingVolume[listingTime.StartOfDay()] = make(resourceVolume)
-// 	}
-//
-// 	if _, ok := a.listingVolume[listingTime.StartOfDay()][resource]; !ok {
-// 		a.listingVolume[listingTime.StartOfDay()][resource] = 0
-// 	}
-//
-// 	a.listingVolume[listingTime.StartOfDay()][resource] += quantity
-// }
-
-// func (a *analytics) updateListingAmount(resource string, listingTime gameclock.GameTime) {
-// 	a.rw.Lock()
-// 	defer a.rw.Unlock()
-//
-// 	if _, ok := a.listingAmount[listingTime.StartOfDay()]; !ok {
-// 		a.listingAmount[listingTime.StartOfDay()] = make(transactionFrequency)
-// 	}
-//
-// 	if _, ok := a.listingAmount[listingTime.StartOfDay()][resource]; !ok {
-// 		a.listingAmount[listingTime.StartOfDay()][resource] = 0
-// 	}
-//
-// 	a.listingAmount[listingTime.StartOfDay()][resource] += 1
-// }
-
-// func (a *analytics) updateSalesVolume(resource string, quantity int, saleTime gameclock.GameTime) {
-// 	a.rw.Lock()
-// 	defer a.rw.Unlock()
-//
-// 	if _, ok := a.salesVolume[saleTime.StartOfDay()]; !ok {
-// 		a.salesVolume[saleTime.StartOfDay()] = make(resourceVolume)
-// 	}
-//
-// 	if _, ok := a.salesVolume[saleTime.StartOfDay()][resource]; !ok {
-// 		a.salesVolume[saleTime.StartOfDay()][resource] = 0
-// 	}
-//
-// 	a.salesVolume[saleTime.StartOfDay()][resource] += quantity
-// }
-
-// func (a *analytics) updateSalesAmount(resource string, saleTime gameclock.GameTime) {
-// 	a.rw.Lock()
-// 	defer a.rw.Unlock()
-//
-// 	if _, ok := a.salesAmount[saleTime.StartOfDay()]; !ok {
-// 		a.salesAmount[saleTime.StartOfDay()] = make(transactionFrequency)
-// 	}
-//
-// 	if _, ok := a.salesAmount[saleTime.StartOfDay()][resource]; !ok {
-// 		a.salesAmount[saleTime.StartOfDay()][resource] = 0
-// 	}
-//
-// 	a.salesAmount[saleTime.StartOfDay()][resource]++
-// }
+// updateListingVolume adds the listed quantity to the supply of the day
+func (a *analytics) updateListingVolume(resource string, quantity int, listingTime gameclock.GameTime) {
+	day := listingTime.StartOfDay()
+
+	if _, ok := a.listingVolume[day]; !ok {
+		a.listingVolume[day] = make(resourceVolume)
+	}
+
+	a.listingVolume[day][resource] += quantity
+}
+
+func (a *analytics) updateListingAmount(resource string, listingTime gameclock.GameTime) {
+	day := listingTime.StartOfDay()
+
+	if _, ok := a.listingAmount[day]; !ok {
+		a.listingAmount[day] = make(transactionFrequency)
+	}
+
+	a.listingAmount[day][resource]++
+}
+
+// updateSalesVolume adds the sold quantity to the demand of the day
+func (a *analytics) updateSalesVolume(resource string, quantity int, saleTime gameclock.GameTime) {
+	day := saleTime.StartOfDay()
+
+	if _, ok := a.salesVolume[day]; !ok {
+		a.salesVolume[day] = make(resourceVolume)
+	}
+
+	a.salesVolume[day][resource] += quantity
+}
+
+func (a *analytics) updateSalesAmount(resource string, saleTime gameclock.GameTime) {
+	day := saleTime.StartOfDay()
+
+	if _, ok := a.salesAmount[day]; !ok {
+		a.salesAmount[day] = make(transactionFrequency)
+	}
+
+	a.salesAmount[day][resource]++
+}
 
 func (a *analytics) calculateDailySupply(resource string, day gameclock.GameTime) int {
-	// a.rw.Lock()
-	// defer a.rw.Unlock()
 
 	dayStart := day.StartOfDay()
 	totalSupply := 0
@@ -109,8 +85,6 @@ func (a *analytics) calculateDailySupply(resource string, day gameclock.GameTime
 }
 
 func (a *analytics) calculateDailyDemand(resource string, day gameclock.GameTime) int {
-	// a.rw.Lock()
-	// defer a.rw.Unlock()
 
 	dayStart := day.StartOfDay()
 	totalDemand := 0
@@ -123,24 +97,12 @@ func (a *analytics) calculateDailyDemand(resource string, day gameclock.GameTime
 }
 
 func (a *analytics) updateItemPrices(resources map[string]resource.Resource, prices resourcePrices, day gameclock.GameTime) resourcePrices {
-	// a.rw.Lock()
-	// defer a.rw.Unlock()
 	startOfDay := day.StartOfDay()
 	for name := range resources {
 		dailySupply := a.calculateDailySupply(name, startOfDay)
 		dailyDemand := a.calculateDailyDemand(name, startOfDay)
 
-		var priceAdjustment float64
-		if dailyDemand > dailySupply {
-			priceAdjustment = 1.01
-		} else if dailyDemand < dailySupply {
-			priceAdjustment = 0.99
-		} else {
-			priceAdjustment = 1.00
-		}
-
-		currentPrice := prices[name]
-		updatedPrice := currentPrice * priceAdjustment
+		updatedPrice := adjustPrice(resources[name], prices[name], dailySupply, dailyDemand)
 		prices[name] = updatedPrice
 	}
 
@@ -148,8 +110,6 @@ func (a *analytics) updateItemPrices(resources map[string]resource.Resource, pri
 }
 
 func (a *analytics) storeHistoricPrices(resources map[string]resource.Resource, prices resourcePrices, day gameclock.GameTime) {
-	// a.rw.Lock()
-	// defer a.rw.Unlock()
 
 	startOfDay := day.StartOfDay()
 
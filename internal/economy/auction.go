@@ -0,0 +1,263 @@
+package economy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// minBidIncrement is how much a bid has to raise the highest bid
+	minBidIncrement = 0.05
+	// snipingWindow is the time before the end of an auction in which a bid extends it
+	snipingWindow gameclock.GameTimeDuration = 1
+	// snipingExtension is the time an auction stays open after a late bid
+	snipingExtension   gameclock.GameTimeDuration = 2
+	maxAuctionDuration gameclock.GameTimeDuration = 30 * gameclock.Day
+)
+
+// auction holds the lot and the credits of the highest bid until it's settled
+type auction struct {
+	id                 string
+	sellerId           uint64
+	resource           string
+	amount             int
+	reservePrice       float64
+	endTime            gameclock.GameTime
+	highestBid         float64
+	highestBidderId    uint64
+	bidderNotification chan string
+	sellerNotification chan string
+}
+
+func (a *auction) copy(zoneId string) gamecomm.Auction {
+	return gamecomm.Auction{
+		Id:              a.id,
+		ZoneId:          zoneId,
+		SellerId:        a.sellerId,
+		Resource:        a.resource,
+		Amount:          a.amount,
+		ReservePrice:    a.reservePrice,
+		HighestBid:      a.highestBid,
+		HighestBidderId: a.highestBidderId,
+		EndTime:         a.endTime,
+	}
+}
+
+// createAuction takes the lot from the seller base and opens the auction
+func (z *zoneMarket) createAuction(sellerId uint64, resourceName string, amount int, reservePrice float64, duration gameclock.GameTimeDuration, notificationChan chan string) (string, error) {
+	if _, ok := z.resources[resourceName]; !ok {
+		return "", fmt.Errorf("error: resource doesn't exist")
+	}
+
+	if amount <= 0 {
+		return "", fmt.Errorf("error: amount should be greater than zero")
+	}
+
+	if reservePrice <= 0 {
+		return "", fmt.Errorf("error: reserve price should be greater than zero")
+	}
+
+	if duration == 0 || duration > maxAuctionDuration {
+		return "", fmt.Errorf("error: duration should be between 1 and %d hours", maxAuctionDuration)
+	}
+
+	id := z.auctionId(z.auctionCounter + 1)
+
+	err := z.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveResourcesFromBase,
+		CorporationId: sellerId,
+		Resource:      resourceName,
+		Amount:        amount,
+		Counterparty:  auctionEscrow(id),
+		Memo:          "auction lot",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	z.auctionCounter++
+
+	z.auctions[id] = &auction{
+		id:                 id,
+		sellerId:           sellerId,
+		resource:           resourceName,
+		amount:             amount,
+		reservePrice:       reservePrice,
+		endTime:            z.gameClock.GetCurrentTime().Add(duration),
+		sellerNotification: notificationChan,
+	}
+
+	return id, nil
+}
+
+// placeBid takes the credits of the bid from the bidder and gives the credits back to the bidder it replaces. Bids
+// close to the end of the auction extend it so every bidder has time to answer.
+func (z *zoneMarket) placeBid(auctionId string, bidderId uint64, bid float64, notificationChan chan string) error {
+	a, ok := z.auctions[auctionId]
+	if !ok {
+		return fmt.Errorf("error: auction not found with ID '%s'", auctionId)
+	}
+
+	now := z.gameClock.GetCurrentTime()
+	if !now.Before(a.endTime) {
+		return fmt.Errorf("error: auction with ID '%s' has ended", auctionId)
+	}
+
+	if bidderId == a.sellerId {
+		return fmt.Errorf("error: you can not bid on your own auction")
+	}
+
+	if bid < a.reservePrice {
+		return fmt.Errorf("error: bid should be at least the reserve price %.2f", a.reservePrice)
+	}
+
+	if a.highestBidderId != 0 && bid < a.highestBid*(1+minBidIncrement) {
+		return fmt.Errorf("error: bid should be at least %.2f", a.highestBid*(1+minBidIncrement))
+	}
+
+	err := z.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveCredits,
+		CorporationId: bidderId,
+		AmountDecimal: bid,
+		Counterparty:  auctionEscrow(a.id),
+		Memo:          "auction bid",
+	})
+	if err != nil {
+		return err
+	}
+
+	if a.highestBidderId != 0 {
+		err = z.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddCredits,
+			CorporationId: a.highestBidderId,
+			AmountDecimal: a.highestBid,
+			Counterparty:  auctionEscrow(a.id),
+			Memo:          "auction outbid refund",
+		})
+		if err != nil {
+			// The highest bid stays, the new bidder gets the credits back
+			refundErr := z.corpCommand(gamecomm.CorpCommand{
+				Action:        gamecomm.AddCredits,
+				CorporationId: bidderId,
+				AmountDecimal: bid,
+				Counterparty:  auctionEscrow(a.id),
+				Memo:          "auction bid refund",
+			})
+			if refundErr != nil {
+				fmt.Println(refundErr.Error())
+			}
+
+			return err
+		}
+
+		notify(a.bidderNotification, fmt.Sprintf("Auction Notification: you were outbid on %v with %.2f credits", a.id, bid))
+	}
+
+	a.highestBid = bid
+	a.highestBidderId = bidderId
+	a.bidderNotification = notificationChan
+
+	if a.endTime <= now.Add(snipingWindow) {
+		a.endTime = now.Add(snipingExtension)
+	}
+
+	return nil
+}
+
+// closeAuctions settles the auctions that ended, the lot goes to the highest bidder and the credits to the seller.
+// Lots without bids go back to the seller.
+func (z *zoneMarket) closeAuctions(now gameclock.GameTime) {
+	for id, a := range z.auctions {
+		if now.Before(a.endTime) {
+			continue
+		}
+
+		delete(z.auctions, id)
+
+		if a.highestBidderId == 0 {
+			err := z.corpCommand(gamecomm.CorpCommand{
+				Action:        gamecomm.AddResourcesToBase,
+				CorporationId: a.sellerId,
+				Resource:      a.resource,
+				Amount:        a.amount,
+				Counterparty:  auctionEscrow(a.id),
+				Memo:          "auction lot returned",
+			})
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+
+			notify(a.sellerNotification, fmt.Sprintf("Auction Notification: %v ended without bids", a.id))
+			continue
+		}
+
+		err := z.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddResourcesToBase,
+			CorporationId: a.highestBidderId,
+			Resource:      a.resource,
+			Amount:        a.amount,
+			Counterparty:  auctionEscrow(a.id),
+			Memo:          "auction won",
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+
+		err = z.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddCredits,
+			CorporationId: a.sellerId,
+			AmountDecimal: a.highestBid,
+			Counterparty:  auctionEscrow(a.id),
+			Memo:          "auction sale",
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+
+		z.addTransaction("", a.sellerId, a.id, a.resource, a.amount, a.highestBid, now)
+
+		notify(a.bidderNotification, fmt.Sprintf("Auction Notification: you won %v, %v %v added to base", a.id, a.amount, a.resource))
+		notify(a.sellerNotification, fmt.Sprintf("Auction Notification: %v sold for %.2f credits", a.id, a.highestBid))
+	}
+}
+
+func (z *zoneMarket) getAuctions() []gamecomm.Auction {
+	auctions := make([]gamecomm.Auction, 0, len(z.auctions))
+	for _, a := range z.auctions {
+		auctions = append(auctions, a.copy(z.zoneId))
+	}
+
+	sort.Slice(auctions, func(i, j int) bool {
+		return auctions[i].EndTime < auctions[j].EndTime
+	})
+
+	return auctions
+}
+
+func (z *zoneMarket) corpCommand(command gamecomm.CorpCommand) error {
+	return sendCorpCommand(z.gameChannels.CorpChannel, command).Err
+}
+
+// sendCorpCommand sends the command to the corporations and waits for the response
+func sendCorpCommand(corpChannel chan gamecomm.CorpCommand, command gamecomm.CorpCommand) gamecomm.ChanResponse {
+	resChan := make(chan gamecomm.ChanResponse)
+	command.ResponseChannel = resChan
+
+	corpChannel <- command
+
+	return <-resChan
+}
+
+// notify sends the message without blocking the zone market
+func notify(notificationChan chan string, message string) {
+	if notificationChan == nil {
+		return
+	}
+
+	go func() {
+		notificationChan <- message
+	}()
+}
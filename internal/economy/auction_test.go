@@ -0,0 +1,156 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+)
+
+func TestPlaceBid(t *testing.T) {
+	tests := []struct {
+		name            string
+		setup           func(t *testing.T, z *zoneMarket, a *auction)
+		bidderId        uint64
+		bid             float64
+		expectedError   string
+		expectedBidder  uint64
+		expectedCredits map[uint64]float64
+	}{
+		{
+			name:            "First Bid",
+			bidderId:        2,
+			bid:             500,
+			expectedBidder:  2,
+			expectedCredits: map[uint64]float64{2: 500, 3: 1_000},
+		},
+		{
+			name: "Outbid",
+			setup: func(t *testing.T, z *zoneMarket, a *auction) {
+				assert.NilError(t, z.placeBid(a.id, 3, 500, nil))
+			},
+			bidderId:        2,
+			bid:             600,
+			expectedBidder:  2,
+			expectedCredits: map[uint64]float64{2: 400, 3: 1_000},
+		},
+		{
+			name: "Bid Under The Increment",
+			setup: func(t *testing.T, z *zoneMarket, a *auction) {
+				assert.NilError(t, z.placeBid(a.id, 3, 500, nil))
+			},
+			bidderId:        2,
+			bid:             510,
+			expectedError:   "error: bid should be at least 525.00",
+			expectedBidder:  3,
+			expectedCredits: map[uint64]float64{2: 1_000, 3: 500},
+		},
+		{
+			name:            "Under The Reserve Price",
+			bidderId:        2,
+			bid:             50,
+			expectedError:   "error: bid should be at least the reserve price",
+			expectedCredits: map[uint64]float64{2: 1_000, 3: 1_000},
+		},
+		{
+			name:            "Own Auction",
+			bidderId:        1,
+			bid:             500,
+			expectedError:   "error: you can not bid on your own auction",
+			expectedCredits: map[uint64]float64{2: 1_000, 3: 1_000},
+		},
+		{
+			name:            "Not Enough Credits",
+			bidderId:        2,
+			bid:             1_500,
+			expectedError:   "error: not enough credits",
+			expectedCredits: map[uint64]float64{2: 1_000, 3: 1_000},
+		},
+		{
+			name: "Auction Ended",
+			setup: func(t *testing.T, z *zoneMarket, a *auction) {
+				a.endTime = z.gameClock.GetCurrentTime()
+			},
+			bidderId:        2,
+			bid:             500,
+			expectedError:   "has ended",
+			expectedCredits: map[uint64]float64{2: 1_000, 3: 1_000},
+		},
+		{
+			// The highest bidder went bankrupt so the refund fails, the new bidder keeps the credits
+			name: "Outbid Refund Fails",
+			setup: func(t *testing.T, z *zoneMarket, a *auction) {
+				a.highestBidderId = 9
+				a.highestBid = 500
+			},
+			bidderId:        2,
+			bid:             600,
+			expectedError:   "error: corporation not found",
+			expectedBidder:  9,
+			expectedCredits: map[uint64]float64{2: 1_000, 3: 1_000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, tc := createTestZone(t, map[uint64]*testCorporation{
+				1: {resources: map[string]int{"iron": 100}},
+				2: {credits: 1_000},
+				3: {credits: 1_000},
+			})
+
+			id, err := z.createAuction(1, "iron", 100, 100, 10*gameclock.Day, nil)
+			assert.NilError(t, err)
+
+			a := z.auctions[id]
+			if tt.setup != nil {
+				tt.setup(t, z, a)
+			}
+
+			err = z.placeBid(id, tt.bidderId, tt.bid, nil)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, a.highestBid, tt.bid)
+			}
+
+			assert.Equal(t, a.highestBidderId, tt.expectedBidder)
+			for corporationId, credits := range tt.expectedCredits {
+				assert.Equal(t, tc.get(corporationId).credits, credits)
+			}
+		})
+	}
+}
+
+func TestCloseAuctions(t *testing.T) {
+	z, tc := createTestZone(t, map[uint64]*testCorporation{
+		1: {resources: map[string]int{"iron": 200}},
+		2: {credits: 1_000},
+	})
+
+	sold, err := z.createAuction(1, "iron", 100, 100, gameclock.Day, nil)
+	assert.NilError(t, err)
+
+	unsold, err := z.createAuction(1, "iron", 100, 100, gameclock.Day, nil)
+	assert.NilError(t, err)
+
+	assert.NilError(t, z.placeBid(sold, 2, 400, nil))
+
+	// Nothing closes before the end time
+	z.closeAuctions(z.gameClock.GetCurrentTime())
+	assert.Equal(t, len(z.auctions), 2)
+
+	z.closeAuctions(z.gameClock.GetCurrentTime().Add(gameclock.Day))
+	assert.Equal(t, len(z.auctions), 0)
+
+	assert.Equal(t, tc.get(1).credits, 400.0)
+	assert.Equal(t, tc.get(1).resources["iron"], 100)
+	assert.Equal(t, tc.get(2).credits, 600.0)
+	assert.Equal(t, tc.get(2).resources["iron"], 100)
+
+	// Both escrows are empty once the auctions are settled
+	assert.Equal(t, len(z.ledger.Balance(auctionEscrow(sold))), 0)
+	assert.Equal(t, len(z.ledger.Balance(auctionEscrow(unsold))), 0)
+}
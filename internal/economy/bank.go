@@ -0,0 +1,407 @@
+package economy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+const (
+	// baseInterestRate is the monthly rate of the safest corporations
+	baseInterestRate = 0.01
+	// reputationPremium is added to the monthly rate of corporations with the worst reputation
+	reputationPremium = 0.02
+	// leveragePremium is added to the monthly rate of loans as big as the net worth of the corporation
+	leveragePremium = 0.02
+	// defaultPremium is added to the monthly rate for every loan the corporation defaulted on
+	defaultPremium  = 0.01
+	maxInterestRate = 0.1
+	maxReputation   = 100
+	// maxLoanFactor is how much of its net worth a corporation can borrow without collateral
+	maxLoanFactor = 2.0
+	maxLoanMonths = 60
+	// maxMissedPayments is how many payments in a row a corporation can miss before it defaults
+	maxMissedPayments = 3
+	// shipValuePerCargo is the collateral value of a ship for every unit of cargo it can carry at full health
+	shipValuePerCargo = 10.0
+)
+
+// bank lends credits to the corporations. Like the zone markets it runs on its own goroutine and everything else
+// sends it tasks.
+type bank struct {
+	loans        map[string]*loan
+	loanCounter  int
+	creditLines  map[uint64]*creditLine
+	defaults     map[uint64]int
+	prices       resourcePrices
+	gameChannels gamecomm.GameChannels
+	gameClock    *gameclock.GameClock
+	ledger       *ledger.Ledger
+	tasks        chan func(b *bank)
+}
+
+func newBank(gameChannels gamecomm.GameChannels, resources map[string]resource.Resource, gc *gameclock.GameClock, l *ledger.Ledger) *bank {
+	prices := make(resourcePrices, len(resources))
+	for _, r := range resources {
+		prices[r.Name] = r.BasePrice
+	}
+
+	return &bank{
+		loans:        make(map[string]*loan),
+		creditLines:  make(map[uint64]*creditLine),
+		defaults:     make(map[uint64]int),
+		prices:       prices,
+		gameChannels: gameChannels,
+		gameClock:    gc,
+		ledger:       l,
+		tasks:        make(chan func(b *bank), zoneTaskBuffer),
+	}
+}
+
+func (b *bank) run() {
+	for task := range b.tasks {
+		task(b)
+	}
+}
+
+func (b *bank) handle(command gamecomm.EconomyCommand) {
+	defer close(command.ResponseChannel)
+
+	switch command.Action {
+	case gamecomm.GetLoanOffer:
+		offer, err := b.loanOffer(command.CorporationId, command.Credits, command.Months, command.Collateral)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: offer}
+	case gamecomm.TakeLoan:
+		l, err := b.takeLoan(command.CorporationId, command.Credits, command.Months, command.Collateral, command.NotificationChan)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: l}
+	case gamecomm.RepayLoan:
+		balance, err := b.repayLoan(command.LoanId, command.CorporationId, command.Credits)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: balance}
+	case gamecomm.GetLoans:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: b.getLoans(command.CorporationId)}
+	case gamecomm.OpenCreditLine:
+		cl, err := b.openCreditLine(command.CorporationId, command.NotificationChan)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: cl}
+	case gamecomm.DrawCredit:
+		balance, err := b.drawCredit(command.CorporationId, command.Credits)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: balance}
+	case gamecomm.RepayCredit:
+		balance, err := b.repayCredit(command.CorporationId, command.Credits)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: balance}
+	case gamecomm.GetCreditLine:
+		cl, ok := b.creditLines[command.CorporationId]
+		if !ok {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: fmt.Errorf("error: corporation %d has no credit line", command.CorporationId)}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: cl.copy()}
+	default:
+		command.ResponseChannel <- gamecomm.ChanResponse{Err: fmt.Errorf("error: wrong action")}
+	}
+}
+
+// collectPayments takes every monthly payment that is due, payments of months the bank didn't see are collected
+// one after the other
+func (b *bank) collectPayments(now gameclock.GameTime) {
+	for id, l := range b.loans {
+		for !now.Before(l.nextPayment) {
+			if _, ok := b.loans[id]; !ok {
+				break
+			}
+
+			b.collectLoanPayment(l)
+		}
+	}
+
+	for _, cl := range b.creditLines {
+		for !now.Before(cl.nextPayment) {
+			b.collectCreditLinePayment(cl)
+		}
+	}
+}
+
+// debt is what the corporation owes on its loans and its credit line
+func (b *bank) debt(corporationId uint64) float64 {
+	debt := 0.0
+	for _, l := range b.loans {
+		if l.corporationId == corporationId {
+			debt += l.balance
+		}
+	}
+
+	if cl, ok := b.creditLines[corporationId]; ok {
+		debt += cl.balance
+	}
+
+	return debt
+}
+
+// equity is the net worth of the corporation less what it owes the bank, the credits it borrowed don't count
+func (b *bank) equity(corp gamecomm.Corporation) float64 {
+	return corporationNetWorth(corp, b.prices) - b.debt(corp.ID)
+}
+
+// interestRate is the monthly rate for the corporation, the collateral covers part of the risk of the loan
+func (b *bank) interestRate(corp gamecomm.Corporation, netWorth float64, principal float64, coverage float64) float64 {
+	reputation := max(min(corp.Reputation, maxReputation), -maxReputation)
+	reputationRisk := 1 - float64(reputation+maxReputation)/(2*maxReputation)
+
+	leverage := 1.0
+	if netWorth > 0 {
+		leverage = min(principal/netWorth, 1)
+	}
+
+	risk := (reputationPremium*reputationRisk + leveragePremium*leverage) * (1 - min(coverage, 1))
+
+	return min(baseInterestRate+risk+defaultPremium*float64(b.defaults[corp.ID]), maxInterestRate)
+}
+
+// installment is the monthly payment that pays the principal and the interest in the months
+func installment(principal float64, rate float64, months int) float64 {
+	if months <= 0 {
+		return principal
+	}
+
+	if rate == 0 {
+		return principal / float64(months)
+	}
+
+	return principal * rate / (1 - math.Pow(1+rate, -float64(months)))
+}
+
+// collateralValue checks the corporation has the collateral on its bases and squads and returns what it's worth.
+// Squads pledged for another loan can't be pledged again, pledged resources are already off the bases.
+func (b *bank) collateralValue(corporationId uint64, corp gamecomm.Corporation, collateral gamecomm.Collateral) (float64, error) {
+	value := 0.0
+
+	for resourceName, amount := range collateral.Resources {
+		if amount <= 0 {
+			return 0, fmt.Errorf("error: collateral amount should be greater than zero")
+		}
+
+		stored := 0
+		for _, base := range corp.Bases {
+			stored += base.StoredResources[resourceName]
+		}
+
+		if stored < amount {
+			return 0, fmt.Errorf("error: not enough %v on the bases", resourceName)
+		}
+
+		value += float64(amount) * b.prices[resourceName]
+	}
+
+	pledged := b.pledgedSquads(corporationId)
+	for _, squadIndex := range collateral.Squads {
+		if pledged[squadIndex] {
+			return 0, fmt.Errorf("error: squad %v is already pledged", squadIndex)
+		}
+		pledged[squadIndex] = true
+
+		res := sendCorpCommand(b.gameChannels.CorpChannel, gamecomm.CorpCommand{
+			Action:        gamecomm.GetSquad,
+			CorporationId: corporationId,
+			SquadIndex:    squadIndex,
+		})
+		if res.Err != nil {
+			return 0, res.Err
+		}
+
+		squad := res.Val.(gamecomm.Squad)
+		if squad.Ships == (gamecomm.Ship{}) {
+			return 0, fmt.Errorf("error: squad %v has no ship", squadIndex)
+		}
+
+		value += shipValue(squad.Ships)
+	}
+
+	return value, nil
+}
+
+// pledgedSquads returns the squads of the corporation that are collateral of a loan
+func (b *bank) pledgedSquads(corporationId uint64) map[int]bool {
+	pledged := make(map[int]bool)
+	for _, l := range b.loans {
+		if l.corporationId != corporationId {
+			continue
+		}
+
+		for _, squadIndex := range l.collateral.Squads {
+			pledged[squadIndex] = true
+		}
+	}
+
+	return pledged
+}
+
+// pledgeCollateral takes the pledged resources from the bases of the corporation into the collateral escrow of the
+// loan, nothing is taken if the bases don't have all of them
+func (b *bank) pledgeCollateral(loanId string, corporationId uint64, resources map[string]int) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	corp, err := b.getCorporation(corporationId)
+	if err != nil {
+		return err
+	}
+
+	taken := make(map[string]int, len(resources))
+	for resourceName, amount := range resources {
+		for i, base := range corp.Bases {
+			take := min(amount-taken[resourceName], base.StoredResources[resourceName])
+			if take <= 0 {
+				continue
+			}
+
+			err := b.corpCommand(gamecomm.CorpCommand{
+				Action:        gamecomm.RemoveResourcesFromBase,
+				CorporationId: corporationId,
+				BaseIndex:     i,
+				Resource:      resourceName,
+				Amount:        take,
+				Counterparty:  collateralEscrow(loanId),
+				Memo:          "loan collateral pledged",
+			})
+			if err != nil {
+				b.returnCollateral(loanId, corporationId, taken)
+				return err
+			}
+
+			taken[resourceName] += take
+		}
+
+		if taken[resourceName] < amount {
+			b.returnCollateral(loanId, corporationId, taken)
+			return fmt.Errorf("error: not enough %v on the bases", resourceName)
+		}
+	}
+
+	return nil
+}
+
+// returnCollateral gives the resources in the collateral escrow of the loan back to the first base
+func (b *bank) returnCollateral(loanId string, corporationId uint64, resources map[string]int) {
+	for resourceName, amount := range resources {
+		if amount <= 0 {
+			continue
+		}
+
+		err := b.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddResourcesToBase,
+			CorporationId: corporationId,
+			Resource:      resourceName,
+			Amount:        amount,
+			Counterparty:  collateralEscrow(loanId),
+			Memo:          "loan collateral returned",
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+}
+
+func shipValue(ship gamecomm.Ship) float64 {
+	if ship.MaxHealth <= 0 {
+		return 0
+	}
+
+	return float64(ship.MaxCargo) * shipValuePerCargo * float64(ship.ActualHealth) / float64(ship.MaxHealth)
+}
+
+// seizeCollateral takes the resources in the collateral escrow and the ships of the pledged squads, returns what
+// they are worth
+func (b *bank) seizeCollateral(l *loan) float64 {
+	value := 0.0
+
+	for resourceName, amount := range l.collateral.Resources {
+		b.ledger.Transfer("collateral seized", collateralEscrow(l.id), bankAccount, resourceName, float64(amount))
+		value += float64(amount) * b.prices[resourceName]
+	}
+
+	for _, squadIndex := range l.collateral.Squads {
+		res := sendCorpCommand(b.gameChannels.CorpChannel, gamecomm.CorpCommand{
+			Action:        gamecomm.SeizeShip,
+			CorporationId: l.corporationId,
+			SquadIndex:    squadIndex,
+		})
+		if res.Err != nil {
+			continue
+		}
+
+		value += shipValue(res.Val.(gamecomm.Ship))
+	}
+
+	return value
+}
+
+// writeOffLoan drops the loan, seizes its collateral and writes off the balance the collateral doesn't cover.
+// Returns what the collateral was worth.
+func (b *bank) writeOffLoan(l *loan) float64 {
+	delete(b.loans, l.id)
+
+	seized := b.seizeCollateral(l)
+	b.writeOffBalance("loan written off", l.balance-seized)
+
+	return seized
+}
+
+// writeOffBalance books the balance the bank won't get back as bad debt
+func (b *bank) writeOffBalance(memo string, balance float64) {
+	if balance < paidOff {
+		return
+	}
+
+	b.ledger.Transfer(memo, badDebtAccount, bankAccount, gamecomm.CreditsAsset, balance)
+}
+
+func (b *bank) getCorporation(corporationId uint64) (gamecomm.Corporation, error) {
+	res := sendCorpCommand(b.gameChannels.CorpChannel, gamecomm.CorpCommand{
+		Action:        gamecomm.GetCorporation,
+		CorporationId: corporationId,
+	})
+	if res.Err != nil {
+		return gamecomm.Corporation{}, res.Err
+	}
+
+	return res.Val.(gamecomm.Corporation), nil
+}
+
+func (b *bank) corpCommand(command gamecomm.CorpCommand) error {
+	return sendCorpCommand(b.gameChannels.CorpChannel, command).Err
+}
@@ -0,0 +1,345 @@
+package economy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestLoanOffer(t *testing.T) {
+	tests := []struct {
+		name          string
+		corporation   *testCorporation
+		loans         []*loan
+		creditBalance float64
+		collateral    gamecomm.Collateral
+		expectedMax   float64
+		expectedError string
+	}{
+		{
+			name:        "Twice The Net Worth",
+			corporation: &testCorporation{credits: 10_000},
+			expectedMax: 20_000,
+		},
+		{
+			// The borrowed credits are on the corporation but don't count as net worth
+			name:        "Loans Count Against The Limit",
+			corporation: &testCorporation{credits: 20_000},
+			loans: []*loan{
+				{id: "loan-1", corporationId: 1, balance: 10_000},
+			},
+			expectedMax: 10_000,
+		},
+		{
+			name:          "Credit Line Counts Against The Limit",
+			corporation:   &testCorporation{credits: 15_000},
+			creditBalance: 5_000,
+			expectedMax:   15_000,
+		},
+		{
+			name: "Collateral On Every Base",
+			corporation: &testCorporation{
+				credits:    10_000,
+				resources:  map[string]int{"iron": 50},
+				otherBases: []map[string]int{{"iron": 50}},
+			},
+			collateral:  gamecomm.Collateral{Resources: map[string]int{"iron": 100}},
+			expectedMax: 50_000,
+		},
+		{
+			name: "Not Enough Collateral",
+			corporation: &testCorporation{
+				credits:    10_000,
+				resources:  map[string]int{"iron": 50},
+				otherBases: []map[string]int{{"iron": 30}},
+			},
+			collateral:    gamecomm.Collateral{Resources: map[string]int{"iron": 100}},
+			expectedError: "error: not enough iron on the bases",
+		},
+		{
+			name: "Squad Already Pledged",
+			corporation: &testCorporation{
+				credits: 10_000,
+				squads:  []gamecomm.Ship{{MaxCargo: 100, MaxHealth: 100, ActualHealth: 100}},
+			},
+			loans: []*loan{
+				{id: "loan-1", corporationId: 1, collateral: gamecomm.Collateral{Squads: []int{0}}},
+			},
+			collateral:    gamecomm.Collateral{Squads: []int{0}},
+			expectedError: "error: squad 0 is already pledged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := createTestBank(t, map[uint64]*testCorporation{1: tt.corporation})
+
+			for _, l := range tt.loans {
+				b.loans[l.id] = l
+			}
+
+			if tt.creditBalance > 0 {
+				b.creditLines[1] = &creditLine{corporationId: 1, limit: tt.creditBalance, balance: tt.creditBalance}
+			}
+
+			if tt.expectedError != "" {
+				_, err := b.loanOffer(1, 1_000, 12, tt.collateral)
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			offer, err := b.loanOffer(1, tt.expectedMax, 12, tt.collateral)
+			assert.NilError(t, err)
+			assert.Equal(t, offer.Principal, tt.expectedMax)
+
+			_, err = b.loanOffer(1, tt.expectedMax+1, 12, tt.collateral)
+			assert.Error(t, err)
+			assert.StringContains(t, err.Error(), fmt.Sprintf("error: the bank lends at most %.2f credits", tt.expectedMax))
+		})
+	}
+}
+
+func TestTakeLoan(t *testing.T) {
+	b, tc := createTestBank(t, map[uint64]*testCorporation{
+		1: {
+			credits:    10_000,
+			resources:  map[string]int{"iron": 50},
+			otherBases: []map[string]int{{"iron": 50}},
+		},
+	})
+
+	l, err := b.takeLoan(1, 10_000, 12, gamecomm.Collateral{Resources: map[string]int{"iron": 80}}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, l.Id, "loan-1")
+	assert.Equal(t, tc.get(1).credits, 20_000.0)
+
+	// The collateral is taken from every base and held until the loan is paid
+	assert.Equal(t, tc.get(1).resources["iron"], 0)
+	assert.Equal(t, tc.get(1).otherBases[0]["iron"], 20)
+	assert.Equal(t, b.ledger.Balance(collateralEscrow(l.Id))["iron"], 80.0)
+
+	// The borrowed credits don't count as net worth and the pledged iron is held by the bank, the corporation
+	// can only owe twice the 12,000 credits left after the loan
+	_, err = b.takeLoan(1, 14_001, 12, gamecomm.Collateral{}, nil)
+	assert.Error(t, err)
+	assert.StringContains(t, err.Error(), "error: the bank lends at most 14000.00 credits")
+
+	balance, err := b.repayLoan(l.Id, 1, 10_000)
+	assert.NilError(t, err)
+	assert.Equal(t, balance, 0.0)
+
+	// Paying the loan gives the collateral back
+	assert.Equal(t, len(b.loans), 0)
+	assert.Equal(t, tc.get(1).credits, 10_000.0)
+	assert.Equal(t, tc.get(1).resources["iron"], 80)
+	assert.Equal(t, len(b.ledger.Balance(collateralEscrow(l.Id))), 0)
+}
+
+func TestTakeLoanReturnsCollateral(t *testing.T) {
+	b, tc := createTestBank(t, map[uint64]*testCorporation{
+		1: {credits: 10_000, resources: map[string]int{"iron": 100}},
+	})
+	tc.errors = map[gamecomm.CommandType]error{gamecomm.AddCredits: fmt.Errorf("error: corporation is frozen")}
+
+	_, err := b.takeLoan(1, 5_000, 12, gamecomm.Collateral{Resources: map[string]int{"iron": 100}}, nil)
+	assert.Error(t, err)
+
+	tc.errors = nil
+
+	// The pledged resources go back when the credits can't be given
+	assert.Equal(t, len(b.loans), 0)
+	assert.Equal(t, tc.get(1).resources["iron"], 100)
+	assert.Equal(t, len(b.ledger.Balance(collateralEscrow("loan-1"))), 0)
+}
+
+func TestCollectLoanPayments(t *testing.T) {
+	tests := []struct {
+		name            string
+		credits         float64
+		now             gameclock.GameTime
+		expectedLoan    bool
+		expectedLeft    int
+		expectedMissed  int
+		expectedCredits float64
+		expectedBadDebt float64
+	}{
+		{
+			name:            "Not Due",
+			credits:         1_000,
+			now:             gameclock.Month - 1,
+			expectedLoan:    true,
+			expectedLeft:    12,
+			expectedCredits: 1_000,
+		},
+		{
+			// The bank didn't see the first month so both payments are taken
+			name:            "Payments Missed By The Clock",
+			credits:         1_000,
+			now:             2 * gameclock.Month,
+			expectedLoan:    true,
+			expectedLeft:    10,
+			expectedCredits: 800,
+		},
+		{
+			name:            "Missed Payment",
+			credits:         50,
+			now:             gameclock.Month,
+			expectedLoan:    true,
+			expectedLeft:    12,
+			expectedMissed:  1,
+			expectedCredits: 50,
+		},
+		{
+			name:            "Default",
+			credits:         50,
+			now:             3 * gameclock.Month,
+			expectedCredits: 50,
+			// Three months of interest were added to the balance and the iron covers 500 credits
+			expectedBadDebt: func() float64 {
+				balance := 1_000.0
+				for i := 0; i < 3; i++ {
+					balance += balance * 0.01
+				}
+
+				return balance - 500
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, tc := createTestBank(t, map[uint64]*testCorporation{
+				1: {credits: tt.credits, resources: map[string]int{"iron": 5}},
+			})
+
+			err := b.pledgeCollateral("loan-1", 1, map[string]int{"iron": 5})
+			assert.NilError(t, err)
+
+			l := &loan{
+				id:            "loan-1",
+				corporationId: 1,
+				principal:     1_000,
+				balance:       1_000,
+				rate:          0.01,
+				payment:       100,
+				paymentsLeft:  12,
+				nextPayment:   gameclock.Month,
+				collateral:    gamecomm.Collateral{Resources: map[string]int{"iron": 5}},
+			}
+			b.loans[l.id] = l
+
+			b.collectPayments(tt.now)
+
+			_, ok := b.loans[l.id]
+			assert.Equal(t, ok, tt.expectedLoan)
+			assert.Equal(t, tc.get(1).credits, tt.expectedCredits)
+
+			if tt.expectedLoan {
+				assert.Equal(t, l.paymentsLeft, tt.expectedLeft)
+				assert.Equal(t, l.missedPayments, tt.expectedMissed)
+				assert.Equal(t, b.defaults[1], 0)
+				return
+			}
+
+			// The bank keeps the collateral and books what it doesn't cover as bad debt
+			assert.Equal(t, b.defaults[1], 1)
+			assert.Equal(t, tc.get(1).resources["iron"], 0)
+			assert.Equal(t, len(b.ledger.Balance(collateralEscrow(l.id))), 0)
+			assert.Equal(t, b.ledger.Balance(bankAccount)["iron"], 5.0)
+			assert.Equal(t, b.ledger.Balance(badDebtAccount)[gamecomm.CreditsAsset], -tt.expectedBadDebt)
+		})
+	}
+}
+
+func TestCreditLine(t *testing.T) {
+	b, tc := createTestBank(t, map[uint64]*testCorporation{
+		1: {credits: 10_000},
+	})
+
+	cl, err := b.openCreditLine(1, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, cl.Limit, 2_500.0)
+
+	_, err = b.drawCredit(1, 3_000)
+	assert.Error(t, err)
+	assert.StringContains(t, err.Error(), "error: only 2500.00 credits left on the credit line")
+
+	balance, err := b.drawCredit(1, 2_500)
+	assert.NilError(t, err)
+	assert.Equal(t, balance, 2_500.0)
+	assert.Equal(t, tc.get(1).credits, 12_500.0)
+
+	// The drawn credits don't raise the limit
+	cl, err = b.openCreditLine(1, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, cl.Limit, 2_500.0)
+
+	// Neither does a richer corporation while there is a balance
+	tc.corporations[1].credits += 10_000
+	cl, err = b.openCreditLine(1, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, cl.Limit, 2_500.0)
+
+	balance, err = b.repayCredit(1, 2_500)
+	assert.NilError(t, err)
+	assert.Equal(t, balance, 0.0)
+
+	cl, err = b.openCreditLine(1, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, cl.Limit, 5_000.0)
+}
+
+func TestCreditLineMissedPayments(t *testing.T) {
+	b, tc := createTestBank(t, map[uint64]*testCorporation{
+		1: {credits: 10_000},
+	})
+
+	_, err := b.openCreditLine(1, nil)
+	assert.NilError(t, err)
+
+	_, err = b.drawCredit(1, 2_000)
+	assert.NilError(t, err)
+
+	tc.corporations[1].credits = 0
+
+	b.collectPayments(3 * gameclock.Month)
+
+	cl := b.creditLines[1]
+	assert.Equal(t, cl.missedPayments, maxMissedPayments)
+	assert.Equal(t, cl.frozen, true)
+	assert.Equal(t, b.defaults[1], 1)
+
+	_, err = b.drawCredit(1, 100)
+	assert.Error(t, err)
+	assert.StringContains(t, err.Error(), "error: credit line is frozen")
+}
+
+func TestWriteOff(t *testing.T) {
+	b, tc := createTestBank(t, map[uint64]*testCorporation{
+		1: {credits: 10_000, resources: map[string]int{"iron": 10}},
+	})
+
+	l, err := b.takeLoan(1, 5_000, 12, gamecomm.Collateral{Resources: map[string]int{"iron": 10}}, nil)
+	assert.NilError(t, err)
+
+	_, err = b.openCreditLine(1, nil)
+	assert.NilError(t, err)
+
+	_, err = b.drawCredit(1, 500)
+	assert.NilError(t, err)
+
+	b.writeOff(1)
+
+	assert.Equal(t, len(b.loans), 0)
+	assert.Equal(t, len(b.creditLines), 0)
+	assert.Equal(t, b.defaults[1], 1)
+	assert.Equal(t, tc.get(1).resources["iron"], 0)
+	assert.Equal(t, len(b.ledger.Balance(collateralEscrow(l.Id))), 0)
+
+	// The loan less the seized iron and the credit line balance are bad debt
+	assert.Equal(t, b.ledger.Balance(badDebtAccount)[gamecomm.CreditsAsset], -4_500.0)
+}
@@ -0,0 +1,188 @@
+package economy
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// creditLineFactor is the share of the net worth a corporation can draw from its credit line
+	creditLineFactor = 0.5
+	// creditLinePremium is added to the monthly rate of credit lines, they have no collateral
+	creditLinePremium = 0.005
+	// minPaymentRate is the share of the balance paid every month
+	minPaymentRate = 0.1
+)
+
+// creditLine lets a corporation draw credits up to the limit whenever it needs them. A credit line that misses too
+// many payments is frozen until it's paid.
+type creditLine struct {
+	corporationId    uint64
+	limit            float64
+	balance          float64
+	rate             float64
+	missedPayments   int
+	nextPayment      gameclock.GameTime
+	frozen           bool
+	notificationChan chan string
+}
+
+func (cl *creditLine) copy() gamecomm.CreditLine {
+	return gamecomm.CreditLine{
+		CorporationId:  cl.corporationId,
+		Limit:          cl.limit,
+		Balance:        cl.balance,
+		Rate:           cl.rate,
+		MissedPayments: cl.missedPayments,
+		NextPayment:    cl.nextPayment,
+		Frozen:         cl.frozen,
+	}
+}
+
+// openCreditLine opens a credit line or reviews the limit and rate of the one the corporation has. The limit comes
+// from the net worth after debts and a review can't raise it while there is a balance to pay.
+func (b *bank) openCreditLine(corporationId uint64, notificationChan chan string) (gamecomm.CreditLine, error) {
+	cl, ok := b.creditLines[corporationId]
+	if ok && cl.frozen {
+		return gamecomm.CreditLine{}, fmt.Errorf("error: credit line is frozen until the balance is paid")
+	}
+
+	corp, err := b.getCorporation(corporationId)
+	if err != nil {
+		return gamecomm.CreditLine{}, err
+	}
+
+	equity := b.equity(corp)
+
+	reputation := max(min(corp.Reputation, maxReputation), -maxReputation)
+	limit := creditLineFactor * equity * float64(reputation+maxReputation) / (2 * maxReputation)
+	if limit <= 0 {
+		return gamecomm.CreditLine{}, fmt.Errorf("error: the net worth of the corporation is too low for a credit line")
+	}
+
+	if ok && cl.balance >= paidOff {
+		limit = min(limit, cl.limit)
+	}
+
+	if !ok {
+		cl = &creditLine{
+			corporationId: corporationId,
+			nextPayment:   b.gameClock.GetCurrentTime().Add(gameclock.Month),
+		}
+		b.creditLines[corporationId] = cl
+	}
+
+	cl.limit = limit
+	cl.rate = min(b.interestRate(corp, equity, limit, 0)+creditLinePremium, maxInterestRate)
+	cl.notificationChan = notificationChan
+
+	return cl.copy(), nil
+}
+
+// drawCredit gives the credits to the corporation and returns the balance of the credit line
+func (b *bank) drawCredit(corporationId uint64, credits float64) (float64, error) {
+	cl, ok := b.creditLines[corporationId]
+	if !ok {
+		return 0, fmt.Errorf("error: corporation %d has no credit line", corporationId)
+	}
+
+	if cl.frozen {
+		return 0, fmt.Errorf("error: credit line is frozen until the balance is paid")
+	}
+
+	if credits <= 0 {
+		return 0, fmt.Errorf("error: credits should be greater than zero")
+	}
+
+	if cl.balance+credits > cl.limit {
+		return 0, fmt.Errorf("error: only %.2f credits left on the credit line", cl.limit-cl.balance)
+	}
+
+	err := b.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.AddCredits,
+		CorporationId: corporationId,
+		AmountDecimal: credits,
+		Counterparty:  bankAccount,
+		Memo:          "credit line draw",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cl.balance += credits
+
+	return cl.balance, nil
+}
+
+// repayCredit pays part of the balance of the credit line and returns what is left, paying all of it unfreezes the
+// credit line
+func (b *bank) repayCredit(corporationId uint64, credits float64) (float64, error) {
+	cl, ok := b.creditLines[corporationId]
+	if !ok {
+		return 0, fmt.Errorf("error: corporation %d has no credit line", corporationId)
+	}
+
+	if credits <= 0 {
+		return 0, fmt.Errorf("error: credits should be greater than zero")
+	}
+
+	amount := min(credits, cl.balance)
+
+	err := b.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveCredits,
+		CorporationId: corporationId,
+		AmountDecimal: amount,
+		Counterparty:  bankAccount,
+		Memo:          "credit line repayment",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cl.balance -= amount
+	if cl.balance < paidOff {
+		cl.balance = 0
+		cl.frozen = false
+		cl.missedPayments = 0
+	}
+
+	return cl.balance, nil
+}
+
+// collectCreditLinePayment adds the interest of the month and takes part of the balance
+func (b *bank) collectCreditLinePayment(cl *creditLine) {
+	cl.nextPayment = cl.nextPayment.Add(gameclock.Month)
+
+	if cl.balance < paidOff {
+		return
+	}
+
+	cl.balance += cl.balance * cl.rate
+	due := cl.balance * minPaymentRate
+
+	err := b.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveCredits,
+		CorporationId: cl.corporationId,
+		AmountDecimal: due,
+		Counterparty:  bankAccount,
+		Memo:          "credit line payment",
+	})
+	if err != nil {
+		cl.missedPayments++
+
+		if cl.missedPayments >= maxMissedPayments && !cl.frozen {
+			cl.frozen = true
+			b.defaults[cl.corporationId]++
+			notify(cl.notificationChan, fmt.Sprintf("Bank Notification: credit line frozen after %d missed payments", cl.missedPayments))
+			return
+		}
+
+		notify(cl.notificationChan, fmt.Sprintf("Bank Notification: missed credit line payment of %.2f credits", due))
+		return
+	}
+
+	cl.balance -= due
+	cl.missedPayments = 0
+}
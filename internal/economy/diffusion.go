@@ -0,0 +1,141 @@
+package economy
+
+import (
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// diffusionRate is the share of the price gap with the neighbouring zones that closes every day
+	diffusionRate = 0.02
+	// diffusionDistance is the distance at which a zone has half of the influence of a zone next to it
+	diffusionDistance = 1_000.0
+	// tradeFlowDays is how many days back the trade flow is measured
+	tradeFlowDays = 7
+	// tradeFlowScale is the traded amount at which arbitrage doubles the diffusion rate
+	tradeFlowScale = 100_000.0
+	// defaultSpreadsLimit is the number of spreads returned when no limit is given
+	defaultSpreadsLimit = 10
+)
+
+// diffusePrices returns the prices of every zone moved towards the prices of the other zones, closer zones have more
+// influence and resources traded more in the zones close their gaps faster
+func diffusePrices(snapshots map[string]zoneSnapshot) map[string]resourcePrices {
+	diffused := make(map[string]resourcePrices, len(snapshots))
+
+	for zoneId, zone := range snapshots {
+		prices := make(resourcePrices, len(zone.prices))
+
+		for name, price := range zone.prices {
+			totalWeight := 0.0
+			weightedGap := 0.0
+			arbitrage := 0.0
+
+			for otherZoneId, other := range snapshots {
+				if otherZoneId == zoneId {
+					continue
+				}
+
+				otherPrice, ok := other.prices[name]
+				if !ok {
+					continue
+				}
+
+				weight := 1 / (1 + gamecomm.Distance(zone.location, other.location)/diffusionDistance)
+
+				totalWeight += weight
+				weightedGap += weight * (otherPrice - price)
+				arbitrage += weight * float64(other.flows[name])
+			}
+
+			prices[name] = price
+			if totalWeight == 0 {
+				continue
+			}
+
+			flow := float64(zone.flows[name]) + arbitrage/totalWeight
+			rate := diffusionRate * (1 + min(flow/tradeFlowScale, 1))
+
+			prices[name] = price + rate*weightedGap/totalWeight
+		}
+
+		diffused[zoneId] = prices
+	}
+
+	return diffused
+}
+
+// tradeFlows returns the amount traded of every resource in the zone in the last days
+func (z *zoneMarket) tradeFlows(day gameclock.GameTime) resourceVolume {
+	flows := make(resourceVolume)
+
+	var since gameclock.GameTime
+	if day > tradeFlowDays*gameclock.Day {
+		since = day - tradeFlowDays*gameclock.Day
+	}
+
+	for i := len(z.transactions) - 1; i >= 0; i-- {
+		t := z.transactions[i]
+		if t.time.Before(since) {
+			break
+		}
+
+		flows[t.resource] += t.amount
+	}
+
+	return flows
+}
+
+// priceSpreads returns the biggest price differences of a resource between two zones
+func priceSpreads(snapshots map[string]zoneSnapshot, limit int) []gamecomm.PriceSpread {
+	if limit <= 0 {
+		limit = defaultSpreadsLimit
+	}
+
+	zoneIds := make([]string, 0, len(snapshots))
+	for zoneId := range snapshots {
+		zoneIds = append(zoneIds, zoneId)
+	}
+
+	spreads := []gamecomm.PriceSpread{}
+	for i, zoneId := range zoneIds {
+		zone := snapshots[zoneId]
+
+		for _, otherZoneId := range zoneIds[i+1:] {
+			other := snapshots[otherZoneId]
+
+			for name, price := range zone.prices {
+				otherPrice, ok := other.prices[name]
+				if !ok || otherPrice == price {
+					continue
+				}
+
+				spread := gamecomm.PriceSpread{
+					Resource:   name,
+					LowZoneId:  zoneId,
+					LowPrice:   price,
+					HighZoneId: otherZoneId,
+					HighPrice:  otherPrice,
+				}
+
+				if otherPrice < price {
+					spread.LowZoneId, spread.HighZoneId = otherZoneId, zoneId
+					spread.LowPrice, spread.HighPrice = otherPrice, price
+				}
+
+				spread.Spread = spread.HighPrice - spread.LowPrice
+				spread.Distance = gamecomm.Distance(zone.location, other.location)
+
+				spreads = append(spreads, spread)
+			}
+		}
+	}
+
+	sort.Slice(spreads, func(i, j int) bool {
+		return spreads[i].Spread > spreads[j].Spread
+	})
+
+	return spreads[:min(limit, len(spreads))]
+}
@@ -0,0 +1,126 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestDiffusePrices(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshots map[string]zoneSnapshot
+		wants     map[string]resourcePrices
+	}{
+		{
+			name: "Prices Move Towards Each Other",
+			snapshots: map[string]zoneSnapshot{
+				"Zone-1": {prices: resourcePrices{"iron": 100}},
+				"Zone-2": {prices: resourcePrices{"iron": 200}},
+			},
+			wants: map[string]resourcePrices{
+				"Zone-1": {"iron": 100 + diffusionRate*100},
+				"Zone-2": {"iron": 200 - diffusionRate*100},
+			},
+		},
+		{
+			// Zone-3 is at the half influence distance of Zone-1
+			name: "Far Zones Weigh Less",
+			snapshots: map[string]zoneSnapshot{
+				"Zone-1": {prices: resourcePrices{"iron": 100}},
+				"Zone-2": {prices: resourcePrices{"iron": 200}},
+				"Zone-3": {location: gamecomm.Coordinates{X: diffusionDistance}, prices: resourcePrices{"iron": 50}},
+			},
+			wants: map[string]resourcePrices{
+				"Zone-1": {"iron": 100 + diffusionRate*(100-0.5*50)/1.5},
+				"Zone-2": {"iron": 200 + diffusionRate*(-100-0.5*150)/1.5},
+				"Zone-3": {"iron": 50 + diffusionRate*(0.5*50+0.5*150)},
+			},
+		},
+		{
+			// The traded zone and the zones trading with it close the gap twice as fast
+			name: "Trade Flows Speed It Up",
+			snapshots: map[string]zoneSnapshot{
+				"Zone-1": {prices: resourcePrices{"iron": 100}, flows: resourceVolume{"iron": tradeFlowScale}},
+				"Zone-2": {prices: resourcePrices{"iron": 200}},
+			},
+			wants: map[string]resourcePrices{
+				"Zone-1": {"iron": 100 + 2*diffusionRate*100},
+				"Zone-2": {"iron": 200 - 2*diffusionRate*100},
+			},
+		},
+		{
+			name: "Resources Only Traded In One Zone",
+			snapshots: map[string]zoneSnapshot{
+				"Zone-1": {prices: resourcePrices{"iron": 100, "water": 10}},
+				"Zone-2": {prices: resourcePrices{"iron": 100}},
+			},
+			wants: map[string]resourcePrices{
+				"Zone-1": {"iron": 100, "water": 10},
+				"Zone-2": {"iron": 100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffused := diffusePrices(tt.snapshots)
+
+			assert.Equal(t, len(diffused), len(tt.wants))
+			for zoneId, prices := range tt.wants {
+				assert.Equal(t, len(diffused[zoneId]), len(prices))
+				for name, price := range prices {
+					assert.Equal(t, diffused[zoneId][name], price)
+				}
+			}
+		})
+	}
+}
+
+func TestTradeFlows(t *testing.T) {
+	z, _ := createTestZone(t, map[uint64]*testCorporation{})
+
+	z.transactions = []transaction{
+		{resource: "iron", amount: 50, time: 2 * gameclock.Day},
+		{resource: "iron", amount: 20, time: 4 * gameclock.Day},
+		{resource: "water", amount: 10, time: 9 * gameclock.Day},
+		{resource: "iron", amount: 5, time: 10 * gameclock.Day},
+	}
+
+	// Only the last tradeFlowDays count
+	flows := z.tradeFlows(10 * gameclock.Day)
+
+	assert.Equal(t, flows["iron"], 25)
+	assert.Equal(t, flows["water"], 10)
+}
+
+func TestPriceSpreads(t *testing.T) {
+	snapshots := map[string]zoneSnapshot{
+		"Zone-1": {prices: resourcePrices{"iron": 100, "water": 10}},
+		"Zone-2": {location: gamecomm.Coordinates{X: 3, Y: 4}, prices: resourcePrices{"iron": 150, "water": 10}},
+		"Zone-3": {prices: resourcePrices{"iron": 130}},
+	}
+
+	spreads := priceSpreads(snapshots, 0)
+
+	// Equal prices make no spread
+	assert.Equal(t, len(spreads), 3)
+
+	assert.Equal(t, spreads[0].Resource, "iron")
+	assert.Equal(t, spreads[0].LowZoneId, "Zone-1")
+	assert.Equal(t, spreads[0].HighZoneId, "Zone-2")
+	assert.Equal(t, spreads[0].Spread, 50.0)
+	assert.Equal(t, spreads[0].Distance, 5.0)
+
+	assert.Equal(t, spreads[1].LowZoneId, "Zone-1")
+	assert.Equal(t, spreads[1].HighZoneId, "Zone-3")
+	assert.Equal(t, spreads[1].Spread, 30.0)
+
+	assert.Equal(t, spreads[2].LowZoneId, "Zone-3")
+	assert.Equal(t, spreads[2].HighZoneId, "Zone-2")
+	assert.Equal(t, spreads[2].Spread, 20.0)
+
+	assert.Equal(t, len(priceSpreads(snapshots, 1)), 1)
+}
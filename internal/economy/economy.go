@@ -1,10 +1,9 @@
 package economy
 
 import (
-	"sync"
-
 	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
 	"github.com/luisya22/galactic-exchange/internal/resource"
 )
 
@@ -14,26 +13,25 @@ import (
 // Communicate with World to check planet events or emergencies
 // Calculate current market trends
 
+// transactionLimit is the number of transactions every zone keeps
+const transactionLimit = 100_000
+
 type resourcePrices map[string]float64
 
+// Economy routes every command to the market of its zone. Each zone market runs on its own goroutine and owns its
+// listings, analytics and prices, so zones never lock each other.
 type Economy struct {
-	transactions                   []transaction
-	zoneTransactions               map[string][]int
-	planetTransactions             map[string][]int
 	corporationPlanetTradeRelation map[uint64]int
 	corporationContracts           map[uint64][]int
 	gameChannels                   gamecomm.GameChannels
-	Workers                        int
-	rw                             sync.RWMutex
-	limit                          int
-	marketListings                 map[string]*[]MarketListing
-	zoneMarketListingCounter       map[string]int
-	zoneMutexes                    map[string]*sync.RWMutex
 	resources                      map[string]resource.Resource
 	gameClock                      *gameclock.GameClock
-	resourcePrices                 map[string]resourcePrices
-	zoneAnalytics                  zoneAnalytics
+	zones                          map[string]*zoneMarket
+	exchange                       *stockExchange
+	bank                           *bank
+	corporationEvents              chan gamecomm.CorporationEvent
 	newDayChan                     chan gameclock.GameTime
+	newHourChan                    chan gameclock.GameTime
 }
 
 // type contract struct {
@@ -45,56 +43,44 @@ type Economy struct {
 // 	endTime       gameclock.GameTime
 // }
 
-func NewEconomy(gameChannels gamecomm.GameChannels, resources map[string]resource.Resource, zoneIds []string, gc *gameclock.GameClock) *Economy {
-
-	sellOffers := make(map[string]*[]MarketListing, len(zoneIds))
-	zoneSellOfferCounter := make(map[string]int, len(zoneIds))
-	zoneMutexes := make(map[string]*sync.RWMutex, len(zoneIds))
-	zoneAnalytics := make(zoneAnalytics, len(zoneIds))
-	rp := make(map[string]resourcePrices, len(zoneIds))
-
-	for _, zoneId := range zoneIds {
-		sellOffers[zoneId] = &[]MarketListing{}
-		zoneSellOfferCounter[zoneId] = 0
-		zoneMutexes[zoneId] = new(sync.RWMutex)
-		zoneAnalytics[zoneId] = newAnalytics()
+func NewEconomy(gameChannels gamecomm.GameChannels, resources map[string]resource.Resource, zoneLocations map[string]gamecomm.Coordinates, gc *gameclock.GameClock, l *ledger.Ledger) *Economy {
 
-		// TODO: Optimize
-		prices := make(resourcePrices)
-		for _, r := range resources {
-			prices[r.Name] = r.BasePrice
-		}
-
-		rp[zoneId] = prices
+	zones := make(map[string]*zoneMarket, len(zoneLocations))
+	for zoneId, location := range zoneLocations {
+		zones[zoneId] = newZoneMarket(zoneId, location, gameChannels, resources, gc, l)
 	}
 
 	return &Economy{
-		transactions:                   []transaction{},
-		zoneTransactions:               make(map[string][]int),
-		planetTransactions:             make(map[string][]int),
 		corporationPlanetTradeRelation: make(map[uint64]int),
 		corporationContracts:           make(map[uint64][]int),
 		gameChannels:                   gameChannels,
-		marketListings:                 sellOffers,
-		zoneMarketListingCounter:       zoneSellOfferCounter,
-		zoneMutexes:                    zoneMutexes,
 		resources:                      resources,
 		gameClock:                      gc,
-		zoneAnalytics:                  zoneAnalytics,
-		resourcePrices:                 rp,
+		zones:                          zones,
+		exchange:                       newStockExchange(gameChannels),
+		bank:                           newBank(gameChannels, resources, gc, l),
+		corporationEvents:              make(chan gamecomm.CorporationEvent, zoneTaskBuffer),
 		newDayChan:                     make(chan gameclock.GameTime),
-		Workers:                        10,
+		newHourChan:                    make(chan gameclock.GameTime),
 	}
 }
 
 func (e *Economy) Run() {
 	economyChannel := e.gameChannels.EconomyChannel
 
+	for _, z := range e.zones {
+		go z.run()
+	}
+
+	go e.exchange.run()
+	go e.bank.run()
 	go e.listen()
+	go e.handleCorporationEvents()
 	go e.priceUpdate()
+	go e.hourlySettlement()
 
 	zoneIds := []string{}
-	for z := range e.zoneMutexes {
+	for z := range e.zones {
 		zoneIds = append(zoneIds, z)
 	}
 
@@ -108,26 +94,79 @@ func (e *Economy) Run() {
 
 }
 
+// priceUpdate updates the prices of every zone with the trades of the previous day and then moves them towards the
+// prices of the other zones. The share prices and the bank follow with the new resource prices.
 func (e *Economy) priceUpdate() {
 	e.gameClock.Subscribe(e.newDayChan)
 
 	for newDayTime := range e.newDayChan {
+		newDayTime := newDayTime
 		previousDay := newDayTime.PreviousDay()
-		for zoneId, a := range e.zoneAnalytics {
 
-			e.rw.Lock()
-			a.rw.Lock()
-			e.resourcePrices[zoneId] = a.updateItemPrices(e.resources, e.resourcePrices[zoneId], previousDay)
-			a.rw.Unlock()
-			e.rw.Unlock()
+		snapshots := e.collectSnapshots(func(z *zoneMarket) {
+			z.updatePrices(previousDay)
+		}, newDayTime)
+
+		diffusedPrices := diffusePrices(snapshots)
+
+		for zoneId, z := range e.zones {
+			prices := diffusedPrices[zoneId]
+			z.tasks <- func(z *zoneMarket) {
+				z.prices = prices
+				z.analytics.storeHistoricPrices(z.resources, z.prices, newDayTime)
+				z.markToMarket(newDayTime)
+			}
+		}
+
+		averages := averagePrices(diffusedPrices)
+		e.exchange.tasks <- func(x *stockExchange) {
+			x.updateSharePrices(averages)
+		}
+
+		e.bank.tasks <- func(b *bank) {
+			b.prices = averages
+		}
+	}
+
+}
+
+// hourlySettlement settles every hour the auctions that ended and the bank payments that are due. The clock drops
+// ticks while the economy is busy, payments missed by a dropped tick are taken on the next one.
+func (e *Economy) hourlySettlement() {
+	e.gameClock.SubscribeHour(e.newHourChan)
+
+	for now := range e.newHourChan {
+		now := now
+		for _, z := range e.zones {
+			z.tasks <- func(z *zoneMarket) {
+				z.closeAuctions(now)
+			}
+		}
+
+		e.bank.tasks <- func(b *bank) {
+			b.collectPayments(now)
+		}
+	}
+}
+
+// collectSnapshots runs the task on every zone and returns the state of the zones after it
+func (e *Economy) collectSnapshots(task func(z *zoneMarket), day gameclock.GameTime) map[string]zoneSnapshot {
+	snapshotChan := make(chan zoneSnapshot, len(e.zones))
 
-			a.rw.Lock()
-			a.storeHistoricPrices(e.resources, e.resourcePrices[zoneId], newDayTime)
-			a.rw.Unlock()
+	for _, z := range e.zones {
+		z.tasks <- func(z *zoneMarket) {
+			task(z)
+			snapshotChan <- z.snapshot(day)
 		}
+	}
 
+	snapshots := make(map[string]zoneSnapshot, len(e.zones))
+	for range e.zones {
+		s := <-snapshotChan
+		snapshots[s.zoneId] = s
 	}
 
+	return snapshots
 }
 
 // Save contracts and existing trades between Corporation and Planets
@@ -161,10 +200,5 @@ func (e *Economy) priceUpdate() {
 
 // TODO: acceptContract
 
-// TODO: Calculate Resource Prices Projections
-// func (e *Economy) calculateProjections(){
-// }
-
-// TODO: Per zone marketplace
 // TODO: Player and NPCs would set prices and planets would buy
 // TODO: Planets would analyze and score every offer available and buy
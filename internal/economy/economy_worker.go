@@ -2,24 +2,77 @@ package economy
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
 	"github.com/luisya22/galactic-exchange/internal/resource"
 )
 
+// listen sends every command to the market of its zone, share commands to the exchange and loan commands to the
+// bank, commands over every zone run on their own goroutine
 func (e *Economy) listen() {
-	for i := 0; i < e.Workers; i++ {
-		go e.worker(e.gameChannels.EconomyChannel)
+	for command := range e.gameChannels.EconomyChannel {
+		switch command.Action {
+		case gamecomm.GetPriceSpreads:
+			go e.sendPriceSpreads(command)
+			continue
+		case gamecomm.LiquidateAssets:
+			go e.liquidateAssets(command)
+			continue
+		case gamecomm.IssueShares, gamecomm.PlaceShareOrder, gamecomm.CancelShareOrder, gamecomm.PayDividend, gamecomm.GetStocks, gamecomm.GetPortfolio:
+			command := command
+			e.exchange.tasks <- func(x *stockExchange) {
+				x.handle(command)
+			}
+			continue
+		case gamecomm.GetLoanOffer, gamecomm.TakeLoan, gamecomm.RepayLoan, gamecomm.GetLoans, gamecomm.OpenCreditLine, gamecomm.DrawCredit, gamecomm.RepayCredit, gamecomm.GetCreditLine:
+			command := command
+			e.bank.tasks <- func(b *bank) {
+				b.handle(command)
+			}
+			continue
+		}
+
+		z, ok := e.zones[command.ZoneId]
+		if !ok {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: fmt.Errorf("error: zone not found '%s'", command.ZoneId)}
+			close(command.ResponseChannel)
+			continue
+		}
+
+		command := command
+		z.tasks <- func(z *zoneMarket) {
+			z.handle(command)
+		}
 	}
 }
 
+func (e *Economy) sendPriceSpreads(command gamecomm.EconomyCommand) {
+	snapshots := e.collectSnapshots(func(z *zoneMarket) {}, e.gameClock.GetCurrentTime())
+
+	command.ResponseChannel <- gamecomm.ChanResponse{Val: priceSpreads(snapshots, command.Amount)}
+	close(command.ResponseChannel)
+}
+
 func (e *Economy) addRandomMarketListings(resources []resource.Resource, zoneIds []string, economyChannel chan gamecomm.EconomyCommand) {
 
 	for x := 0; x < 100; x++ {
 		for _, z := range zoneIds {
 			for _, r := range resources {
+				// The listings take the goods from the base
+				res := sendCorpCommand(e.gameChannels.CorpChannel, gamecomm.CorpCommand{
+					Action:        gamecomm.AddResourcesToBase,
+					CorporationId: 1,
+					Resource:      r.Name,
+					Amount:        100_000,
+					Counterparty:  seedAccount,
+					Memo:          "market seed",
+				})
+				if res.Err != nil {
+					fmt.Println(res.Err.Error())
+					continue
+				}
+
 				resChan := make(chan gamecomm.ChanResponse)
 				command := gamecomm.EconomyCommand{
 					Action:          gamecomm.AddMarketListing,
@@ -33,7 +86,7 @@ func (e *Economy) addRandomMarketListings(resources []resource.Resource, zoneIds
 
 				economyChannel <- command
 
-				res := <-resChan
+				res = <-resChan
 				if res.Err != nil {
 					fmt.Println(res.Err.Error())
 				}
@@ -44,110 +97,3 @@ func (e *Economy) addRandomMarketListings(resources []resource.Resource, zoneIds
 	}
 
 }
-
-// TODO: add WaitGroup to all the workers
-// TODO: Test
-func (e *Economy) worker(ch <-chan gamecomm.EconomyCommand) {
-	for command := range ch {
-
-		listingTime := e.gameClock.GetCurrentTime()
-
-		switch command.Action {
-		case gamecomm.AddMarketListing:
-			so := MarketListing{
-				ResourceName:  command.Resource,
-				Amount:        command.Amount,
-				Price:         command.Price,
-				CorporationId: command.CorporationId,
-				ListTime:      listingTime,
-			}
-
-			id, err := e.addMarketListing(command.ZoneId, so)
-			if err != nil {
-				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
-				close(command.ResponseChannel)
-				return
-			}
-
-			// if _, ok := e.zoneAnalytics[command.ZoneId]; ok {
-			// 	e.zoneAnalytics[command.ZoneId].updateListingAmount(command.Resource, listingTime)
-			// 	e.zoneAnalytics[command.ZoneId].updateListingVolume(command.Resource, command.Amount, listingTime)
-			// }
-
-			command.ResponseChannel <- gamecomm.ChanResponse{Val: id}
-
-		case gamecomm.BuyMarketListing:
-			fmt.Println(command)
-			marketListing, err := e.getMarketListing(command.ZoneId, command.MarketListingId)
-			if err != nil {
-				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
-				close(command.ResponseChannel)
-				continue
-			}
-
-			amount, err := e.removeAmount(command.ZoneId, command.MarketListingId, command.Amount)
-			if err != nil {
-				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
-				close(command.ResponseChannel)
-				continue
-			}
-
-			// if _, ok := e.zoneAnalytics[command.ZoneId]; ok {
-			// 	e.zoneAnalytics[command.ZoneId].updateSalesAmount(command.Resource, listingTime)
-			// 	e.zoneAnalytics[command.ZoneId].updateSalesVolume(command.Resource, command.Amount, listingTime)
-			// }
-
-			command.ResponseChannel <- gamecomm.ChanResponse{Val: amount}
-
-			err = e.addTransaction(
-				command.ZoneId,
-				command.BuyerPlanetId,
-				command.CorporationId,
-				command.Resource,
-				marketListing.Price,
-				e.gameClock.GetCurrentTime(),
-			)
-			if err != nil {
-				log.Println(err)
-			}
-		case gamecomm.GetMarketListings:
-			marketListings, err := e.getZoneMarketListings(command.ZoneId)
-			if err != nil {
-				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
-				close(command.ResponseChannel)
-				continue
-			}
-
-			command.ResponseChannel <- gamecomm.ChanResponse{Val: *marketListings}
-		case gamecomm.EditMarketListingPrice:
-			err := e.editPrice(command.ZoneId, command.MarketListingId, command.CorporationId, command.Price)
-			if err != nil {
-				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
-				close(command.ResponseChannel)
-				continue
-			}
-
-			command.ResponseChannel <- gamecomm.ChanResponse{Val: "OK"}
-		case gamecomm.GetMarketListingsByResource:
-			marketListings, err := e.getZoneMarketListingsByResource(command.ZoneId, command.Resource)
-			if err != nil {
-				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
-				close(command.ResponseChannel)
-				continue
-			}
-
-			command.ResponseChannel <- gamecomm.ChanResponse{Val: *marketListings}
-		case gamecomm.GetMarketPrice:
-			marketPrice, err := e.getZoneResourceMarketPrice(command.ZoneId, command.Resource)
-			if err != nil {
-				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
-				close(command.ResponseChannel)
-				continue
-			}
-
-			command.ResponseChannel <- gamecomm.ChanResponse{Val: marketPrice}
-		}
-
-		close(command.ResponseChannel)
-	}
-}
@@ -0,0 +1,177 @@
+package economy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// forecastHistoryDays is how many days of price history the trend is fitted on
+	forecastHistoryDays = 30
+	maxForecastDays     = 90
+	// pressureWeight is the share of the supply and demand pressure expected to reach the price every day
+	pressureWeight = 0.5
+	// confidenceZ is the half width of the confidence band in standard deviations
+	confidenceZ = 1.96
+)
+
+// forecastPrice projects the price of the resource in the zone for the next days. The trend comes from the price
+// history and the supply listed in the zone against the planets demand pushes it up or down. Physical futures due in
+// the forecast days count as supply when shorts deliver and as demand when longs take delivery. The confidence band
+// grows with the days projected.
+func (z *zoneMarket) forecastPrice(resourceName string, days int, demand int) (gamecomm.PriceForecast, error) {
+	if days <= 0 || days > maxForecastDays {
+		return gamecomm.PriceForecast{}, fmt.Errorf("error: forecast days must be between 1 and %v", maxForecastDays)
+	}
+
+	r, ok := z.resources[resourceName]
+	if !ok {
+		return gamecomm.PriceForecast{}, fmt.Errorf("error: resource not found '%s'", resourceName)
+	}
+
+	currentPrice, err := z.getResourceMarketPrice(resourceName)
+	if err != nil {
+		return gamecomm.PriceForecast{}, err
+	}
+
+	supply := 0
+	for _, ml := range z.book.byResource[resourceName] {
+		supply += ml.Amount
+	}
+
+	until := z.gameClock.GetCurrentTime().Add(gameclock.GameTimeDuration(days * gameclock.Day))
+	delivered, taken := z.upcomingDeliveries(resourceName, until)
+	supply += delivered
+	demand += taken
+
+	slope, deviation := z.priceTrend(resourceName)
+	if deviation == 0 {
+		deviation = currentPrice * priceModel(r).Volatility
+	}
+
+	pressure := priceChange(r, supply, demand) * pressureWeight
+
+	forecast := gamecomm.PriceForecast{
+		ZoneId:       z.zoneId,
+		Resource:     resourceName,
+		CurrentPrice: currentPrice,
+		Projections:  make([]gamecomm.PriceProjection, 0, days),
+	}
+
+	for day := 1; day <= days; day++ {
+		price := clampPrice(r, (currentPrice+slope*float64(day))*math.Pow(1+pressure, float64(day)))
+		band := confidenceZ * deviation * math.Sqrt(float64(day))
+
+		forecast.Projections = append(forecast.Projections, gamecomm.PriceProjection{
+			Day:   day,
+			Price: price,
+			Low:   clampPrice(r, price-band),
+			High:  clampPrice(r, price+band),
+		})
+	}
+
+	return forecast, nil
+}
+
+// upcomingDeliveries returns how much of the resource the physical futures positions due by the time deliver to the
+// zone and take from it
+func (z *zoneMarket) upcomingDeliveries(resourceName string, until gameclock.GameTime) (int, int) {
+	delivered, taken := 0, 0
+	for _, fp := range z.futures {
+		if fp.resource != resourceName || fp.settlement != gamecomm.PhysicalSettlement || fp.deliveryTime.After(until) {
+			continue
+		}
+
+		if fp.side == gamecomm.Short {
+			delivered += fp.amount()
+		} else {
+			taken += fp.amount()
+		}
+	}
+
+	return delivered, taken
+}
+
+// priceTrend fits a line to the last days of price history and returns the daily change and the standard deviation
+// of the prices around it
+func (z *zoneMarket) priceTrend(resourceName string) (float64, float64) {
+	today := z.gameClock.GetCurrentTime().StartOfDay()
+
+	xs := []float64{}
+	ys := []float64{}
+	for i := forecastHistoryDays - 1; i >= 0; i-- {
+		if gameclock.GameTime(i*gameclock.Day) > today {
+			continue
+		}
+
+		price, ok := z.analytics.historicPrices[resourceName][today-gameclock.GameTime(i*gameclock.Day)]
+		if !ok {
+			continue
+		}
+
+		xs = append(xs, float64(-i))
+		ys = append(ys, price)
+	}
+
+	return linearRegression(xs, ys)
+}
+
+// linearRegression returns the slope of the least squares line and the standard deviation of the residuals
+func linearRegression(xs []float64, ys []float64) (float64, float64) {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0, 0
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+
+	meanX := sumX / n
+	meanY := sumY / n
+
+	var covariance, variance float64
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+
+	if variance == 0 {
+		return 0, 0
+	}
+
+	slope := covariance / variance
+	intercept := meanY - slope*meanX
+
+	var squaredResiduals float64
+	for i := range xs {
+		residual := ys[i] - (intercept + slope*xs[i])
+		squaredResiduals += residual * residual
+	}
+
+	return slope, math.Sqrt(squaredResiduals / n)
+}
+
+// zoneDemand asks the world how much of the resource the planets of the zone want
+func (z *zoneMarket) zoneDemand(resourceName string) (int, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	z.gameChannels.WorldChannel <- gamecomm.WorldCommand{
+		Action:          gamecomm.GetZoneDemand,
+		ZoneId:          z.zoneId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return 0, res.Err
+	}
+
+	demand := res.Val.(map[string]gamecomm.ResourceDemand)
+
+	return demand[resourceName].Quantity, nil
+}
@@ -0,0 +1,77 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestForecastPrice(t *testing.T) {
+	tests := []struct {
+		name          string
+		positions     []*futuresPosition
+		days          int
+		expectedPrice float64
+		expectedError string
+	}{
+		{
+			name:          "No Pressure",
+			days:          10,
+			expectedPrice: 100,
+		},
+		{
+			name: "Short Delivery Adds Supply",
+			positions: []*futuresPosition{
+				{id: "short", resource: "iron", contracts: 1, side: gamecomm.Short, settlement: gamecomm.PhysicalSettlement, deliveryTime: 5 * gameclock.Day},
+			},
+			days:          10,
+			expectedPrice: 96.875,
+		},
+		{
+			name: "Long Delivery Adds Demand",
+			positions: []*futuresPosition{
+				{id: "long", resource: "iron", contracts: 1, side: gamecomm.Long, settlement: gamecomm.PhysicalSettlement, deliveryTime: 5 * gameclock.Day},
+			},
+			days:          10,
+			expectedPrice: 103.125,
+		},
+		{
+			name: "Deliveries Outside The Forecast",
+			positions: []*futuresPosition{
+				{id: "late", resource: "iron", contracts: 1, side: gamecomm.Short, settlement: gamecomm.PhysicalSettlement, deliveryTime: 20 * gameclock.Day},
+				{id: "cash", resource: "iron", contracts: 1, side: gamecomm.Short, settlement: gamecomm.CashSettlement, deliveryTime: 5 * gameclock.Day},
+				{id: "water", resource: "water", contracts: 1, side: gamecomm.Short, settlement: gamecomm.PhysicalSettlement, deliveryTime: 5 * gameclock.Day},
+			},
+			days:          10,
+			expectedPrice: 100,
+		},
+		{
+			name:          "Too Many Days",
+			days:          maxForecastDays + 1,
+			expectedError: "error: forecast days must be between 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, _ := createTestZone(t, map[uint64]*testCorporation{})
+
+			for _, fp := range tt.positions {
+				z.futures[fp.id] = fp
+			}
+
+			forecast, err := z.forecastPrice("iron", tt.days, 0)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, len(forecast.Projections), tt.days)
+			assert.Equal(t, forecast.Projections[0].Price, tt.expectedPrice)
+		})
+	}
+}
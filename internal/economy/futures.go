@@ -0,0 +1,339 @@
+package economy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// contractSize is the amount of the resource in a standard contract
+	contractSize = 1_000
+	// initialMarginRate is the share of the contracts value held from the corporation when a position is opened
+	initialMarginRate = 0.1
+	// maintenanceMarginRate is the margin under which the corporation has to top up the margin or lose the position
+	maintenanceMarginRate = 0.05
+	// carryRate is the daily premium of the futures price over the zone price
+	carryRate       = 0.001
+	maxDeliveryDays = 180
+)
+
+// futuresPosition is a position the exchange holds against a corporation, the margin goes up and down every day
+// with the futures price
+type futuresPosition struct {
+	id               string
+	corporationId    uint64
+	resource         string
+	contracts        int
+	side             gamecomm.FuturesSide
+	settlement       gamecomm.SettlementType
+	entryPrice       float64
+	markPrice        float64
+	margin           float64
+	deposited        float64
+	deliveryTime     gameclock.GameTime
+	notificationChan chan string
+}
+
+func (fp *futuresPosition) copy(zoneId string) gamecomm.FuturesPosition {
+	return gamecomm.FuturesPosition{
+		Id:            fp.id,
+		ZoneId:        zoneId,
+		CorporationId: fp.corporationId,
+		Resource:      fp.resource,
+		Contracts:     fp.contracts,
+		ContractSize:  contractSize,
+		Side:          fp.side,
+		Settlement:    fp.settlement,
+		EntryPrice:    fp.entryPrice,
+		MarkPrice:     fp.markPrice,
+		Margin:        fp.margin,
+		DeliveryTime:  fp.deliveryTime,
+	}
+}
+
+func (fp *futuresPosition) amount() int {
+	return fp.contracts * contractSize
+}
+
+func (fp *futuresPosition) value(price float64) float64 {
+	return price * float64(fp.amount())
+}
+
+// profit is what the position gains when the price moves from the mark price to the price
+func (fp *futuresPosition) profit(price float64) float64 {
+	profit := fp.value(price) - fp.value(fp.markPrice)
+	if fp.side == gamecomm.Short {
+		return -profit
+	}
+
+	return profit
+}
+
+// futuresPrice is the zone price plus the carry until the delivery, on the delivery date it's the zone price
+func (z *zoneMarket) futuresPrice(resourceName string, deliveryTime gameclock.GameTime, now gameclock.GameTime) (float64, error) {
+	spotPrice, err := z.getResourceMarketPrice(resourceName)
+	if err != nil {
+		return 0, err
+	}
+
+	if !now.Before(deliveryTime) {
+		return spotPrice, nil
+	}
+
+	daysLeft := float64(deliveryTime-now) / gameclock.Day
+
+	return spotPrice * (1 + carryRate*daysLeft), nil
+}
+
+// openFuturesPosition holds the initial margin from the corporation and opens the position at the futures price
+func (z *zoneMarket) openFuturesPosition(corporationId uint64, resourceName string, contracts int, days int, side gamecomm.FuturesSide, settlement gamecomm.SettlementType, notificationChan chan string) (string, error) {
+	if contracts <= 0 {
+		return "", fmt.Errorf("error: contracts should be greater than zero")
+	}
+
+	if days <= 0 || days > maxDeliveryDays {
+		return "", fmt.Errorf("error: delivery days must be between 1 and %v", maxDeliveryDays)
+	}
+
+	if side != gamecomm.Long && side != gamecomm.Short {
+		return "", fmt.Errorf("error: unknown futures side %v", side)
+	}
+
+	if settlement != gamecomm.CashSettlement && settlement != gamecomm.PhysicalSettlement {
+		return "", fmt.Errorf("error: unknown settlement type %v", settlement)
+	}
+
+	now := z.gameClock.GetCurrentTime()
+	deliveryTime := now.Add(gameclock.GameTimeDuration(days * gameclock.Day))
+
+	price, err := z.futuresPrice(resourceName, deliveryTime, now)
+	if err != nil {
+		return "", err
+	}
+
+	fp := &futuresPosition{
+		corporationId:    corporationId,
+		resource:         resourceName,
+		contracts:        contracts,
+		side:             side,
+		settlement:       settlement,
+		entryPrice:       price,
+		markPrice:        price,
+		deliveryTime:     deliveryTime,
+		notificationChan: notificationChan,
+	}
+
+	fp.id = z.futuresId(z.futuresCounter + 1)
+	fp.margin = fp.value(price) * initialMarginRate
+	fp.deposited = fp.margin
+
+	err = z.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveCredits,
+		CorporationId: corporationId,
+		AmountDecimal: fp.margin,
+		Counterparty:  futuresEscrow(fp.id),
+		Memo:          "futures initial margin",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	z.futuresCounter++
+	z.futures[fp.id] = fp
+
+	return fp.id, nil
+}
+
+// closeFuturesPosition marks the position at the current futures price and gives the margin back
+func (z *zoneMarket) closeFuturesPosition(positionId string, corporationId uint64) (float64, error) {
+	fp, ok := z.futures[positionId]
+	if !ok {
+		return 0, fmt.Errorf("error: futures position not found with ID '%s'", positionId)
+	}
+
+	if fp.corporationId != corporationId {
+		return 0, fmt.Errorf("error: you can not close futures position with ID '%s'", positionId)
+	}
+
+	price, err := z.futuresPrice(fp.resource, fp.deliveryTime, z.gameClock.GetCurrentTime())
+	if err != nil {
+		return 0, err
+	}
+
+	fp.margin += fp.profit(price)
+	fp.markPrice = price
+
+	margin := fp.margin
+	z.releaseMargin(fp)
+
+	return margin, nil
+}
+
+// markToMarket moves the margin of every position with the futures price of the day. Positions under the
+// maintenance margin are topped up to the initial margin or closed, and positions on their delivery date settle.
+func (z *zoneMarket) markToMarket(now gameclock.GameTime) {
+	for _, fp := range z.futures {
+		price, err := z.futuresPrice(fp.resource, fp.deliveryTime, now)
+		if err != nil {
+			continue
+		}
+
+		fp.margin += fp.profit(price)
+		fp.markPrice = price
+
+		if !now.Before(fp.deliveryTime) {
+			z.settleFuturesPosition(fp, now)
+			continue
+		}
+
+		if fp.margin >= fp.value(price)*maintenanceMarginRate {
+			continue
+		}
+
+		topUp := fp.value(price)*initialMarginRate - fp.margin
+
+		err = z.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.RemoveCredits,
+			CorporationId: fp.corporationId,
+			AmountDecimal: topUp,
+			Counterparty:  futuresEscrow(fp.id),
+			Memo:          "futures margin call",
+		})
+		if err != nil {
+			notify(fp.notificationChan, fmt.Sprintf("Futures Notification: %v closed, not enough credits for the margin call", fp.id))
+			z.releaseMargin(fp)
+			continue
+		}
+
+		fp.margin += topUp
+		fp.deposited += topUp
+		notify(fp.notificationChan, fmt.Sprintf("Futures Notification: margin call on %v, %.2f credits added to the margin", fp.id, topUp))
+	}
+}
+
+// settleFuturesPosition delivers the resources at the zone price for physical positions, positions that can't be
+// delivered and cash positions only keep what the margin made
+func (z *zoneMarket) settleFuturesPosition(fp *futuresPosition, now gameclock.GameTime) {
+	if fp.settlement == gamecomm.PhysicalSettlement {
+		err := z.deliverFuturesPosition(fp, now)
+		if err != nil {
+			notify(fp.notificationChan, fmt.Sprintf("Futures Notification: %v couldn't be delivered, settled in cash: %v", fp.id, err.Error()))
+		}
+	}
+
+	notify(fp.notificationChan, fmt.Sprintf("Futures Notification: %v settled, %.2f credits returned from the margin", fp.id, fp.margin))
+	z.releaseMargin(fp)
+}
+
+func (z *zoneMarket) deliverFuturesPosition(fp *futuresPosition, now gameclock.GameTime) error {
+	payment := gamecomm.CorpCommand{
+		CorporationId: fp.corporationId,
+		AmountDecimal: fp.value(fp.markPrice),
+		Counterparty:  futuresAccount,
+		Memo:          "futures delivery payment",
+	}
+
+	delivery := gamecomm.CorpCommand{
+		CorporationId: fp.corporationId,
+		Resource:      fp.resource,
+		Amount:        fp.amount(),
+		Counterparty:  futuresAccount,
+		Memo:          "futures delivery",
+	}
+
+	// The long side pays the zone price for the resources and the short side gets paid for them
+	steps := []gamecomm.CorpCommand{}
+	if fp.side == gamecomm.Long {
+		payment.Action = gamecomm.RemoveCredits
+		delivery.Action = gamecomm.AddResourcesToBase
+		steps = append(steps, payment, delivery)
+	} else {
+		delivery.Action = gamecomm.RemoveResourcesFromBase
+		payment.Action = gamecomm.AddCredits
+		steps = append(steps, delivery, payment)
+	}
+
+	for i, step := range steps {
+		err := z.corpCommand(step)
+		if err != nil {
+			// Undo the steps already done so the position settles in cash
+			for _, done := range steps[:i] {
+				undoErr := z.corpCommand(reverseCommand(done))
+				if undoErr != nil {
+					fmt.Println(undoErr.Error())
+				}
+			}
+
+			return err
+		}
+	}
+
+	z.addTransaction("", fp.corporationId, fp.id, fp.resource, fp.amount(), fp.value(fp.markPrice), now)
+
+	return nil
+}
+
+// reverseCommand returns the command that gives back what the command moved
+func reverseCommand(command gamecomm.CorpCommand) gamecomm.CorpCommand {
+	reverse := command
+	reverse.Memo = command.Memo + " reversed"
+
+	switch command.Action {
+	case gamecomm.AddCredits:
+		reverse.Action = gamecomm.RemoveCredits
+	case gamecomm.RemoveCredits:
+		reverse.Action = gamecomm.AddCredits
+	case gamecomm.AddResourcesToBase:
+		reverse.Action = gamecomm.RemoveResourcesFromBase
+	case gamecomm.RemoveResourcesFromBase:
+		reverse.Action = gamecomm.AddResourcesToBase
+	}
+
+	return reverse
+}
+
+// releaseMargin closes the position, what is left of the margin goes back to the corporation and losses over the
+// margin are charged to it even if its credits go negative. The profit or loss of the position is posted between
+// the futures account and the margin before it's released.
+func (z *zoneMarket) releaseMargin(fp *futuresPosition) {
+	delete(z.futures, fp.id)
+
+	escrow := futuresEscrow(fp.id)
+	z.ledger.Transfer("futures profit and loss", futuresAccount, escrow, gamecomm.CreditsAsset, fp.margin-fp.deposited)
+
+	command := gamecomm.CorpCommand{
+		Action:        gamecomm.AddCredits,
+		CorporationId: fp.corporationId,
+		AmountDecimal: fp.margin,
+		Counterparty:  escrow,
+		Memo:          "futures margin released",
+	}
+
+	if fp.margin < 0 {
+		command.Action = gamecomm.ChargeCredits
+		command.AmountDecimal = -fp.margin
+	}
+
+	err := z.corpCommand(command)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+func (z *zoneMarket) getFuturesPositions(corporationId uint64) []gamecomm.FuturesPosition {
+	positions := []gamecomm.FuturesPosition{}
+	for _, fp := range z.futures {
+		if fp.corporationId == corporationId {
+			positions = append(positions, fp.copy(z.zoneId))
+		}
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].DeliveryTime < positions[j].DeliveryTime
+	})
+
+	return positions
+}
@@ -0,0 +1,194 @@
+package economy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestOpenFuturesPosition(t *testing.T) {
+	tests := []struct {
+		name            string
+		contracts       int
+		days            int
+		side            gamecomm.FuturesSide
+		settlement      gamecomm.SettlementType
+		expectedError   string
+		expectedCredits float64
+	}{
+		{
+			name:            "Open Position",
+			contracts:       1,
+			days:            10,
+			side:            gamecomm.Long,
+			settlement:      gamecomm.PhysicalSettlement,
+			expectedCredits: 20_000 - 10_100,
+		},
+		{
+			name:            "Zero Contracts",
+			contracts:       0,
+			days:            10,
+			side:            gamecomm.Long,
+			settlement:      gamecomm.PhysicalSettlement,
+			expectedError:   "error: contracts should be greater than zero",
+			expectedCredits: 20_000,
+		},
+		{
+			name:            "Delivery Too Far",
+			contracts:       1,
+			days:            maxDeliveryDays + 1,
+			side:            gamecomm.Long,
+			settlement:      gamecomm.PhysicalSettlement,
+			expectedError:   "error: delivery days must be between 1",
+			expectedCredits: 20_000,
+		},
+		{
+			name:            "Unknown Side",
+			contracts:       1,
+			days:            10,
+			side:            gamecomm.FuturesSide(7),
+			settlement:      gamecomm.PhysicalSettlement,
+			expectedError:   "error: unknown futures side",
+			expectedCredits: 20_000,
+		},
+		{
+			name:            "Unknown Settlement",
+			contracts:       1,
+			days:            10,
+			side:            gamecomm.Short,
+			settlement:      gamecomm.SettlementType(7),
+			expectedError:   "error: unknown settlement type",
+			expectedCredits: 20_000,
+		},
+		{
+			name:            "Not Enough Credits For The Margin",
+			contracts:       2,
+			days:            10,
+			side:            gamecomm.Short,
+			settlement:      gamecomm.CashSettlement,
+			expectedError:   "error: not enough credits",
+			expectedCredits: 20_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, tc := createTestZone(t, map[uint64]*testCorporation{
+				1: {credits: 20_000},
+			})
+
+			id, err := z.openFuturesPosition(1, "iron", tt.contracts, tt.days, tt.side, tt.settlement, nil)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+				assert.Equal(t, len(z.futures), 0)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, z.futures[id].margin, 10_100.0)
+				assert.Equal(t, z.ledger.Balance(futuresEscrow(id))[gamecomm.CreditsAsset], 10_100.0)
+			}
+
+			assert.Equal(t, tc.get(1).credits, tt.expectedCredits)
+		})
+	}
+}
+
+func TestDeliverFuturesPosition(t *testing.T) {
+	tests := []struct {
+		name              string
+		side              gamecomm.FuturesSide
+		credits           float64
+		stored            int
+		errors            map[gamecomm.CommandType]error
+		expectedError     string
+		expectedCredits   float64
+		expectedResources int
+	}{
+		{
+			name:              "Long Takes Delivery",
+			side:              gamecomm.Long,
+			credits:           200_000,
+			expectedCredits:   100_000,
+			expectedResources: 1_000,
+		},
+		{
+			name:              "Long Can't Pay",
+			side:              gamecomm.Long,
+			credits:           50_000,
+			expectedError:     "error: not enough credits",
+			expectedCredits:   50_000,
+			expectedResources: 0,
+		},
+		{
+			name:              "Long Delivery Fails",
+			side:              gamecomm.Long,
+			credits:           200_000,
+			errors:            map[gamecomm.CommandType]error{gamecomm.AddResourcesToBase: fmt.Errorf("error: base is full")},
+			expectedError:     "error: base is full",
+			expectedCredits:   200_000,
+			expectedResources: 0,
+		},
+		{
+			name:              "Short Delivers",
+			side:              gamecomm.Short,
+			stored:            1_500,
+			expectedCredits:   100_000,
+			expectedResources: 500,
+		},
+		{
+			name:              "Short Without Resources",
+			side:              gamecomm.Short,
+			stored:            500,
+			expectedError:     "error: not enough resources on base",
+			expectedCredits:   0,
+			expectedResources: 500,
+		},
+		{
+			name:              "Short Payment Fails",
+			side:              gamecomm.Short,
+			stored:            1_500,
+			errors:            map[gamecomm.CommandType]error{gamecomm.AddCredits: fmt.Errorf("error: payment failed")},
+			expectedError:     "error: payment failed",
+			expectedCredits:   0,
+			expectedResources: 1_500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, tc := createTestZone(t, map[uint64]*testCorporation{
+				1: {credits: tt.credits, resources: map[string]int{"iron": tt.stored}},
+			})
+			tc.errors = tt.errors
+
+			fp := &futuresPosition{
+				id:            "Zone-1-future-1",
+				corporationId: 1,
+				resource:      "iron",
+				contracts:     1,
+				side:          tt.side,
+				settlement:    gamecomm.PhysicalSettlement,
+				markPrice:     100,
+			}
+
+			err := z.deliverFuturesPosition(fp, gameclock.GameTime(0))
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+
+			assert.Equal(t, tc.get(1).credits, tt.expectedCredits)
+			assert.Equal(t, tc.get(1).resources["iron"], tt.expectedResources)
+
+			// Nothing is left on the futures account after a failed delivery
+			if tt.expectedError != "" {
+				assert.Equal(t, len(z.ledger.Balance(futuresAccount)), 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package economy
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// liquidationDiscount is the share of the market price paid for goods in a forced sale
+const liquidationDiscount = 0.5
+
+// CorporationEvents is the channel the economy reads the solvency events of the corporations from
+func (e *Economy) CorporationEvents() chan gamecomm.CorporationEvent {
+	return e.corporationEvents
+}
+
+// handleCorporationEvents freezes the credit of insolvent corporations, writes off the debts of restructured ones
+// and removes bankrupt corporations from every market
+func (e *Economy) handleCorporationEvents() {
+	for event := range e.corporationEvents {
+		corporationId := event.CorporationId
+
+		switch event.Type {
+		case gamecomm.CorporationInsolvent:
+			e.bank.tasks <- func(b *bank) {
+				b.freezeCreditLine(corporationId)
+			}
+		case gamecomm.CorporationRecovered:
+			e.bank.tasks <- func(b *bank) {
+				b.unfreezeCreditLine(corporationId)
+			}
+		case gamecomm.CorporationRestructured:
+			e.bank.tasks <- func(b *bank) {
+				b.writeOff(corporationId)
+			}
+		case gamecomm.CorporationBankrupt:
+			e.bank.tasks <- func(b *bank) {
+				b.writeOff(corporationId)
+			}
+
+			e.exchange.tasks <- func(x *stockExchange) {
+				x.removeCorporation(corporationId)
+			}
+
+			for _, z := range e.zones {
+				z.tasks <- func(z *zoneMarket) {
+					z.removeCorporation(corporationId)
+				}
+			}
+		}
+	}
+}
+
+// liquidateAssets sells the listings of the corporation in every zone to the bank and returns what the bank pays
+// for the goods stored on its bases. The listings are paid before the answer so the corporation knows its credits.
+func (e *Economy) liquidateAssets(command gamecomm.EconomyCommand) {
+	done := make(chan struct{}, len(e.zones))
+
+	for _, z := range e.zones {
+		z.tasks <- func(z *zoneMarket) {
+			z.liquidateListings(command.CorporationId)
+			done <- struct{}{}
+		}
+	}
+
+	for range e.zones {
+		<-done
+	}
+
+	e.bank.tasks <- func(b *bank) {
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: b.liquidationValue(command.Assets)}
+		close(command.ResponseChannel)
+	}
+}
+
+// liquidateListings takes the listings of the corporation off the zone and pays it the liquidation price for the
+// goods the bank takes from the market escrow
+func (z *zoneMarket) liquidateListings(corporationId uint64) {
+	for _, ml := range z.seizeListings(corporationId, "listing liquidated") {
+		credits := float64(ml.Amount) * z.prices[ml.ResourceName] * liquidationDiscount
+		if credits <= 0 {
+			continue
+		}
+
+		err := z.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddCredits,
+			CorporationId: corporationId,
+			AmountDecimal: credits,
+			Counterparty:  bankAccount,
+			Memo:          "listing liquidated",
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+}
+
+// seizeListings removes the listings of the corporation and moves their goods from the market escrow to the bank
+func (z *zoneMarket) seizeListings(corporationId uint64, memo string) []MarketListing {
+	seized := []MarketListing{}
+
+	for _, ml := range z.book.all() {
+		if ml.CorporationId != corporationId {
+			continue
+		}
+
+		amount, err := z.book.removeAmount(ml.Id, ml.Amount)
+		if err != nil {
+			fmt.Println(err.Error())
+			continue
+		}
+
+		z.ledger.Transfer(memo, gamecomm.MarketEscrow(z.zoneId), bankAccount, ml.ResourceName, float64(amount))
+
+		ml.Amount = amount
+		seized = append(seized, ml)
+	}
+
+	return seized
+}
+
+// removeCorporation takes the listings and futures positions of a bankrupt corporation off the zone. The bank keeps
+// the goods of the listings and the margin of the positions goes to the other side of the contracts.
+func (z *zoneMarket) removeCorporation(corporationId uint64) {
+	z.seizeListings(corporationId, "bankrupt listing seized")
+
+	for _, fp := range z.futures {
+		if fp.corporationId == corporationId {
+			z.forfeitMargin(fp)
+		}
+	}
+}
+
+// forfeitMargin closes the position of a corporation that can't be paid, the margin it deposited settles to the
+// futures account
+func (z *zoneMarket) forfeitMargin(fp *futuresPosition) {
+	delete(z.futures, fp.id)
+
+	z.ledger.Transfer("futures margin forfeited", futuresEscrow(fp.id), futuresAccount, gamecomm.CreditsAsset, fp.deposited)
+}
+
+// removeCorporation delists the shares of a bankrupt corporation and drops its orders. Buyers of the delisted
+// shares get their credits back, the shares are worth nothing.
+func (x *stockExchange) removeCorporation(corporationId uint64) {
+	if s, ok := x.stocks[corporationId]; ok {
+		for _, o := range s.bids {
+			delete(x.orders, o.id)
+
+			if o.corporationId == corporationId {
+				continue
+			}
+
+			err := x.corpCommand(gamecomm.CorpCommand{
+				Action:        gamecomm.AddCredits,
+				CorporationId: o.corporationId,
+				AmountDecimal: o.price * float64(o.amount),
+				Counterparty:  exchangeEscrow,
+				Memo:          "share order cancelled",
+			})
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+
+			notify(o.notificationChan, fmt.Sprintf("Exchange Notification: %v went bankrupt, order %v cancelled", s.name, o.id))
+		}
+
+		for _, o := range s.asks {
+			delete(x.orders, o.id)
+		}
+
+		delete(x.stocks, corporationId)
+	}
+
+	for id, o := range x.orders {
+		if o.corporationId != corporationId {
+			continue
+		}
+
+		x.stocks[o.issuerId].remove(o)
+		delete(x.orders, id)
+	}
+
+	for _, s := range x.stocks {
+		delete(s.holdings, corporationId)
+	}
+}
+
+// liquidationValue is what the bank pays for the goods in a forced sale
+func (b *bank) liquidationValue(assets map[string]int) float64 {
+	value := 0.0
+	for resourceName, amount := range assets {
+		value += float64(amount) * b.prices[resourceName] * liquidationDiscount
+	}
+
+	return value
+}
+
+// freezeCreditLine stops an insolvent corporation from drawing more credits
+func (b *bank) freezeCreditLine(corporationId uint64) {
+	cl, ok := b.creditLines[corporationId]
+	if !ok || cl.frozen {
+		return
+	}
+
+	cl.frozen = true
+	notify(cl.notificationChan, "Bank Notification: credit line frozen while the corporation is insolvent")
+}
+
+// unfreezeCreditLine lets a corporation that recovered draw credits again unless it froze for missed payments
+func (b *bank) unfreezeCreditLine(corporationId uint64) {
+	cl, ok := b.creditLines[corporationId]
+	if !ok || cl.missedPayments >= maxMissedPayments {
+		return
+	}
+
+	cl.frozen = false
+}
+
+// writeOff drops the loans and the credit line of a corporation that can't pay them, the bank keeps the collateral
+// and books the rest as bad debt
+func (b *bank) writeOff(corporationId uint64) {
+	written := false
+
+	for _, l := range b.loans {
+		if l.corporationId == corporationId {
+			b.writeOffLoan(l)
+			written = true
+		}
+	}
+
+	if cl, ok := b.creditLines[corporationId]; ok {
+		delete(b.creditLines, corporationId)
+		b.writeOffBalance("credit line written off", cl.balance)
+		written = written || cl.balance >= paidOff
+	}
+
+	if written {
+		b.defaults[corporationId]++
+	}
+}
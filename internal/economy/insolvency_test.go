@@ -0,0 +1,73 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestLiquidateListings(t *testing.T) {
+	z, tc := createTestZone(t, map[uint64]*testCorporation{
+		1: {resources: map[string]int{"iron": 100, "water": 50}},
+		2: {resources: map[string]int{"iron": 100}},
+	})
+
+	_, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 100, Price: 150, CorporationId: 1})
+	assert.NilError(t, err)
+
+	_, err = z.addMarketListing(MarketListing{ResourceName: "water", Amount: 50, Price: 10, CorporationId: 1})
+	assert.NilError(t, err)
+
+	other, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 100, Price: 150, CorporationId: 2})
+	assert.NilError(t, err)
+
+	z.liquidateListings(1)
+
+	// The bank pays half the zone price for the listed goods and the listings of other corporations stay
+	assert.Equal(t, len(z.book.all()), 1)
+	assert.Equal(t, z.book.all()[0].Id, other)
+	assert.Equal(t, tc.get(1).credits, 100*100*liquidationDiscount+50*10*liquidationDiscount)
+
+	assert.Equal(t, z.ledger.Balance(gamecomm.MarketEscrow(z.zoneId))["iron"], 100.0)
+	assert.Equal(t, z.ledger.Balance(gamecomm.MarketEscrow(z.zoneId))["water"], 0.0)
+	assert.Equal(t, z.ledger.Balance(bankAccount)["iron"], 100.0)
+	assert.Equal(t, z.ledger.Balance(bankAccount)["water"], 50.0)
+}
+
+func TestRemoveCorporation(t *testing.T) {
+	z, tc := createTestZone(t, map[uint64]*testCorporation{
+		1: {credits: 20_000, resources: map[string]int{"iron": 100}},
+		2: {credits: 20_000},
+	})
+
+	_, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 100, Price: 150, CorporationId: 1})
+	assert.NilError(t, err)
+
+	bankrupt, err := z.openFuturesPosition(1, "iron", 1, 10, gamecomm.Long, gamecomm.PhysicalSettlement, nil)
+	assert.NilError(t, err)
+
+	kept, err := z.openFuturesPosition(2, "iron", 1, 10, gamecomm.Long, gamecomm.PhysicalSettlement, nil)
+	assert.NilError(t, err)
+
+	deposited := z.futures[bankrupt].deposited
+
+	// The corporation is gone by the time the economy hears of the bankruptcy
+	tc.rw.Lock()
+	delete(tc.corporations, 1)
+	tc.rw.Unlock()
+
+	z.removeCorporation(1)
+
+	assert.Equal(t, len(z.book.all()), 0)
+	assert.Equal(t, len(z.futures), 1)
+
+	_, ok := z.futures[kept]
+	assert.Equal(t, ok, true)
+
+	// The bank keeps the listed goods and the margin settles to the other side of the contract
+	assert.Equal(t, len(z.ledger.Balance(gamecomm.MarketEscrow(z.zoneId))), 0)
+	assert.Equal(t, z.ledger.Balance(bankAccount)["iron"], 100.0)
+	assert.Equal(t, len(z.ledger.Balance(futuresEscrow(bankrupt))), 0)
+	assert.Equal(t, z.ledger.Balance(futuresAccount)[gamecomm.CreditsAsset], deposited)
+}
@@ -0,0 +1,150 @@
+package economy
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+var (
+	// futuresAccount is the other side of every futures contract, it pays the profits and takes the losses
+	futuresAccount = gamecomm.NewExternalAccount("futures")
+	// bankAccount lends the credits of loans and credit lines and takes their payments and the seized collateral
+	bankAccount = gamecomm.NewExternalAccount("bank")
+	// exchangeEscrow holds the credits of the buy orders on the stock exchange
+	exchangeEscrow = gamecomm.NewEscrowAccount("exchange")
+	// badDebtAccount takes the balances the bank writes off
+	badDebtAccount = gamecomm.NewExternalAccount("bad debt")
+	// seedAccount supplies the goods of the random market listings
+	seedAccount = gamecomm.NewExternalAccount("market seed")
+)
+
+func auctionEscrow(auctionId string) gamecomm.Account {
+	return gamecomm.NewEscrowAccount("auction/" + auctionId)
+}
+
+func futuresEscrow(positionId string) gamecomm.Account {
+	return gamecomm.NewEscrowAccount("futures/" + positionId)
+}
+
+// collateralEscrow holds the resources pledged for a loan until it's paid off or seized
+func collateralEscrow(loanId string) gamecomm.Account {
+	return gamecomm.NewEscrowAccount("collateral/" + loanId)
+}
+
+func dividendEscrow(issuerId uint64) gamecomm.Account {
+	return gamecomm.NewEscrowAccount(fmt.Sprintf("dividend/%d", issuerId))
+}
+
+func (z *zoneMarket) auctionId(n int) string {
+	return fmt.Sprintf("%v-auction-%d", z.zoneId, n)
+}
+
+func (z *zoneMarket) futuresId(n int) string {
+	return fmt.Sprintf("%v-future-%d", z.zoneId, n)
+}
+
+func loanId(n int) string {
+	return fmt.Sprintf("loan-%d", n)
+}
+
+// Holdings returns what the escrow accounts of the economy actually hold. The escrows of closed auctions, futures
+// and loans are reported empty, so anything left behind in them shows up when the ledger is checked.
+func (e *Economy) Holdings() map[gamecomm.Account]map[string]float64 {
+	actors := len(e.zones) + 2
+	holdingsChan := make(chan map[gamecomm.Account]map[string]float64, actors)
+
+	for _, z := range e.zones {
+		z.tasks <- func(z *zoneMarket) {
+			holdingsChan <- z.holdings()
+		}
+	}
+
+	e.bank.tasks <- func(b *bank) {
+		holdingsChan <- b.holdings()
+	}
+
+	e.exchange.tasks <- func(x *stockExchange) {
+		holdingsChan <- x.holdings()
+	}
+
+	holdings := make(map[gamecomm.Account]map[string]float64)
+	for i := 0; i < actors; i++ {
+		for account, assets := range <-holdingsChan {
+			holdings[account] = assets
+		}
+	}
+
+	return holdings
+}
+
+// holdings returns the goods of the listings, the lots and bids of the auctions and the futures margins
+func (z *zoneMarket) holdings() map[gamecomm.Account]map[string]float64 {
+	listed := make(map[string]float64)
+	for _, ml := range z.book.all() {
+		listed[ml.ResourceName] += float64(ml.Amount)
+	}
+
+	holdings := map[gamecomm.Account]map[string]float64{gamecomm.MarketEscrow(z.zoneId): listed}
+
+	for n := 1; n <= z.auctionCounter; n++ {
+		holdings[auctionEscrow(z.auctionId(n))] = map[string]float64{}
+	}
+
+	for _, a := range z.auctions {
+		lot := map[string]float64{a.resource: float64(a.amount)}
+		if a.highestBidderId != 0 {
+			lot[gamecomm.CreditsAsset] = a.highestBid
+		}
+
+		holdings[auctionEscrow(a.id)] = lot
+	}
+
+	for n := 1; n <= z.futuresCounter; n++ {
+		holdings[futuresEscrow(z.futuresId(n))] = map[string]float64{}
+	}
+
+	for _, fp := range z.futures {
+		holdings[futuresEscrow(fp.id)] = map[string]float64{gamecomm.CreditsAsset: fp.deposited}
+	}
+
+	return holdings
+}
+
+// holdings returns the resources pledged for the loans
+func (b *bank) holdings() map[gamecomm.Account]map[string]float64 {
+	holdings := make(map[gamecomm.Account]map[string]float64)
+
+	for n := 1; n <= b.loanCounter; n++ {
+		holdings[collateralEscrow(loanId(n))] = map[string]float64{}
+	}
+
+	for _, l := range b.loans {
+		pledged := make(map[string]float64, len(l.collateral.Resources))
+		for resourceName, amount := range l.collateral.Resources {
+			pledged[resourceName] = float64(amount)
+		}
+
+		holdings[collateralEscrow(l.id)] = pledged
+	}
+
+	return holdings
+}
+
+// holdings returns the credits of the buy orders, dividends are paid out as soon as they are taken
+func (x *stockExchange) holdings() map[gamecomm.Account]map[string]float64 {
+	held := 0.0
+	holdings := make(map[gamecomm.Account]map[string]float64, len(x.stocks)+1)
+
+	for issuerId, s := range x.stocks {
+		for _, o := range s.bids {
+			held += o.price * float64(o.amount)
+		}
+
+		holdings[dividendEscrow(issuerId)] = map[string]float64{}
+	}
+
+	holdings[exchangeEscrow] = map[string]float64{gamecomm.CreditsAsset: held}
+
+	return holdings
+}
@@ -0,0 +1,87 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// onZone runs the task on the zone market goroutine and waits for it
+func onZone(e *Economy, zoneId string, task func(z *zoneMarket)) {
+	done := make(chan struct{})
+	e.zones[zoneId].tasks <- func(z *zoneMarket) {
+		task(z)
+		close(done)
+	}
+
+	<-done
+}
+
+// escrowMismatches returns the mismatches of the ledger check on escrow accounts, the test corporations don't post
+// opening balances so their accounts never match
+func escrowMismatches(report gamecomm.LedgerReport) []gamecomm.LedgerMismatch {
+	result := []gamecomm.LedgerMismatch{}
+	for _, m := range report.Mismatches {
+		if m.Account.Type == gamecomm.EscrowAccount {
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+func TestEconomyHoldings(t *testing.T) {
+	e, tc := createTestEconomy(t, map[uint64]*testCorporation{
+		1: {credits: 10_000, resources: map[string]int{"iron": 200}},
+		2: {credits: 20_000, resources: map[string]int{"iron": 100}},
+	}, "Zone-1")
+
+	var sold string
+	onZone(e, "Zone-1", func(z *zoneMarket) {
+		_, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 30, Price: 100, CorporationId: 1})
+		assert.NilError(t, err)
+
+		id, err := z.createAuction(1, "iron", 20, 50, 2, nil)
+		assert.NilError(t, err)
+		assert.NilError(t, z.placeBid(id, 2, 500, nil))
+
+		// The second auction ends without bids and gives the lot back
+		sold, err = z.createAuction(1, "iron", 10, 50, 1, nil)
+		assert.NilError(t, err)
+		z.auctions[sold].endTime = 0
+		z.closeAuctions(1)
+
+		_, err = z.openFuturesPosition(2, "iron", 1, 10, gamecomm.Long, gamecomm.PhysicalSettlement, nil)
+		assert.NilError(t, err)
+	})
+
+	done := make(chan struct{})
+	e.bank.tasks <- func(b *bank) {
+		_, err := b.takeLoan(2, 1_000, 12, gamecomm.Collateral{Resources: map[string]int{"iron": 40}}, nil)
+		assert.NilError(t, err)
+		close(done)
+	}
+	<-done
+
+	holdings := e.Holdings()
+
+	assert.Equal(t, holdings[gamecomm.MarketEscrow("Zone-1")]["iron"], 30.0)
+	assert.Equal(t, holdings[auctionEscrow("Zone-1-auction-1")]["iron"], 20.0)
+	assert.Equal(t, holdings[auctionEscrow("Zone-1-auction-1")][gamecomm.CreditsAsset], 500.0)
+	assert.Equal(t, len(holdings[auctionEscrow(sold)]), 0)
+	assert.Greater(t, holdings[futuresEscrow("Zone-1-future-1")][gamecomm.CreditsAsset], 0.0)
+	assert.Equal(t, holdings[collateralEscrow("loan-1")]["iron"], 40.0)
+	assert.Equal(t, holdings[exchangeEscrow][gamecomm.CreditsAsset], 0.0)
+
+	assert.Equal(t, len(escrowMismatches(tc.ledger.Check(holdings))), 0)
+
+	// Goods left behind in the escrow of a closed auction are found
+	tc.ledger.Transfer("lost lot", gamecomm.NewBaseAccount(1, 0), auctionEscrow(sold), "iron", 5)
+
+	mismatches := escrowMismatches(tc.ledger.Check(e.Holdings()))
+	assert.Equal(t, len(mismatches), 1)
+	assert.Equal(t, mismatches[0].Account, auctionEscrow(sold))
+	assert.Equal(t, mismatches[0].Ledger, 5.0)
+	assert.Equal(t, mismatches[0].Actual, 0.0)
+}
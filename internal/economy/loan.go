@@ -0,0 +1,255 @@
+package economy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// paidOff is the balance under which a loan or a credit line is considered paid
+const paidOff = 0.01
+
+// loan is paid back in monthly installments, missed payments add their interest to the balance and move the end of
+// the loan one month
+type loan struct {
+	id               string
+	corporationId    uint64
+	principal        float64
+	balance          float64
+	rate             float64
+	payment          float64
+	paymentsLeft     int
+	missedPayments   int
+	nextPayment      gameclock.GameTime
+	collateral       gamecomm.Collateral
+	notificationChan chan string
+}
+
+func (l *loan) copy() gamecomm.Loan {
+	return gamecomm.Loan{
+		Id:             l.id,
+		CorporationId:  l.corporationId,
+		Principal:      l.principal,
+		Balance:        l.balance,
+		Rate:           l.rate,
+		Payment:        l.payment,
+		PaymentsLeft:   l.paymentsLeft,
+		MissedPayments: l.missedPayments,
+		NextPayment:    l.nextPayment,
+		Collateral:     copyCollateral(l.collateral),
+	}
+}
+
+func copyCollateral(collateral gamecomm.Collateral) gamecomm.Collateral {
+	resources := make(map[string]int, len(collateral.Resources))
+	for resourceName, amount := range collateral.Resources {
+		resources[resourceName] = amount
+	}
+
+	squads := make([]int, len(collateral.Squads))
+	copy(squads, collateral.Squads)
+
+	return gamecomm.Collateral{
+		Resources: resources,
+		Squads:    squads,
+	}
+}
+
+// loanOffer prices the loan with the reputation and net worth of the corporation. Corporations can owe up to twice
+// their net worth after debts, plus the value of the collateral.
+func (b *bank) loanOffer(corporationId uint64, principal float64, months int, collateral gamecomm.Collateral) (gamecomm.LoanOffer, error) {
+	if principal <= 0 {
+		return gamecomm.LoanOffer{}, fmt.Errorf("error: principal should be greater than zero")
+	}
+
+	if months <= 0 || months > maxLoanMonths {
+		return gamecomm.LoanOffer{}, fmt.Errorf("error: months must be between 1 and %v", maxLoanMonths)
+	}
+
+	corp, err := b.getCorporation(corporationId)
+	if err != nil {
+		return gamecomm.LoanOffer{}, err
+	}
+
+	collateralValue, err := b.collateralValue(corporationId, corp, collateral)
+	if err != nil {
+		return gamecomm.LoanOffer{}, err
+	}
+
+	debt := b.debt(corporationId)
+	equity := b.equity(corp)
+
+	maxPrincipal := max(maxLoanFactor*equity-debt, 0) + collateralValue
+	if principal > maxPrincipal {
+		return gamecomm.LoanOffer{}, fmt.Errorf("error: the bank lends at most %.2f credits to the corporation", maxPrincipal)
+	}
+
+	rate := b.interestRate(corp, equity, principal+debt, collateralValue/principal)
+
+	return gamecomm.LoanOffer{
+		CorporationId:   corporationId,
+		Principal:       principal,
+		Months:          months,
+		Rate:            rate,
+		Payment:         installment(principal, rate, months),
+		CollateralValue: collateralValue,
+	}, nil
+}
+
+// takeLoan holds the pledged resources and gives the credits to the corporation, the first payment is due in a
+// month
+func (b *bank) takeLoan(corporationId uint64, principal float64, months int, collateral gamecomm.Collateral, notificationChan chan string) (gamecomm.Loan, error) {
+	offer, err := b.loanOffer(corporationId, principal, months, collateral)
+	if err != nil {
+		return gamecomm.Loan{}, err
+	}
+
+	id := loanId(b.loanCounter + 1)
+
+	err = b.pledgeCollateral(id, corporationId, collateral.Resources)
+	if err != nil {
+		return gamecomm.Loan{}, err
+	}
+
+	err = b.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.AddCredits,
+		CorporationId: corporationId,
+		AmountDecimal: principal,
+		Counterparty:  bankAccount,
+		Memo:          "loan principal",
+	})
+	if err != nil {
+		b.returnCollateral(id, corporationId, collateral.Resources)
+		return gamecomm.Loan{}, err
+	}
+
+	b.loanCounter++
+	l := &loan{
+		id:               id,
+		corporationId:    corporationId,
+		principal:        principal,
+		balance:          principal,
+		rate:             offer.Rate,
+		payment:          offer.Payment,
+		paymentsLeft:     months,
+		nextPayment:      b.gameClock.GetCurrentTime().Add(gameclock.Month),
+		collateral:       copyCollateral(collateral),
+		notificationChan: notificationChan,
+	}
+
+	b.loans[l.id] = l
+
+	return l.copy(), nil
+}
+
+// repayLoan pays part of the balance before it's due and returns what is left, the next payments get smaller
+func (b *bank) repayLoan(loanId string, corporationId uint64, credits float64) (float64, error) {
+	l, ok := b.loans[loanId]
+	if !ok {
+		return 0, fmt.Errorf("error: loan not found with ID '%s'", loanId)
+	}
+
+	if l.corporationId != corporationId {
+		return 0, fmt.Errorf("error: loan '%s' doesn't belong to corporation %d", loanId, corporationId)
+	}
+
+	if credits <= 0 {
+		return 0, fmt.Errorf("error: credits should be greater than zero")
+	}
+
+	amount := min(credits, l.balance)
+
+	err := b.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveCredits,
+		CorporationId: corporationId,
+		AmountDecimal: amount,
+		Counterparty:  bankAccount,
+		Memo:          "loan repayment",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	l.balance -= amount
+	if l.balance < paidOff {
+		b.payOff(l)
+		return 0, nil
+	}
+
+	l.payment = installment(l.balance, l.rate, l.paymentsLeft)
+
+	return l.balance, nil
+}
+
+// collectLoanPayment takes the monthly installment, the loan defaults after too many missed payments in a row
+func (b *bank) collectLoanPayment(l *loan) {
+	l.nextPayment = l.nextPayment.Add(gameclock.Month)
+
+	interest := l.balance * l.rate
+
+	due := min(l.payment, l.balance+interest)
+	if l.paymentsLeft <= 1 {
+		due = l.balance + interest
+	}
+
+	err := b.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveCredits,
+		CorporationId: l.corporationId,
+		AmountDecimal: due,
+		Counterparty:  bankAccount,
+		Memo:          "loan installment",
+	})
+	if err != nil {
+		l.balance += interest
+		l.missedPayments++
+
+		if l.missedPayments >= maxMissedPayments {
+			b.defaultLoan(l)
+			return
+		}
+
+		notify(l.notificationChan, fmt.Sprintf("Bank Notification: missed payment of %.2f credits on %v, %d missed payments", due, l.id, l.missedPayments))
+		return
+	}
+
+	l.balance += interest - due
+	l.paymentsLeft--
+	l.missedPayments = 0
+
+	if l.paymentsLeft <= 0 || l.balance < paidOff {
+		b.payOff(l)
+		notify(l.notificationChan, fmt.Sprintf("Bank Notification: %v paid off", l.id))
+	}
+}
+
+// payOff closes the loan and gives the pledged resources back
+func (b *bank) payOff(l *loan) {
+	delete(b.loans, l.id)
+	b.returnCollateral(l.id, l.corporationId, l.collateral.Resources)
+}
+
+// defaultLoan seizes the collateral and writes off what it doesn't cover
+func (b *bank) defaultLoan(l *loan) {
+	b.defaults[l.corporationId]++
+
+	seized := b.writeOffLoan(l)
+
+	notify(l.notificationChan, fmt.Sprintf("Bank Notification: %v defaulted, collateral worth %.2f credits seized for a balance of %.2f", l.id, seized, l.balance))
+}
+
+func (b *bank) getLoans(corporationId uint64) []gamecomm.Loan {
+	loans := []gamecomm.Loan{}
+	for _, l := range b.loans {
+		if l.corporationId == corporationId {
+			loans = append(loans, l.copy())
+		}
+	}
+
+	sort.Slice(loans, func(i, j int) bool {
+		return loans[i].NextPayment < loans[j].NextPayment
+	})
+
+	return loans
+}
@@ -9,6 +9,14 @@ import (
 
 // MarketRequest
 
+const (
+	// minListingPriceFactor and maxListingPriceFactor bound the price of an edited listing relative to the zone
+	// market price
+	minListingPriceFactor = 0.1
+	maxListingPriceFactor = 10
+	maxListingAmount      = 1_000_000_000
+)
+
 type MarketListing struct {
 	Id              string
 	ResourceName    string
@@ -17,11 +25,14 @@ type MarketListing struct {
 	Price           float64
 	CorporationId   uint64
 	ListTime        gameclock.GameTime
+	sequence        int
 }
 
-func (e *Economy) addMarketListing(zoneId string, so MarketListing) (string, error) {
+// addMarketListing takes the goods of the listing from the seller base and keeps them in the market escrow until
+// they are bought
+func (z *zoneMarket) addMarketListing(so MarketListing) (string, error) {
 
-	if _, ok := e.resources[so.ResourceName]; !ok {
+	if _, ok := z.resources[so.ResourceName]; !ok {
 		return "", fmt.Errorf("error: resource doesn't exist")
 	}
 
@@ -33,180 +44,152 @@ func (e *Economy) addMarketListing(zoneId string, so MarketListing) (string, err
 		return "", fmt.Errorf("error: price should be greater than zero")
 	}
 
-	resChan := make(chan gamecomm.ChanResponse)
-
-	e.gameChannels.CorpChannel <- gamecomm.CorpCommand{
-		Action:          gamecomm.GetCorporation,
-		CorporationId:   so.CorporationId,
-		ResponseChannel: resChan,
-	}
-
-	res := <-resChan
-	if res.Err != nil {
-		return "", fmt.Errorf("error: incorrect corporation id")
-	}
-
-	e.rw.RLock()
-	mutex, ok := e.zoneMutexes[zoneId]
-	zoneListings := e.marketListings[zoneId]
-	e.rw.RUnlock()
-	if !ok {
-		return "", fmt.Errorf("error: no mutex found for zone ID '%s'", zoneId)
-	}
-
-	mutex.Lock()
-	defer mutex.Unlock()
-
 	// Generate Id
-	e.zoneMarketListingCounter[zoneId]++
-	num := e.zoneMarketListingCounter[zoneId]
-	id := fmt.Sprintf("%v-%d", zoneId, num)
+	id := fmt.Sprintf("%v-%d", z.zoneId, z.listingCounter+1)
 
-	so.Id = id
-	zl := *zoneListings
-	zl = append(zl, so)
-	*zoneListings = zl
-
-	return so.Id, nil
-}
-
-func (e *Economy) getZoneMarketListings(zoneId string) (*[]MarketListing, error) {
-	e.rw.RLock()
-	mutex, ok := e.zoneMutexes[zoneId]
-	e.rw.RUnlock()
-	if !ok {
-		return &[]MarketListing{}, fmt.Errorf("error: no mutex found for zone ID '%s'", zoneId)
+	err := z.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveResourcesFromBase,
+		CorporationId: so.CorporationId,
+		Resource:      so.ResourceName,
+		Amount:        so.Amount,
+		Counterparty:  gamecomm.MarketEscrow(z.zoneId),
+		Memo:          "market listing",
+	})
+	if err != nil {
+		return "", err
 	}
 
-	mutex.RLock()
-	defer mutex.RUnlock()
-
-	marketListings, ok := e.marketListings[zoneId]
-	if !ok {
-		return &[]MarketListing{}, nil
-	}
-
-	return marketListings, nil
-}
-
-func (e *Economy) getZoneMarketListingsByResource(zoneId string, resourceName string) (*[]MarketListing, error) {
-	e.rw.RLock()
-	mutex, ok := e.zoneMutexes[zoneId]
-	e.rw.RUnlock()
-	if !ok {
-		return &[]MarketListing{}, fmt.Errorf("error: no mutex found for zone ID '%s'", zoneId)
-	}
+	z.listingCounter++
+	so.Id = id
 
-	mutex.RLock()
-	defer mutex.RUnlock()
+	z.book.add(so)
 
-	marketListings := []MarketListing{}
+	z.analytics.updateListingAmount(so.ResourceName, so.ListTime)
+	z.analytics.updateListingVolume(so.ResourceName, so.Amount, so.ListTime)
 
-	for _, ml := range *e.marketListings[zoneId] {
-		if ml.ResourceName == resourceName {
-			marketListings = append(marketListings, ml)
-		}
-	}
-
-	return &marketListings, nil
+	return so.Id, nil
 }
 
-// TODO: Refactor to pointer to slice granular if necessary
-func (e *Economy) getZoneResourceMarketPrice(zoneId string, resourceName string) (float64, error) {
-	e.rw.RLock()
-	mutex, ok := e.zoneMutexes[zoneId]
-	e.rw.RUnlock()
-	if !ok {
-		return 0, fmt.Errorf("error: no mutex found for zone ID '%s'", zoneId)
-	}
-
-	mutex.RLock()
-	defer mutex.RUnlock()
-
-	resourcePrices, ok := e.resourcePrices[zoneId]
-	if !ok {
-		return 0, fmt.Errorf("error: resourcePrices not found for zone ID '%s'", zoneId)
-	}
-
-	resourcePrice, ok := resourcePrices[resourceName]
+func (z *zoneMarket) getResourceMarketPrice(resourceName string) (float64, error) {
+	resourcePrice, ok := z.prices[resourceName]
 	if !ok {
 		return 0, fmt.Errorf("error: resource price not found '%s'", resourceName)
 	}
 
 	return resourcePrice, nil
+}
 
+func (z *zoneMarket) removeAmount(listingId string, amount int) (int, error) {
+	return z.book.removeAmount(listingId, amount)
 }
 
-func (e *Economy) getMarketListing(zoneId string, listingId string) (MarketListing, error) {
-	e.rw.RLock()
-	mutex, ok := e.zoneMutexes[zoneId]
-	e.rw.RUnlock()
+// editPrice changes the price of a listing of the corporation, the new price has to be close to the market price
+func (z *zoneMarket) editPrice(listingId string, corporationId uint64, price float64) error {
+	ml, ok := z.book.get(listingId)
 	if !ok {
-		return MarketListing{}, fmt.Errorf("error: no mutex found for zone ID '%s'", zoneId)
+		return fmt.Errorf("error: listing not found with ID '%s'", listingId)
 	}
 
-	mutex.RLock()
-	defer mutex.RUnlock()
+	if price <= 0 {
+		return fmt.Errorf("error: price should be greater than zero")
+	}
 
-	marketListings, ok := e.marketListings[zoneId]
-	if !ok {
-		return MarketListing{}, fmt.Errorf("error: no market listing found for zone ID '%s'", zoneId)
+	marketPrice, err := z.getResourceMarketPrice(ml.ResourceName)
+	if err != nil {
+		return err
 	}
 
-	for _, ml := range *marketListings {
-		if ml.Id == listingId {
-			return ml, nil
-		}
+	minPrice := marketPrice * minListingPriceFactor
+	maxPrice := marketPrice * maxListingPriceFactor
+	if price < minPrice || price > maxPrice {
+		return fmt.Errorf("error: price should be between %.2f and %.2f", minPrice, maxPrice)
 	}
 
-	return MarketListing{}, fmt.Errorf("error: listing with ID '%s' not found", listingId)
+	return z.book.setPrice(listingId, corporationId, price, z.gameClock.GetCurrentTime())
 }
 
-func (e *Economy) removeAmount(zoneId string, listingId string, amount int) (int, error) {
-	e.rw.RLock()
-	mutex, ok := e.zoneMutexes[zoneId]
-	if !ok {
-		e.rw.RUnlock()
-		return 0, fmt.Errorf("error: no mutex found for zone ID '%s'", zoneId)
+// editAmount changes the amount of a listing of the corporation. A raise takes the difference from the seller base
+// into the market escrow and a cut gives it back.
+func (z *zoneMarket) editAmount(listingId string, corporationId uint64, amount int) error {
+	if amount <= 0 || amount > maxListingAmount {
+		return fmt.Errorf("error: amount should be between 1 and %d", maxListingAmount)
 	}
 
-	zoneListings, ok := e.marketListings[zoneId]
-	if !ok {
-		e.rw.RUnlock()
-		return 0, fmt.Errorf("error: no market listing found for zone ID '%s'", zoneId)
+	ml, err := z.book.owned(listingId, corporationId)
+	if err != nil {
+		return err
 	}
-	e.rw.RUnlock()
-
-	mutex.Lock()
-	defer mutex.Unlock()
 
-	selectedIndex := -1
-	for i, ml := range *zoneListings {
-		if ml.Id == listingId {
-			selectedIndex = i
+	if amount != ml.Amount {
+		command := gamecomm.CorpCommand{
+			Action:        gamecomm.RemoveResourcesFromBase,
+			CorporationId: corporationId,
+			Resource:      ml.ResourceName,
+			Amount:        amount - ml.Amount,
+			Counterparty:  gamecomm.MarketEscrow(z.zoneId),
+			Memo:          "market listing raised",
 		}
-	}
-
-	if selectedIndex == -1 {
-		return 0, fmt.Errorf("error: listing not found with ID '%s'", listingId)
-	}
 
-	zl := *zoneListings
+		if amount < ml.Amount {
+			command.Action = gamecomm.AddResourcesToBase
+			command.Amount = ml.Amount - amount
+			command.Memo = "market listing cut"
+		}
 
-	remainingAmount := zl[selectedIndex].Amount
-	if remainingAmount < amount {
-		amount = remainingAmount
+		err = z.corpCommand(command)
+		if err != nil {
+			return err
+		}
 	}
 
-	zl[selectedIndex].Amount -= amount
+	return z.book.setAmount(listingId, corporationId, amount, z.gameClock.GetCurrentTime())
+}
 
-	if zl[selectedIndex].Amount == 0 {
-		zl = append(zl[:selectedIndex], zl[selectedIndex+1:]...)
+// fillOrders buys for every order from its listings in order of preference until the order is complete or the
+// buyer runs out of budget. Listings that are gone are skipped, only the listings that sold to the order are filled.
+func (z *zoneMarket) fillOrders(orders []gamecomm.PurchaseOrder, budgets map[string]float64) []gamecomm.PurchaseFill {
+	fills := []gamecomm.PurchaseFill{}
+
+	for i, order := range orders {
+		remaining := order.Amount
+
+		for _, listingId := range order.ListingIds {
+			if remaining <= 0 {
+				break
+			}
+
+			ml, ok := z.book.get(listingId)
+			if !ok {
+				continue
+			}
+
+			requested := min(remaining, ml.Amount, int(budgets[order.BuyerPlanetId]/ml.Price))
+			if requested <= 0 {
+				continue
+			}
+
+			amount, err := z.removeAmount(listingId, requested)
+			if err != nil {
+				continue
+			}
+
+			credits := ml.Price * float64(amount)
+			budgets[order.BuyerPlanetId] -= credits
+			remaining -= amount
+
+			z.addTransaction(order.BuyerPlanetId, ml.CorporationId, ml.Id, ml.ResourceName, amount, credits, z.gameClock.GetCurrentTime())
+
+			fills = append(fills, gamecomm.PurchaseFill{
+				Order:     i,
+				ListingId: listingId,
+				Requested: requested,
+				Amount:    amount,
+				Price:     ml.Price,
+			})
+		}
 	}
 
-	*zoneListings = zl
-
-	return amount, nil
+	return fills
 }
 
 // func (e *Economy) removeMarketListing(zoneId string, listingId string) error {
@@ -237,43 +220,3 @@ func (e *Economy) removeAmount(zoneId string, listingId string, amount int) (int
 //
 // 	return nil
 // }
-
-func (e *Economy) editPrice(zoneId string, listingId string, corporationId uint64, price float64) error {
-	e.rw.RLock()
-	mutex, ok := e.zoneMutexes[zoneId]
-	if !ok {
-		e.rw.RUnlock()
-		return fmt.Errorf("error: no mutex found for zone ID '%s'", zoneId)
-	}
-
-	zoneListings, ok := e.marketListings[zoneId]
-	if !ok {
-		e.rw.RUnlock()
-		return fmt.Errorf("error: no market listing found for zone ID '%s'", zoneId)
-	}
-	e.rw.RUnlock()
-
-	mutex.Lock()
-	defer mutex.Unlock()
-
-	selectedIndex := -1
-	for i, ml := range *zoneListings {
-		if ml.Id == listingId {
-			selectedIndex = i
-		}
-	}
-
-	zl := *zoneListings
-
-	listing := zl[selectedIndex]
-
-	if listing.CorporationId != corporationId {
-		return fmt.Errorf("error: you can not edit listing with ID '%s'", listingId)
-	}
-
-	zl[selectedIndex].Price = price
-
-	*zoneListings = zl
-
-	return nil
-}
@@ -0,0 +1,229 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestAddMarketListing(t *testing.T) {
+	tests := []struct {
+		name          string
+		listing       MarketListing
+		stored        int
+		expectedError string
+	}{
+		{
+			name:    "Add Listing",
+			listing: MarketListing{ResourceName: "iron", Amount: 300, Price: 90, CorporationId: 1},
+			stored:  500,
+		},
+		{
+			name:          "Not Enough Resources",
+			listing:       MarketListing{ResourceName: "iron", Amount: 300, Price: 90, CorporationId: 1},
+			stored:        200,
+			expectedError: "error: not enough resources on base",
+		},
+		{
+			name:          "Unknown Corporation",
+			listing:       MarketListing{ResourceName: "iron", Amount: 300, Price: 90, CorporationId: 9},
+			stored:        500,
+			expectedError: "error: corporation not found",
+		},
+		{
+			name:          "Unknown Resource",
+			listing:       MarketListing{ResourceName: "gold", Amount: 300, Price: 90, CorporationId: 1},
+			stored:        500,
+			expectedError: "error: resource doesn't exist",
+		},
+		{
+			name:          "Zero Amount",
+			listing:       MarketListing{ResourceName: "iron", Amount: 0, Price: 90, CorporationId: 1},
+			stored:        500,
+			expectedError: "error: amount should be greater than zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, tc := createTestZone(t, map[uint64]*testCorporation{
+				1: {resources: map[string]int{"iron": tt.stored}},
+			})
+
+			id, err := z.addMarketListing(tt.listing)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+				assert.Equal(t, len(z.book.all()), 0)
+				assert.Equal(t, tc.get(1).resources["iron"], tt.stored)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, id, "Zone-1-1")
+
+			ml, ok := z.book.get(id)
+			assert.Equal(t, ok, true)
+			assert.Equal(t, ml.Amount, 300)
+
+			// The goods wait in the market escrow until they are bought
+			assert.Equal(t, tc.get(1).resources["iron"], 200)
+			assert.Equal(t, z.ledger.Balance(gamecomm.MarketEscrow("Zone-1"))["iron"], 300.0)
+		})
+	}
+}
+
+func TestEditPrice(t *testing.T) {
+	tests := []struct {
+		name          string
+		listingId     string
+		corporationId uint64
+		price         float64
+		expectedError string
+		expectedPrice float64
+	}{
+		{name: "Edit Price", listingId: "Zone-1-1", corporationId: 1, price: 120, expectedPrice: 120},
+		{name: "Under The Market Price", listingId: "Zone-1-1", corporationId: 1, price: 9, expectedError: "error: price should be between 10.00 and 1000.00", expectedPrice: 90},
+		{name: "Over The Market Price", listingId: "Zone-1-1", corporationId: 1, price: 1_001, expectedError: "error: price should be between 10.00 and 1000.00", expectedPrice: 90},
+		{name: "Zero Price", listingId: "Zone-1-1", corporationId: 1, price: 0, expectedError: "error: price should be greater than zero", expectedPrice: 90},
+		{name: "Not The Seller", listingId: "Zone-1-1", corporationId: 2, price: 120, expectedError: "error: you can not edit listing", expectedPrice: 90},
+		{name: "Listing Not Found", listingId: "Zone-1-9", corporationId: 1, price: 120, expectedError: "error: listing not found with ID 'Zone-1-9'", expectedPrice: 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, _ := createTestZone(t, map[uint64]*testCorporation{
+				1: {resources: map[string]int{"iron": 500}},
+			})
+
+			id, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 300, Price: 90, CorporationId: 1})
+			assert.NilError(t, err)
+
+			err = z.editPrice(tt.listingId, tt.corporationId, tt.price)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+
+			ml, _ := z.book.get(id)
+			assert.Equal(t, ml.Price, tt.expectedPrice)
+		})
+	}
+}
+
+func TestEditAmount(t *testing.T) {
+	tests := []struct {
+		name           string
+		corporationId  uint64
+		amount         int
+		expectedError  string
+		expectedStored int
+		expectedListed int
+	}{
+		{
+			name:           "Raise Amount",
+			corporationId:  1,
+			amount:         500,
+			expectedStored: 0,
+			expectedListed: 500,
+		},
+		{
+			name:           "Raise Above Stock",
+			corporationId:  1,
+			amount:         501,
+			expectedError:  "error: not enough resources on base",
+			expectedStored: 200,
+			expectedListed: 300,
+		},
+		{
+			name:           "Cut Amount",
+			corporationId:  1,
+			amount:         100,
+			expectedStored: 400,
+			expectedListed: 100,
+		},
+		{
+			name:           "Not The Seller",
+			corporationId:  2,
+			amount:         100,
+			expectedError:  "error: you can not edit listing",
+			expectedStored: 200,
+			expectedListed: 300,
+		},
+		{
+			name:           "Above Max Amount",
+			corporationId:  1,
+			amount:         maxListingAmount + 1,
+			expectedError:  "error: amount should be between 1",
+			expectedStored: 200,
+			expectedListed: 300,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z, tc := createTestZone(t, map[uint64]*testCorporation{
+				1: {resources: map[string]int{"iron": 500}},
+				2: {resources: map[string]int{"iron": 500}},
+			})
+
+			id, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 300, Price: 90, CorporationId: 1})
+			assert.NilError(t, err)
+
+			err = z.editAmount(id, tt.corporationId, tt.amount)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+
+			ml, _ := z.book.get(id)
+			assert.Equal(t, ml.Amount, tt.expectedListed)
+			assert.Equal(t, tc.get(1).resources["iron"], tt.expectedStored)
+			assert.Equal(t, z.ledger.Balance(gamecomm.MarketEscrow("Zone-1"))["iron"], float64(tt.expectedListed))
+		})
+	}
+}
+
+func TestFillOrders(t *testing.T) {
+	z, _ := createTestZone(t, map[uint64]*testCorporation{
+		1: {resources: map[string]int{"iron": 1_000}},
+		2: {resources: map[string]int{"iron": 1_000}},
+	})
+
+	cheap, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 30, Price: 50, CorporationId: 1})
+	assert.NilError(t, err)
+
+	expensive, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 1_000, Price: 200, CorporationId: 2})
+	assert.NilError(t, err)
+
+	orders := []gamecomm.PurchaseOrder{
+		{BuyerPlanetId: "Planet-1", Resource: "iron", Amount: 40, ListingIds: []string{cheap, expensive}},
+		// The cheap listing is sold out by the first order
+		{BuyerPlanetId: "Planet-2", Resource: "iron", Amount: 10, ListingIds: []string{cheap, expensive}},
+		// Planet-3 can only pay for 2 units
+		{BuyerPlanetId: "Planet-3", Resource: "iron", Amount: 10, ListingIds: []string{expensive}},
+	}
+	budgets := map[string]float64{"Planet-1": 10_000, "Planet-2": 10_000, "Planet-3": 500}
+
+	fills := z.fillOrders(orders, budgets)
+
+	assert.Equal(t, len(fills), 4)
+	assert.Equal(t, fills[0], gamecomm.PurchaseFill{Order: 0, ListingId: cheap, Requested: 30, Amount: 30, Price: 50})
+	assert.Equal(t, fills[1], gamecomm.PurchaseFill{Order: 0, ListingId: expensive, Requested: 10, Amount: 10, Price: 200})
+	assert.Equal(t, fills[2], gamecomm.PurchaseFill{Order: 1, ListingId: expensive, Requested: 10, Amount: 10, Price: 200})
+	assert.Equal(t, fills[3], gamecomm.PurchaseFill{Order: 2, ListingId: expensive, Requested: 2, Amount: 2, Price: 200})
+
+	assert.Equal(t, budgets["Planet-1"], 6_500.0)
+	assert.Equal(t, budgets["Planet-3"], 100.0)
+
+	_, ok := z.book.get(cheap)
+	assert.Equal(t, ok, false)
+
+	ml, _ := z.book.get(expensive)
+	assert.Equal(t, ml.Amount, 978)
+}
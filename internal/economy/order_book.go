@@ -0,0 +1,195 @@
+package economy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// orderBook keeps the market listings of a zone by id and, for every resource, sorted from the cheapest to the most
+// expensive. Listings with the same price keep the order they were listed in.
+type orderBook struct {
+	listings   map[string]*MarketListing
+	byResource map[string][]*MarketListing
+	history    map[string][]gamecomm.ListingEdit
+	sequence   int
+}
+
+func newOrderBook() *orderBook {
+	return &orderBook{
+		listings:   make(map[string]*MarketListing),
+		byResource: make(map[string][]*MarketListing),
+		history:    make(map[string][]gamecomm.ListingEdit),
+	}
+}
+
+// before is the order of the resource index
+func (ml *MarketListing) before(other *MarketListing) bool {
+	if ml.Price != other.Price {
+		return ml.Price < other.Price
+	}
+
+	return ml.sequence < other.sequence
+}
+
+func (ob *orderBook) add(ml MarketListing) {
+	ob.sequence++
+	ml.sequence = ob.sequence
+
+	listing := &ml
+	ob.listings[ml.Id] = listing
+	ob.index(listing)
+}
+
+func (ob *orderBook) index(listing *MarketListing) {
+	resourceListings := ob.byResource[listing.ResourceName]
+
+	i := sort.Search(len(resourceListings), func(i int) bool {
+		return listing.before(resourceListings[i])
+	})
+
+	resourceListings = append(resourceListings, nil)
+	copy(resourceListings[i+1:], resourceListings[i:])
+	resourceListings[i] = listing
+
+	ob.byResource[listing.ResourceName] = resourceListings
+}
+
+func (ob *orderBook) unindex(listing *MarketListing) {
+	resourceListings := ob.byResource[listing.ResourceName]
+
+	i := sort.Search(len(resourceListings), func(i int) bool {
+		return !resourceListings[i].before(listing)
+	})
+
+	if i == len(resourceListings) || resourceListings[i] != listing {
+		return
+	}
+
+	ob.byResource[listing.ResourceName] = append(resourceListings[:i], resourceListings[i+1:]...)
+}
+
+func (ob *orderBook) get(listingId string) (MarketListing, bool) {
+	listing, ok := ob.listings[listingId]
+	if !ok {
+		return MarketListing{}, false
+	}
+
+	return *listing, true
+}
+
+// all returns a copy of every listing grouped by resource and sorted by price
+func (ob *orderBook) all() []MarketListing {
+	resources := make([]string, 0, len(ob.byResource))
+	for resourceName := range ob.byResource {
+		resources = append(resources, resourceName)
+	}
+	sort.Strings(resources)
+
+	marketListings := make([]MarketListing, 0, len(ob.listings))
+	for _, resourceName := range resources {
+		marketListings = append(marketListings, ob.resourceListings(resourceName)...)
+	}
+
+	return marketListings
+}
+
+// resourceListings returns a copy of the listings of the resource sorted by price
+func (ob *orderBook) resourceListings(resourceName string) []MarketListing {
+	resourceListings := ob.byResource[resourceName]
+
+	marketListings := make([]MarketListing, 0, len(resourceListings))
+	for _, ml := range resourceListings {
+		marketListings = append(marketListings, *ml)
+	}
+
+	return marketListings
+}
+
+// removeAmount takes up to amount from the listing and removes it once it's empty, returns the amount taken
+func (ob *orderBook) removeAmount(listingId string, amount int) (int, error) {
+	listing, ok := ob.listings[listingId]
+	if !ok {
+		return 0, fmt.Errorf("error: listing not found with ID '%s'", listingId)
+	}
+
+	amount = min(amount, listing.Amount)
+	listing.Amount -= amount
+
+	if listing.Amount == 0 {
+		ob.unindex(listing)
+		delete(ob.listings, listingId)
+	}
+
+	return amount, nil
+}
+
+// owned returns the listing if it belongs to the corporation
+func (ob *orderBook) owned(listingId string, corporationId uint64) (*MarketListing, error) {
+	listing, ok := ob.listings[listingId]
+	if !ok {
+		return nil, fmt.Errorf("error: listing not found with ID '%s'", listingId)
+	}
+
+	if listing.CorporationId != corporationId {
+		return nil, fmt.Errorf("error: you can not edit listing with ID '%s'", listingId)
+	}
+
+	return listing, nil
+}
+
+func (ob *orderBook) setPrice(listingId string, corporationId uint64, price float64, t gameclock.GameTime) error {
+	listing, err := ob.owned(listingId, corporationId)
+	if err != nil {
+		return err
+	}
+
+	ob.record(listing, "price", listing.Price, price, t)
+
+	ob.unindex(listing)
+	listing.Price = price
+	ob.index(listing)
+
+	return nil
+}
+
+func (ob *orderBook) setAmount(listingId string, corporationId uint64, amount int, t gameclock.GameTime) error {
+	listing, err := ob.owned(listingId, corporationId)
+	if err != nil {
+		return err
+	}
+
+	ob.record(listing, "amount", float64(listing.Amount), float64(amount), t)
+
+	listing.Amount = amount
+
+	return nil
+}
+
+// record keeps the edit in the listing history, the history stays after the listing is sold out
+func (ob *orderBook) record(listing *MarketListing, field string, oldValue float64, newValue float64, t gameclock.GameTime) {
+	ob.history[listing.Id] = append(ob.history[listing.Id], gamecomm.ListingEdit{
+		ListingId:     listing.Id,
+		CorporationId: listing.CorporationId,
+		Field:         field,
+		OldValue:      oldValue,
+		NewValue:      newValue,
+		Time:          t,
+	})
+}
+
+func (ob *orderBook) editHistory(listingId string) ([]gamecomm.ListingEdit, error) {
+	edits, ok := ob.history[listingId]
+	if !ok {
+		if _, ok := ob.listings[listingId]; !ok {
+			return nil, fmt.Errorf("error: listing not found with ID '%s'", listingId)
+		}
+	}
+
+	history := make([]gamecomm.ListingEdit, len(edits))
+	copy(history, edits)
+
+	return history, nil
+}
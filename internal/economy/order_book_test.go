@@ -0,0 +1,165 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+)
+
+func TestOrderBookOrder(t *testing.T) {
+	type priceEdit struct {
+		listingId string
+		price     float64
+	}
+
+	tests := []struct {
+		name        string
+		listings    []MarketListing
+		edits       []priceEdit
+		expectedIds []string
+	}{
+		{
+			name: "Cheapest First",
+			listings: []MarketListing{
+				{Id: "a", ResourceName: "iron", Amount: 10, Price: 120, CorporationId: 1},
+				{Id: "b", ResourceName: "iron", Amount: 10, Price: 80, CorporationId: 1},
+				{Id: "c", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1},
+			},
+			expectedIds: []string{"b", "c", "a"},
+		},
+		{
+			name: "Oldest First On The Same Price",
+			listings: []MarketListing{
+				{Id: "a", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1},
+				{Id: "b", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 2},
+				{Id: "c", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1},
+			},
+			expectedIds: []string{"a", "b", "c"},
+		},
+		{
+			name: "Price Edit Moves The Listing",
+			listings: []MarketListing{
+				{Id: "a", ResourceName: "iron", Amount: 10, Price: 80, CorporationId: 1},
+				{Id: "b", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1},
+				{Id: "c", ResourceName: "iron", Amount: 10, Price: 120, CorporationId: 1},
+			},
+			edits:       []priceEdit{{listingId: "a", price: 110}},
+			expectedIds: []string{"b", "a", "c"},
+		},
+		{
+			// The edited listing keeps its place in the listing order
+			name: "Price Edit Keeps The Sequence",
+			listings: []MarketListing{
+				{Id: "a", ResourceName: "iron", Amount: 10, Price: 80, CorporationId: 1},
+				{Id: "b", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1},
+			},
+			edits:       []priceEdit{{listingId: "a", price: 100}},
+			expectedIds: []string{"a", "b"},
+		},
+		{
+			name: "Other Resources Apart",
+			listings: []MarketListing{
+				{Id: "a", ResourceName: "water", Amount: 10, Price: 5, CorporationId: 1},
+				{Id: "b", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1},
+			},
+			expectedIds: []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ob := newOrderBook()
+
+			for _, ml := range tt.listings {
+				ob.add(ml)
+			}
+
+			for _, edit := range tt.edits {
+				assert.NilError(t, ob.setPrice(edit.listingId, 1, edit.price, 0))
+			}
+
+			ids := []string{}
+			for _, ml := range ob.resourceListings("iron") {
+				ids = append(ids, ml.Id)
+			}
+
+			assert.Equal(t, len(ids), len(tt.expectedIds))
+			for i, id := range tt.expectedIds {
+				assert.Equal(t, ids[i], id)
+			}
+		})
+	}
+}
+
+func TestOrderBookRemoveAmount(t *testing.T) {
+	tests := []struct {
+		name            string
+		listingId       string
+		amount          int
+		expectedTaken   int
+		expectedLeft    int
+		expectedListing bool
+		expectedError   string
+	}{
+		{name: "Part Of The Listing", listingId: "a", amount: 4, expectedTaken: 4, expectedLeft: 6, expectedListing: true},
+		{name: "Whole Listing", listingId: "a", amount: 10, expectedTaken: 10},
+		{name: "More Than Listed", listingId: "a", amount: 15, expectedTaken: 10},
+		{name: "Listing Not Found", listingId: "b", amount: 4, expectedLeft: 10, expectedListing: true, expectedError: "error: listing not found with ID 'b'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ob := newOrderBook()
+			ob.add(MarketListing{Id: "a", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1})
+
+			taken, err := ob.removeAmount(tt.listingId, tt.amount)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+
+			assert.Equal(t, taken, tt.expectedTaken)
+
+			ml, ok := ob.get("a")
+			assert.Equal(t, ok, tt.expectedListing)
+			assert.Equal(t, ml.Amount, tt.expectedLeft)
+
+			// Sold out listings are dropped from the resource index too
+			if !tt.expectedListing {
+				assert.Equal(t, len(ob.resourceListings("iron")), 0)
+			}
+		})
+	}
+}
+
+func TestOrderBookEditHistory(t *testing.T) {
+	ob := newOrderBook()
+	ob.add(MarketListing{Id: "a", ResourceName: "iron", Amount: 10, Price: 100, CorporationId: 1})
+
+	err := ob.setPrice("a", 2, 90, 0)
+	assert.Error(t, err)
+	assert.StringContains(t, err.Error(), "error: you can not edit listing with ID 'a'")
+
+	assert.NilError(t, ob.setPrice("a", 1, 90, 1))
+	assert.NilError(t, ob.setAmount("a", 1, 20, 2))
+
+	_, err = ob.removeAmount("a", 20)
+	assert.NilError(t, err)
+
+	// The history stays after the listing sold out
+	history, err := ob.editHistory("a")
+	assert.NilError(t, err)
+	assert.Equal(t, len(history), 2)
+	assert.Equal(t, history[0].Field, "price")
+	assert.Equal(t, history[0].OldValue, 100.0)
+	assert.Equal(t, history[0].NewValue, 90.0)
+	assert.Equal(t, history[1].Field, "amount")
+	assert.Equal(t, history[1].OldValue, 10.0)
+	assert.Equal(t, history[1].NewValue, 20.0)
+
+	_, err = ob.editHistory("b")
+	assert.Error(t, err)
+	assert.StringContains(t, err.Error(), "error: listing not found with ID 'b'")
+}
@@ -0,0 +1,70 @@
+package economy
+
+import (
+	"math"
+
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+// rarityReaction is how much more the price of a resource reacts for every rarity level
+const rarityReaction = 0.25
+
+var defaultPriceModel = resource.PriceModel{
+	Elasticity:     0.05,
+	Volatility:     0.1,
+	MinPriceFactor: 0.25,
+	MaxPriceFactor: 4,
+}
+
+func priceModel(r resource.Resource) resource.PriceModel {
+	pm := r.PriceModel
+
+	if pm.Elasticity <= 0 {
+		pm.Elasticity = defaultPriceModel.Elasticity
+	}
+
+	if pm.Volatility <= 0 {
+		pm.Volatility = defaultPriceModel.Volatility
+	}
+
+	if pm.MinPriceFactor <= 0 {
+		pm.MinPriceFactor = defaultPriceModel.MinPriceFactor
+	}
+
+	if pm.MaxPriceFactor < pm.MinPriceFactor {
+		pm.MaxPriceFactor = max(defaultPriceModel.MaxPriceFactor, pm.MinPriceFactor)
+	}
+
+	return pm
+}
+
+// supplyDemandImbalance goes from -1 when there is only supply to 1 when there is only demand
+func supplyDemandImbalance(supply int, demand int) float64 {
+	if supply+demand == 0 {
+		return 0
+	}
+
+	return float64(demand-supply) / float64(demand+supply)
+}
+
+// priceChange is the share the price moves in a day for the supply and demand imbalance. Rarer resources react
+// more and the move is limited by the volatility.
+func priceChange(r resource.Resource, supply int, demand int) float64 {
+	pm := priceModel(r)
+
+	change := supplyDemandImbalance(supply, demand) * pm.Elasticity * (1 + rarityReaction*float64(r.Rarity))
+
+	return math.Max(math.Min(change, pm.Volatility), -pm.Volatility)
+}
+
+// clampPrice keeps the price between the floor and ceiling of the resource
+func clampPrice(r resource.Resource, price float64) float64 {
+	pm := priceModel(r)
+
+	return math.Max(math.Min(price, r.BasePrice*pm.MaxPriceFactor), r.BasePrice*pm.MinPriceFactor)
+}
+
+// adjustPrice moves the price by the supply and demand imbalance of the day
+func adjustPrice(r resource.Resource, currentPrice float64, supply int, demand int) float64 {
+	return clampPrice(r, currentPrice*(1+priceChange(r, supply, demand)))
+}
@@ -0,0 +1,107 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+func TestAdjustPrice(t *testing.T) {
+	iron := resource.Resource{Name: "iron", BasePrice: 100, Rarity: resource.Common}
+
+	tests := []struct {
+		name     string
+		resource resource.Resource
+		price    float64
+		supply   int
+		demand   int
+		wants    float64
+	}{
+		{
+			name:     "No Trades",
+			resource: iron,
+			price:    100,
+			wants:    100,
+		},
+		{
+			name:     "Balanced",
+			resource: iron,
+			price:    100,
+			supply:   500,
+			demand:   500,
+			wants:    100,
+		},
+		{
+			name:     "Only Supply",
+			resource: iron,
+			price:    100,
+			supply:   500,
+			wants:    93.75,
+		},
+		{
+			name:     "Only Demand",
+			resource: iron,
+			price:    100,
+			demand:   500,
+			wants:    106.25,
+		},
+		{
+			name:     "Price Floor",
+			resource: iron,
+			price:    26,
+			supply:   500,
+			wants:    25,
+		},
+		{
+			name:     "Price Ceiling",
+			resource: iron,
+			price:    395,
+			demand:   500,
+			wants:    400,
+		},
+		{
+			name: "Limited By Volatility",
+			resource: resource.Resource{
+				Name:       "gold",
+				BasePrice:  100,
+				Rarity:     resource.Common,
+				PriceModel: resource.PriceModel{Elasticity: 1, Volatility: 0.25},
+			},
+			price:  100,
+			demand: 500,
+			wants:  125,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, adjustPrice(tt.resource, tt.price, tt.supply, tt.demand), tt.wants)
+		})
+	}
+}
+
+func TestUpdatePrices(t *testing.T) {
+	z, _ := createTestZone(t, map[uint64]*testCorporation{
+		1: {resources: map[string]int{"iron": 1_000}},
+	})
+
+	day := z.gameClock.GetCurrentTime()
+
+	id, err := z.addMarketListing(MarketListing{ResourceName: "iron", Amount: 300, Price: 90, CorporationId: 1, ListTime: day})
+	assert.NilError(t, err)
+
+	z.fillOrders([]gamecomm.PurchaseOrder{
+		{BuyerPlanetId: "Planet-1", Resource: "iron", Amount: 100, ListingIds: []string{id}},
+	}, map[string]float64{"Planet-1": 100_000})
+
+	assert.Equal(t, z.analytics.calculateDailySupply("iron", day), 300)
+	assert.Equal(t, z.analytics.calculateDailyDemand("iron", day), 100)
+
+	z.updatePrices(day)
+
+	// More was listed than sold so the price goes down, nothing was traded for water
+	assert.Equal(t, z.prices["iron"], 96.875)
+	assert.Equal(t, z.prices["water"], 10.0)
+}
@@ -0,0 +1,221 @@
+package economy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// shareOrder is an order resting on the book of a stock. Buy orders hold the credits of the buyer and sell orders
+// hold the shares of the seller until they are filled or cancelled.
+type shareOrder struct {
+	id               string
+	corporationId    uint64
+	issuerId         uint64
+	side             gamecomm.OrderSide
+	amount           int
+	price            float64
+	sequence         int
+	notificationChan chan string
+}
+
+func (o *shareOrder) copy() gamecomm.ShareOrder {
+	return gamecomm.ShareOrder{
+		Id:            o.id,
+		IssuerId:      o.issuerId,
+		CorporationId: o.corporationId,
+		Side:          o.side,
+		Amount:        o.amount,
+		Price:         o.price,
+	}
+}
+
+// placeOrder holds the credits or shares of the order, fills it against the book and leaves the rest on the book.
+// Orders fill at the price of the order they match, the best price first and the oldest first on the same price.
+func (x *stockExchange) placeOrder(corporationId uint64, issuerId uint64, side gamecomm.OrderSide, amount int, price float64, notificationChan chan string) (string, error) {
+	s, ok := x.stocks[issuerId]
+	if !ok {
+		return "", fmt.Errorf("error: corporation %d has not issued shares", issuerId)
+	}
+
+	if amount <= 0 {
+		return "", fmt.Errorf("error: amount should be greater than zero")
+	}
+
+	if price <= 0 {
+		return "", fmt.Errorf("error: price should be greater than zero")
+	}
+
+	switch side {
+	case gamecomm.Buy:
+		err := x.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.RemoveCredits,
+			CorporationId: corporationId,
+			AmountDecimal: price * float64(amount),
+			Counterparty:  exchangeEscrow,
+			Memo:          "share buy order",
+		})
+		if err != nil {
+			return "", err
+		}
+	case gamecomm.Sell:
+		if s.holdings[corporationId] < amount {
+			return "", fmt.Errorf("error: not enough shares")
+		}
+
+		s.holdings[corporationId] -= amount
+	default:
+		return "", fmt.Errorf("error: wrong order side")
+	}
+
+	x.orderCounter++
+	o := &shareOrder{
+		id:               fmt.Sprintf("share-order-%d", x.orderCounter),
+		corporationId:    corporationId,
+		issuerId:         issuerId,
+		side:             side,
+		amount:           amount,
+		price:            price,
+		sequence:         x.orderCounter,
+		notificationChan: notificationChan,
+	}
+
+	x.match(s, o)
+
+	if o.amount > 0 {
+		s.insert(o)
+		x.orders[o.id] = o
+	}
+
+	return o.id, nil
+}
+
+func (x *stockExchange) match(s *stock, o *shareOrder) {
+	if o.side == gamecomm.Buy {
+		for o.amount > 0 && len(s.asks) > 0 && s.asks[0].price <= o.price {
+			ask := s.asks[0]
+			x.trade(s, o, ask, ask.price)
+
+			if ask.amount == 0 {
+				s.asks = s.asks[1:]
+				delete(x.orders, ask.id)
+			}
+		}
+
+		return
+	}
+
+	for o.amount > 0 && len(s.bids) > 0 && s.bids[0].price >= o.price {
+		bid := s.bids[0]
+		x.trade(s, bid, o, bid.price)
+
+		if bid.amount == 0 {
+			s.bids = s.bids[1:]
+			delete(x.orders, bid.id)
+		}
+	}
+}
+
+// trade moves the shares to the buyer and the credits to the seller, buyers get back what they held over the price
+func (x *stockExchange) trade(s *stock, buy *shareOrder, sell *shareOrder, price float64) {
+	amount := min(buy.amount, sell.amount)
+
+	buy.amount -= amount
+	sell.amount -= amount
+	s.holdings[buy.corporationId] += amount
+	s.price = price
+
+	err := x.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.AddCredits,
+		CorporationId: sell.corporationId,
+		AmountDecimal: price * float64(amount),
+		Counterparty:  exchangeEscrow,
+		Memo:          "share sale",
+	})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+
+	if buy.price > price {
+		err = x.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddCredits,
+			CorporationId: buy.corporationId,
+			AmountDecimal: (buy.price - price) * float64(amount),
+			Counterparty:  exchangeEscrow,
+			Memo:          "share buy order surplus",
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	notify(buy.notificationChan, fmt.Sprintf("Exchange Notification: bought %d shares of %v at %.2f", amount, s.name, price))
+	notify(sell.notificationChan, fmt.Sprintf("Exchange Notification: sold %d shares of %v at %.2f", amount, s.name, price))
+}
+
+// cancelOrder takes the order off the book and gives back what it held
+func (x *stockExchange) cancelOrder(orderId string, corporationId uint64) error {
+	o, ok := x.orders[orderId]
+	if !ok {
+		return fmt.Errorf("error: share order not found with ID '%s'", orderId)
+	}
+
+	if o.corporationId != corporationId {
+		return fmt.Errorf("error: share order '%s' doesn't belong to corporation %d", orderId, corporationId)
+	}
+
+	s := x.stocks[o.issuerId]
+
+	if o.side == gamecomm.Buy {
+		err := x.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddCredits,
+			CorporationId: corporationId,
+			AmountDecimal: o.price * float64(o.amount),
+			Counterparty:  exchangeEscrow,
+			Memo:          "share order cancelled",
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		s.holdings[corporationId] += o.amount
+	}
+
+	s.remove(o)
+	delete(x.orders, orderId)
+
+	return nil
+}
+
+// insert keeps the bids from the highest to the lowest price and the asks from the lowest to the highest, orders
+// with the same price stay in the order they arrived
+func (s *stock) insert(o *shareOrder) {
+	if o.side == gamecomm.Buy {
+		i := sort.Search(len(s.bids), func(i int) bool {
+			return s.bids[i].price < o.price
+		})
+		s.bids = append(s.bids[:i], append([]*shareOrder{o}, s.bids[i:]...)...)
+
+		return
+	}
+
+	i := sort.Search(len(s.asks), func(i int) bool {
+		return s.asks[i].price > o.price
+	})
+	s.asks = append(s.asks[:i], append([]*shareOrder{o}, s.asks[i:]...)...)
+}
+
+func (s *stock) remove(o *shareOrder) {
+	orders := &s.asks
+	if o.side == gamecomm.Buy {
+		orders = &s.bids
+	}
+
+	for i, so := range *orders {
+		if so == o {
+			*orders = append((*orders)[:i], (*orders)[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package economy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// fundamentalWeight is how much the share price moves every day towards the value of the corporation
+	fundamentalWeight = 0.2
+	// maxGrowth caps how much a day of net worth growth or loss moves the value of the shares
+	maxGrowth     = 0.5
+	minSharePrice = 0.01
+	maxShares     = 1_000_000_000
+)
+
+// stock is the shares of a corporation, holdings are the shares each corporation owns outside of its sell orders
+type stock struct {
+	issuerId uint64
+	name     string
+	shares   int
+	price    float64
+	netWorth float64
+	dividend float64
+	holdings map[uint64]int
+	bids     []*shareOrder
+	asks     []*shareOrder
+}
+
+func (s *stock) copy() gamecomm.Stock {
+	return gamecomm.Stock{
+		IssuerId:  s.issuerId,
+		Name:      s.name,
+		Shares:    s.shares,
+		Price:     s.price,
+		MarketCap: s.price * float64(s.shares),
+		NetWorth:  s.netWorth,
+		Dividend:  s.dividend,
+	}
+}
+
+// owners returns the shares of every corporation, the ones held in sell orders included
+func (s *stock) owners() map[uint64]int {
+	owners := make(map[uint64]int, len(s.holdings))
+	for corporationId, shares := range s.holdings {
+		if shares > 0 {
+			owners[corporationId] += shares
+		}
+	}
+
+	for _, o := range s.asks {
+		owners[o.corporationId] += o.amount
+	}
+
+	return owners
+}
+
+// stockExchange owns the shares of every corporation. Like the zone markets it runs on its own goroutine and
+// everything else sends it tasks.
+type stockExchange struct {
+	stocks       map[uint64]*stock
+	orders       map[string]*shareOrder
+	orderCounter int
+	gameChannels gamecomm.GameChannels
+	tasks        chan func(x *stockExchange)
+}
+
+func newStockExchange(gameChannels gamecomm.GameChannels) *stockExchange {
+	return &stockExchange{
+		stocks:       make(map[uint64]*stock),
+		orders:       make(map[string]*shareOrder),
+		gameChannels: gameChannels,
+		tasks:        make(chan func(x *stockExchange), zoneTaskBuffer),
+	}
+}
+
+func (x *stockExchange) run() {
+	for task := range x.tasks {
+		task(x)
+	}
+}
+
+func (x *stockExchange) handle(command gamecomm.EconomyCommand) {
+	defer close(command.ResponseChannel)
+
+	switch command.Action {
+	case gamecomm.IssueShares:
+		id, err := x.issueShares(command.CorporationId, command.Amount, command.Price, command.NotificationChan)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: id}
+	case gamecomm.PlaceShareOrder:
+		id, err := x.placeOrder(command.CorporationId, command.IssuerId, command.OrderSide, command.Amount, command.Price, command.NotificationChan)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: id}
+	case gamecomm.CancelShareOrder:
+		err := x.cancelOrder(command.OrderId, command.CorporationId)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: "OK"}
+	case gamecomm.PayDividend:
+		paid, err := x.payDividend(command.CorporationId, command.Price)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: paid}
+	case gamecomm.GetStocks:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: x.getStocks()}
+	case gamecomm.GetPortfolio:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: x.getPortfolio(command.CorporationId)}
+	default:
+		command.ResponseChannel <- gamecomm.ChanResponse{Err: fmt.Errorf("error: wrong action")}
+	}
+}
+
+// issueShares creates new shares of the corporation and offers them on the exchange at the price. The first issue
+// lists the corporation, the next ones dilute the shareholders.
+func (x *stockExchange) issueShares(issuerId uint64, amount int, price float64, notificationChan chan string) (string, error) {
+	if amount <= 0 {
+		return "", fmt.Errorf("error: amount should be greater than zero")
+	}
+
+	if price <= 0 {
+		return "", fmt.Errorf("error: price should be greater than zero")
+	}
+
+	s, ok := x.stocks[issuerId]
+	if !ok {
+		corp, err := x.getCorporation(issuerId)
+		if err != nil {
+			return "", err
+		}
+
+		s = &stock{
+			issuerId: issuerId,
+			name:     corp.Name,
+			price:    price,
+			netWorth: corp.Credits,
+			holdings: make(map[uint64]int),
+		}
+	}
+
+	if s.shares+amount > maxShares {
+		return "", fmt.Errorf("error: a corporation can not have more than %d shares", maxShares)
+	}
+
+	x.stocks[issuerId] = s
+	s.shares += amount
+	s.holdings[issuerId] += amount
+
+	return x.placeOrder(issuerId, issuerId, gamecomm.Sell, amount, price, notificationChan)
+}
+
+// payDividend pays the credits per share from the issuer to every other shareholder and returns the credits paid
+func (x *stockExchange) payDividend(issuerId uint64, perShare float64) (float64, error) {
+	s, ok := x.stocks[issuerId]
+	if !ok {
+		return 0, fmt.Errorf("error: corporation %d has not issued shares", issuerId)
+	}
+
+	if perShare <= 0 {
+		return 0, fmt.Errorf("error: dividend should be greater than zero")
+	}
+
+	owners := s.owners()
+	delete(owners, issuerId)
+
+	shares := 0
+	for _, amount := range owners {
+		shares += amount
+	}
+
+	if shares == 0 {
+		return 0, fmt.Errorf("error: there are no shareholders")
+	}
+
+	total := perShare * float64(shares)
+
+	err := x.corpCommand(gamecomm.CorpCommand{
+		Action:        gamecomm.RemoveCredits,
+		CorporationId: issuerId,
+		AmountDecimal: total,
+		Counterparty:  dividendEscrow(issuerId),
+		Memo:          "dividend paid",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for corporationId, amount := range owners {
+		err = x.corpCommand(gamecomm.CorpCommand{
+			Action:        gamecomm.AddCredits,
+			CorporationId: corporationId,
+			AmountDecimal: perShare * float64(amount),
+			Counterparty:  dividendEscrow(issuerId),
+			Memo:          "dividend received",
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	s.dividend = perShare
+
+	return total, nil
+}
+
+// updateSharePrices moves every share price towards the net worth per share of the corporation, corporations with a
+// growing net worth are worth more than their net worth
+func (x *stockExchange) updateSharePrices(prices resourcePrices) {
+	for _, s := range x.stocks {
+		corp, err := x.getCorporation(s.issuerId)
+		if err != nil {
+			fmt.Println(err.Error())
+			continue
+		}
+
+		netWorth := corporationNetWorth(corp, prices)
+		s.price = sharePrice(s.price, netWorth, s.netWorth, s.shares)
+		s.netWorth = netWorth
+	}
+}
+
+func sharePrice(price float64, netWorth float64, previousNetWorth float64, shares int) float64 {
+	if shares == 0 {
+		return price
+	}
+
+	growth := 0.0
+	if previousNetWorth > 0 {
+		growth = max(min((netWorth-previousNetWorth)/previousNetWorth, maxGrowth), -maxGrowth)
+	}
+
+	target := max(netWorth/float64(shares), minSharePrice) * (1 + growth)
+
+	return max(price+fundamentalWeight*(target-price), minSharePrice)
+}
+
+// corporationNetWorth is the credits of the corporation plus the value of the resources stored on its bases
+func corporationNetWorth(corp gamecomm.Corporation, prices resourcePrices) float64 {
+	netWorth := corp.Credits
+	for _, b := range corp.Bases {
+		for resourceName, amount := range b.StoredResources {
+			netWorth += float64(amount) * prices[resourceName]
+		}
+	}
+
+	return netWorth
+}
+
+// averagePrices returns the average price of every resource over the zones
+func averagePrices(zonePrices map[string]resourcePrices) resourcePrices {
+	averages := make(resourcePrices)
+	if len(zonePrices) == 0 {
+		return averages
+	}
+
+	for _, prices := range zonePrices {
+		for resourceName, price := range prices {
+			averages[resourceName] += price
+		}
+	}
+
+	for resourceName, total := range averages {
+		averages[resourceName] = total / float64(len(zonePrices))
+	}
+
+	return averages
+}
+
+func (x *stockExchange) getStocks() []gamecomm.Stock {
+	stocks := make([]gamecomm.Stock, 0, len(x.stocks))
+	for _, s := range x.stocks {
+		stocks = append(stocks, s.copy())
+	}
+
+	sort.Slice(stocks, func(i, j int) bool {
+		return stocks[i].IssuerId < stocks[j].IssuerId
+	})
+
+	return stocks
+}
+
+func (x *stockExchange) getPortfolio(corporationId uint64) gamecomm.Portfolio {
+	portfolio := gamecomm.Portfolio{
+		CorporationId: corporationId,
+		Holdings:      []gamecomm.ShareHolding{},
+		Orders:        []gamecomm.ShareOrder{},
+	}
+
+	for _, s := range x.stocks {
+		shares := s.owners()[corporationId]
+		if shares == 0 {
+			continue
+		}
+
+		value := s.price * float64(shares)
+		portfolio.Holdings = append(portfolio.Holdings, gamecomm.ShareHolding{
+			IssuerId: s.issuerId,
+			Name:     s.name,
+			Shares:   shares,
+			Price:    s.price,
+			Value:    value,
+		})
+		portfolio.Value += value
+	}
+
+	sequences := make(map[string]int)
+	for _, o := range x.orders {
+		if o.corporationId != corporationId {
+			continue
+		}
+
+		portfolio.Orders = append(portfolio.Orders, o.copy())
+		sequences[o.id] = o.sequence
+	}
+
+	sort.Slice(portfolio.Holdings, func(i, j int) bool {
+		return portfolio.Holdings[i].IssuerId < portfolio.Holdings[j].IssuerId
+	})
+
+	sort.Slice(portfolio.Orders, func(i, j int) bool {
+		return sequences[portfolio.Orders[i].Id] < sequences[portfolio.Orders[j].Id]
+	})
+
+	return portfolio
+}
+
+func (x *stockExchange) getCorporation(corporationId uint64) (gamecomm.Corporation, error) {
+	res := sendCorpCommand(x.gameChannels.CorpChannel, gamecomm.CorpCommand{
+		Action:        gamecomm.GetCorporation,
+		CorporationId: corporationId,
+	})
+	if res.Err != nil {
+		return gamecomm.Corporation{}, res.Err
+	}
+
+	return res.Val.(gamecomm.Corporation), nil
+}
+
+func (x *stockExchange) corpCommand(command gamecomm.CorpCommand) error {
+	return sendCorpCommand(x.gameChannels.CorpChannel, command).Err
+}
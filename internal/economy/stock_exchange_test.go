@@ -0,0 +1,276 @@
+package economy
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// testShareOrder is an order on the shares of corporation 1 placed by a test
+type testShareOrder struct {
+	corporationId uint64
+	side          gamecomm.OrderSide
+	amount        int
+	price         float64
+}
+
+// createTestStock lists corporation 1 with 50 shares offered at 10 credits, corporations 2 and 3 start with 1,000
+// credits. The orders are placed after the listing.
+func createTestStock(t *testing.T, orders ...testShareOrder) (*stockExchange, *testCorporations) {
+	t.Helper()
+
+	gameChannels, tc, _ := createTestCorporations(t, map[uint64]*testCorporation{
+		1: {},
+		2: {credits: 1_000},
+		3: {credits: 1_000},
+	})
+
+	x := newStockExchange(gameChannels)
+
+	_, err := x.issueShares(1, 50, 10, nil)
+	assert.NilError(t, err)
+
+	for _, o := range orders {
+		_, err = x.placeOrder(o.corporationId, 1, o.side, o.amount, o.price, nil)
+		assert.NilError(t, err)
+	}
+
+	return x, tc
+}
+
+// assertExchangeEscrow checks the exchange escrow only holds the credits of the buy orders left on the book
+func assertExchangeEscrow(t *testing.T, x *stockExchange, tc *testCorporations) {
+	t.Helper()
+
+	held := 0.0
+	for _, s := range x.stocks {
+		for _, o := range s.bids {
+			held += o.price * float64(o.amount)
+		}
+	}
+
+	assert.Equal(t, tc.ledger.Balance(exchangeEscrow)[gamecomm.CreditsAsset], held)
+}
+
+func TestPlaceShareOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    []testShareOrder
+		order    testShareOrder
+		credits  map[uint64]float64
+		holdings map[uint64]int
+		bids     int
+		asks     int
+		price    float64
+	}{
+		{
+			name:     "Buy Under The Ask",
+			order:    testShareOrder{2, gamecomm.Buy, 10, 5},
+			credits:  map[uint64]float64{1: 0, 2: 950},
+			holdings: map[uint64]int{2: 0},
+			bids:     1,
+			asks:     1,
+			price:    10,
+		},
+		{
+			// The buyer gets back what it offered over the ask
+			name:     "Buy Fills At The Ask Price",
+			order:    testShareOrder{2, gamecomm.Buy, 20, 12},
+			credits:  map[uint64]float64{1: 200, 2: 800},
+			holdings: map[uint64]int{2: 20},
+			asks:     1,
+			price:    10,
+		},
+		{
+			name:     "Buy More Than Offered",
+			order:    testShareOrder{2, gamecomm.Buy, 60, 10},
+			credits:  map[uint64]float64{1: 500, 2: 400},
+			holdings: map[uint64]int{2: 50},
+			bids:     1,
+			price:    10,
+		},
+		{
+			name:     "Sell Into The Best Bid",
+			setup:    []testShareOrder{{2, gamecomm.Buy, 20, 10}, {3, gamecomm.Buy, 5, 8}, {3, gamecomm.Buy, 5, 9}},
+			order:    testShareOrder{2, gamecomm.Sell, 10, 8},
+			credits:  map[uint64]float64{1: 200, 2: 885, 3: 915},
+			holdings: map[uint64]int{2: 10, 3: 10},
+			asks:     1,
+			price:    8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, tc := createTestStock(t, tt.setup...)
+
+			_, err := x.placeOrder(tt.order.corporationId, 1, tt.order.side, tt.order.amount, tt.order.price, nil)
+			assert.NilError(t, err)
+
+			s := x.stocks[1]
+			assert.Equal(t, len(s.bids), tt.bids)
+			assert.Equal(t, len(s.asks), tt.asks)
+			assert.Equal(t, s.price, tt.price)
+
+			for corporationId, credits := range tt.credits {
+				assert.Equal(t, tc.get(corporationId).credits, credits)
+			}
+
+			for corporationId, shares := range tt.holdings {
+				assert.Equal(t, s.holdings[corporationId], shares)
+			}
+
+			assertExchangeEscrow(t, x, tc)
+		})
+	}
+}
+
+func TestPlaceShareOrderErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		issuerId      uint64
+		order         testShareOrder
+		expectedError string
+	}{
+		{name: "Sell Without Shares", issuerId: 1, order: testShareOrder{3, gamecomm.Sell, 10, 10}, expectedError: "error: not enough shares"},
+		{name: "Not Enough Credits", issuerId: 1, order: testShareOrder{2, gamecomm.Buy, 200, 10}, expectedError: "error: not enough credits"},
+		{name: "Stock Not Listed", issuerId: 9, order: testShareOrder{2, gamecomm.Buy, 10, 10}, expectedError: "error: corporation 9 has not issued shares"},
+		{name: "Wrong Side", issuerId: 1, order: testShareOrder{2, gamecomm.OrderSide(7), 10, 10}, expectedError: "error: wrong order side"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, tc := createTestStock(t)
+
+			_, err := x.placeOrder(tt.order.corporationId, tt.issuerId, tt.order.side, tt.order.amount, tt.order.price, nil)
+			assert.Error(t, err)
+			assert.StringContains(t, err.Error(), tt.expectedError)
+
+			// Nothing moves on a rejected order
+			s := x.stocks[1]
+			assert.Equal(t, len(s.bids), 0)
+			assert.Equal(t, len(s.asks), 1)
+			assert.Equal(t, tc.get(2).credits, 1_000.0)
+			assert.Equal(t, tc.get(3).credits, 1_000.0)
+			assertExchangeEscrow(t, x, tc)
+		})
+	}
+}
+
+func TestCancelShareOrder(t *testing.T) {
+	tests := []struct {
+		name             string
+		order            testShareOrder
+		corporationId    uint64
+		orderId          string
+		expectedError    string
+		expectedCredits  float64
+		expectedHoldings int
+		expectedOrder    bool
+	}{
+		// A buy order under the asks or a sell order over them stays on the book
+		{name: "Cancel Buy Order", order: testShareOrder{2, gamecomm.Buy, 5, 5}, corporationId: 2, expectedCredits: 900, expectedHoldings: 10},
+		{name: "Cancel Sell Order", order: testShareOrder{2, gamecomm.Sell, 5, 20}, corporationId: 2, expectedCredits: 900, expectedHoldings: 10},
+		{name: "Order Of Another Corporation", order: testShareOrder{2, gamecomm.Buy, 5, 5}, corporationId: 3, expectedError: "doesn't belong to corporation 3", expectedCredits: 875, expectedHoldings: 10, expectedOrder: true},
+		{name: "Order Not Found", order: testShareOrder{2, gamecomm.Sell, 5, 20}, corporationId: 2, orderId: "share-order-9", expectedError: "error: share order not found with ID 'share-order-9'", expectedCredits: 900, expectedHoldings: 5, expectedOrder: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, tc := createTestStock(t, testShareOrder{2, gamecomm.Buy, 10, 10})
+
+			id, err := x.placeOrder(tt.order.corporationId, 1, tt.order.side, tt.order.amount, tt.order.price, nil)
+			assert.NilError(t, err)
+
+			orderId := id
+			if tt.orderId != "" {
+				orderId = tt.orderId
+			}
+
+			err = x.cancelOrder(orderId, tt.corporationId)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+
+			_, ok := x.orders[id]
+			assert.Equal(t, ok, tt.expectedOrder)
+			assert.Equal(t, tc.get(2).credits, tt.expectedCredits)
+			assert.Equal(t, x.stocks[1].holdings[2], tt.expectedHoldings)
+			assertExchangeEscrow(t, x, tc)
+		})
+	}
+}
+
+func TestPayDividend(t *testing.T) {
+	tests := []struct {
+		name            string
+		setup           []testShareOrder
+		perShare        float64
+		expectedError   string
+		expectedPaid    float64
+		expectedCredits map[uint64]float64
+	}{
+		{
+			// Shares waiting in sell orders still get the dividend, the issuer doesn't pay itself
+			name:            "Every Other Shareholder",
+			setup:           []testShareOrder{{2, gamecomm.Buy, 20, 10}, {3, gamecomm.Buy, 10, 10}, {2, gamecomm.Sell, 5, 20}},
+			perShare:        2,
+			expectedPaid:    60,
+			expectedCredits: map[uint64]float64{1: 240, 2: 840, 3: 920},
+		},
+		{name: "No Shareholders", perShare: 2, expectedError: "error: there are no shareholders", expectedCredits: map[uint64]float64{1: 0}},
+		{name: "Not Enough Credits", setup: []testShareOrder{{2, gamecomm.Buy, 20, 10}}, perShare: 20, expectedError: "error: not enough credits", expectedCredits: map[uint64]float64{1: 200, 2: 800}},
+		{name: "Zero Dividend", perShare: 0, expectedError: "error: dividend should be greater than zero", expectedCredits: map[uint64]float64{1: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, tc := createTestStock(t, tt.setup...)
+
+			paid, err := x.payDividend(1, tt.perShare)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.StringContains(t, err.Error(), tt.expectedError)
+				assert.Equal(t, x.stocks[1].dividend, 0.0)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, paid, tt.expectedPaid)
+				assert.Equal(t, x.stocks[1].dividend, tt.perShare)
+			}
+
+			for corporationId, credits := range tt.expectedCredits {
+				assert.Equal(t, tc.get(corporationId).credits, credits)
+			}
+
+			assert.Equal(t, len(tc.ledger.Balance(dividendEscrow(1))), 0)
+		})
+	}
+}
+
+func TestSharePrice(t *testing.T) {
+	tests := []struct {
+		name             string
+		price            float64
+		netWorth         float64
+		previousNetWorth float64
+		shares           int
+		wants            float64
+	}{
+		{name: "No Shares", price: 10, netWorth: 5_000, previousNetWorth: 1_000, wants: 10},
+		{name: "At The Net Worth", price: 10, netWorth: 1_000, previousNetWorth: 1_000, shares: 100, wants: 10},
+		{name: "Towards The Net Worth", price: 10, netWorth: 2_000, shares: 100, wants: 12},
+		{name: "Growth Adds To The Value", price: 10, netWorth: 1_250, previousNetWorth: 1_000, shares: 100, wants: 11.125},
+		{name: "Growth Is Capped", price: 10, netWorth: 4_000, previousNetWorth: 1_000, shares: 100, wants: 20},
+		{name: "Minimum Price", price: minSharePrice, netWorth: -1_000, shares: 100, wants: minSharePrice},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, sharePrice(tt.price, tt.netWorth, tt.previousNetWorth, tt.shares), tt.wants)
+		})
+	}
+}
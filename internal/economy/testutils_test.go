@@ -0,0 +1,268 @@
+package economy
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+// testCorporation is what the test corporations worker keeps for a corporation, resources are stored on the first
+// base and otherBases are the bases after it
+type testCorporation struct {
+	credits    float64
+	reputation int
+	resources  map[string]int
+	otherBases []map[string]int
+	squads     []gamecomm.Ship
+}
+
+func (c *testCorporation) base(baseIndex int) (map[string]int, error) {
+	if baseIndex == 0 {
+		return c.resources, nil
+	}
+
+	if baseIndex < 0 || baseIndex > len(c.otherBases) {
+		return nil, fmt.Errorf("error: base not found %v", baseIndex)
+	}
+
+	return c.otherBases[baseIndex-1], nil
+}
+
+func (c *testCorporation) copy(corporationId uint64) gamecomm.Corporation {
+	corp := gamecomm.Corporation{ID: corporationId, Credits: c.credits, Reputation: c.reputation}
+
+	for _, resources := range append([]map[string]int{c.resources}, c.otherBases...) {
+		stored := make(map[string]int, len(resources))
+		for resourceName, amount := range resources {
+			stored[resourceName] = amount
+		}
+
+		corp.Bases = append(corp.Bases, &gamecomm.Base{StoredResources: stored})
+	}
+
+	return corp
+}
+
+// testCorporations answers the corporation commands of the economy and posts them on the ledger like the
+// corporation worker does
+type testCorporations struct {
+	rw           sync.Mutex
+	corporations map[uint64]*testCorporation
+	commands     []gamecomm.CorpCommand
+	ledger       *ledger.Ledger
+	// errors makes every command of the action fail
+	errors map[gamecomm.CommandType]error
+}
+
+func (tc *testCorporations) get(corporationId uint64) testCorporation {
+	tc.rw.Lock()
+	defer tc.rw.Unlock()
+
+	c, ok := tc.corporations[corporationId]
+	if !ok {
+		return testCorporation{}
+	}
+
+	return *c
+}
+
+func (tc *testCorporations) actions(action gamecomm.CommandType) []gamecomm.CorpCommand {
+	tc.rw.Lock()
+	defer tc.rw.Unlock()
+
+	commands := []gamecomm.CorpCommand{}
+	for _, command := range tc.commands {
+		if command.Action == action {
+			commands = append(commands, command)
+		}
+	}
+
+	return commands
+}
+
+func (tc *testCorporations) handle(command gamecomm.CorpCommand) (any, error) {
+	tc.rw.Lock()
+	defer tc.rw.Unlock()
+
+	tc.commands = append(tc.commands, command)
+
+	if err, ok := tc.errors[command.Action]; ok {
+		return nil, err
+	}
+
+	c, ok := tc.corporations[command.CorporationId]
+	if !ok {
+		return nil, fmt.Errorf("error: corporation not found")
+	}
+
+	corporationAccount := gamecomm.NewCorporationAccount(command.CorporationId)
+	baseAccount := gamecomm.NewBaseAccount(command.CorporationId, command.BaseIndex)
+
+	switch command.Action {
+	case gamecomm.GetCorporation:
+		return c.copy(command.CorporationId), nil
+	case gamecomm.GetSquad:
+		if command.SquadIndex < 0 || command.SquadIndex >= len(c.squads) {
+			return nil, fmt.Errorf("error: squad not found %v", command.SquadIndex)
+		}
+
+		return gamecomm.Squad{Ships: c.squads[command.SquadIndex]}, nil
+	case gamecomm.SeizeShip:
+		if command.SquadIndex < 0 || command.SquadIndex >= len(c.squads) {
+			return nil, fmt.Errorf("error: squad not found %v", command.SquadIndex)
+		}
+
+		ship := c.squads[command.SquadIndex]
+		c.squads[command.SquadIndex] = gamecomm.Ship{}
+
+		return ship, nil
+	case gamecomm.AddCredits:
+		c.credits += command.AmountDecimal
+		tc.ledger.Transfer(command.Memo, command.Counterparty, corporationAccount, gamecomm.CreditsAsset, command.AmountDecimal)
+
+		return c.credits, nil
+	case gamecomm.RemoveCredits:
+		if c.credits < command.AmountDecimal {
+			return nil, fmt.Errorf("error: not enough credits")
+		}
+
+		c.credits -= command.AmountDecimal
+		tc.ledger.Transfer(command.Memo, corporationAccount, command.Counterparty, gamecomm.CreditsAsset, command.AmountDecimal)
+
+		return c.credits, nil
+	case gamecomm.ChargeCredits:
+		c.credits -= command.AmountDecimal
+		tc.ledger.Transfer(command.Memo, corporationAccount, command.Counterparty, gamecomm.CreditsAsset, command.AmountDecimal)
+
+		return c.credits, nil
+	case gamecomm.AddResourcesToBase:
+		resources, err := c.base(command.BaseIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		resources[command.Resource] += command.Amount
+		tc.ledger.Transfer(command.Memo, command.Counterparty, baseAccount, command.Resource, float64(command.Amount))
+
+		return resources[command.Resource], nil
+	case gamecomm.RemoveResourcesFromBase:
+		resources, err := c.base(command.BaseIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		if resources[command.Resource] < command.Amount {
+			return nil, fmt.Errorf("error: not enough resources on base")
+		}
+
+		resources[command.Resource] -= command.Amount
+		tc.ledger.Transfer(command.Memo, baseAccount, command.Counterparty, command.Resource, float64(command.Amount))
+
+		return resources[command.Resource], nil
+	default:
+		return nil, fmt.Errorf("error: wrong action")
+	}
+}
+
+func listenCorporationWorker(t *testing.T, tc *testCorporations, corpChan chan gamecomm.CorpCommand) {
+	t.Helper()
+
+	go func() {
+		for command := range corpChan {
+			val, err := tc.handle(command)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: val, Err: err}
+			close(command.ResponseChannel)
+		}
+	}()
+}
+
+func createTestResources() map[string]resource.Resource {
+	return map[string]resource.Resource{
+		"iron":  {Name: "iron", BasePrice: 100, Rarity: resource.Common},
+		"water": {Name: "water", BasePrice: 10, Rarity: resource.Abundant},
+	}
+}
+
+// createTestCorporations starts the test corporations worker on a new corporation channel
+func createTestCorporations(t *testing.T, corporations map[uint64]*testCorporation) (gamecomm.GameChannels, *testCorporations, *gameclock.GameClock) {
+	t.Helper()
+
+	gc := gameclock.NewGameClock(0, 1)
+	l := ledger.New(gc)
+
+	gameChannels := gamecomm.GameChannels{CorpChannel: make(chan gamecomm.CorpCommand)}
+	t.Cleanup(func() {
+		close(gameChannels.CorpChannel)
+	})
+
+	for _, c := range corporations {
+		if c.resources == nil {
+			c.resources = make(map[string]int)
+		}
+	}
+
+	tc := &testCorporations{corporations: corporations, ledger: l}
+	listenCorporationWorker(t, tc, gameChannels.CorpChannel)
+
+	return gameChannels, tc, gc
+}
+
+// createTestZone returns a zone market of "Zone-1" whose corporations start with the credits and resources given.
+// The zone isn't running, the tests call it directly.
+func createTestZone(t *testing.T, corporations map[uint64]*testCorporation) (*zoneMarket, *testCorporations) {
+	t.Helper()
+
+	gameChannels, tc, gc := createTestCorporations(t, corporations)
+
+	return newZoneMarket("Zone-1", gamecomm.Coordinates{}, gameChannels, createTestResources(), gc, tc.ledger), tc
+}
+
+// createTestBank returns a bank whose corporations start with the credits and resources given, resources are worth
+// their base price
+func createTestBank(t *testing.T, corporations map[uint64]*testCorporation) (*bank, *testCorporations) {
+	t.Helper()
+
+	gameChannels, tc, gc := createTestCorporations(t, corporations)
+
+	return newBank(gameChannels, createTestResources(), gc, tc.ledger), tc
+}
+
+// createTestEconomy returns an economy whose zone markets, exchange, bank and router are running, the clock driven
+// work and the market seeding don't start
+func createTestEconomy(t *testing.T, corporations map[uint64]*testCorporation, zoneIds ...string) (*Economy, *testCorporations) {
+	t.Helper()
+
+	gameChannels, tc, gc := createTestCorporations(t, corporations)
+	gameChannels.EconomyChannel = make(chan gamecomm.EconomyCommand)
+
+	zoneLocations := make(map[string]gamecomm.Coordinates, len(zoneIds))
+	for _, zoneId := range zoneIds {
+		zoneLocations[zoneId] = gamecomm.Coordinates{}
+	}
+
+	e := NewEconomy(gameChannels, createTestResources(), zoneLocations, gc, tc.ledger)
+	for _, z := range e.zones {
+		go z.run()
+	}
+
+	go e.exchange.run()
+	go e.bank.run()
+	go e.listen()
+
+	t.Cleanup(func() {
+		close(gameChannels.EconomyChannel)
+		for _, z := range e.zones {
+			close(z.tasks)
+		}
+		close(e.exchange.tasks)
+		close(e.bank.tasks)
+	})
+
+	return e, tc
+}
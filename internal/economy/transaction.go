@@ -2,52 +2,51 @@ package economy
 
 import "github.com/luisya22/galactic-exchange/internal/gameclock"
 
+// transaction is a sale of a market listing, corporationId is the seller and planetId the buyer
 type transaction struct {
 	planetId      string
 	corporationId uint64
+	listingId     string
 	resource      string
+	amount        int
 	credits       float64
 	time          gameclock.GameTime
 }
 
-// Save Transactions per zone and globally
-func (e *Economy) addTransaction(zoneId string, planetId string, corporationId uint64, resource string, credits float64, t gameclock.GameTime) error {
-	e.rw.Lock()
-	defer e.rw.Unlock()
-
+// Save Transactions of the zone, every sale counts for the demand of the day
+func (z *zoneMarket) addTransaction(planetId string, corporationId uint64, listingId string, resource string, amount int, credits float64, t gameclock.GameTime) {
 	tran := transaction{
 		planetId:      planetId,
 		corporationId: corporationId,
+		listingId:     listingId,
 		resource:      resource,
+		amount:        amount,
 		credits:       credits,
 		time:          t,
 	}
 
-	index := len(e.transactions)
+	z.analytics.updateSalesAmount(resource, t)
+	z.analytics.updateSalesVolume(resource, amount, t)
+
+	index := len(z.transactions)
 
 	// Transaction
-	e.transactions = append(e.transactions, tran)
-	if len(e.transactions) > e.limit {
-		e.transactions = e.transactions[len(e.transactions)-e.limit:]
+	z.transactions = append(z.transactions, tran)
+	if len(z.transactions) > z.limit {
+		z.transactions = z.transactions[len(z.transactions)-z.limit:]
 	}
 
-	// Zone Transaction
-	_, ok := e.zoneTransactions[zoneId]
-	if !ok {
-		e.zoneTransactions[zoneId] = []int{}
+	// Planet Transaction, auctions are sold to corporations
+	if planetId == "" {
+		return
 	}
 
-	e.zoneTransactions[zoneId] = append(e.zoneTransactions[zoneId], index)
-
-	// Planet Transaction
-	_, ok = e.planetTransactions[planetId]
+	_, ok := z.planetTransactions[planetId]
 	if !ok {
-		e.planetTransactions[planetId] = []int{}
+		z.planetTransactions[planetId] = []int{}
 	}
 
-	e.planetTransactions[planetId] = append(e.planetTransactions[planetId], index)
+	z.planetTransactions[planetId] = append(z.planetTransactions[planetId], index)
 
 	// TODO: Save Corporation-Planet Trade Relations level
-
-	return nil
 }
@@ -0,0 +1,218 @@
+package economy
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+// zoneTaskBuffer is how many tasks can wait for a zone market before the sender blocks
+const zoneTaskBuffer = 100
+
+// zoneMarket owns the listings, transactions, analytics and prices of a zone. Only the goroutine running the market
+// touches them, everything else sends it tasks, so the tasks of a zone run in the order they were sent.
+type zoneMarket struct {
+	zoneId             string
+	location           gamecomm.Coordinates
+	book               *orderBook
+	listingCounter     int
+	transactions       []transaction
+	planetTransactions map[string][]int
+	limit              int
+	auctions           map[string]*auction
+	auctionCounter     int
+	futures            map[string]*futuresPosition
+	futuresCounter     int
+	analytics          *analytics
+	prices             resourcePrices
+	resources          map[string]resource.Resource
+	gameChannels       gamecomm.GameChannels
+	gameClock          *gameclock.GameClock
+	ledger             *ledger.Ledger
+	tasks              chan func(z *zoneMarket)
+}
+
+// zoneSnapshot is a copy of the zone state used by the work that needs every zone
+type zoneSnapshot struct {
+	zoneId   string
+	location gamecomm.Coordinates
+	prices   resourcePrices
+	flows    resourceVolume
+}
+
+func newZoneMarket(zoneId string, location gamecomm.Coordinates, gameChannels gamecomm.GameChannels, resources map[string]resource.Resource, gc *gameclock.GameClock, l *ledger.Ledger) *zoneMarket {
+	// TODO: Optimize
+	prices := make(resourcePrices)
+	for _, r := range resources {
+		prices[r.Name] = r.BasePrice
+	}
+
+	return &zoneMarket{
+		zoneId:             zoneId,
+		location:           location,
+		book:               newOrderBook(),
+		transactions:       []transaction{},
+		planetTransactions: make(map[string][]int),
+		limit:              transactionLimit,
+		auctions:           make(map[string]*auction),
+		futures:            make(map[string]*futuresPosition),
+		analytics:          newAnalytics(),
+		prices:             prices,
+		resources:          resources,
+		gameChannels:       gameChannels,
+		gameClock:          gc,
+		ledger:             l,
+		tasks:              make(chan func(z *zoneMarket), zoneTaskBuffer),
+	}
+}
+
+func (z *zoneMarket) run() {
+	for task := range z.tasks {
+		task(z)
+	}
+}
+
+func (z *zoneMarket) updatePrices(day gameclock.GameTime) {
+	z.prices = z.analytics.updateItemPrices(z.resources, z.prices, day)
+}
+
+func (z *zoneMarket) snapshot(day gameclock.GameTime) zoneSnapshot {
+	prices := make(resourcePrices, len(z.prices))
+	for name, price := range z.prices {
+		prices[name] = price
+	}
+
+	return zoneSnapshot{
+		zoneId:   z.zoneId,
+		location: z.location,
+		prices:   prices,
+		flows:    z.tradeFlows(day),
+	}
+}
+
+// TODO: Test
+func (z *zoneMarket) handle(command gamecomm.EconomyCommand) {
+	defer close(command.ResponseChannel)
+
+	listingTime := z.gameClock.GetCurrentTime()
+
+	switch command.Action {
+	case gamecomm.AddMarketListing:
+		so := MarketListing{
+			ResourceName:  command.Resource,
+			Amount:        command.Amount,
+			Price:         command.Price,
+			CorporationId: command.CorporationId,
+			ListTime:      listingTime,
+		}
+
+		id, err := z.addMarketListing(so)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: id}
+
+	case gamecomm.GetMarketListings:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: z.book.all()}
+	case gamecomm.EditMarketListingPrice:
+		err := z.editPrice(command.MarketListingId, command.CorporationId, command.Price)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: "OK"}
+	case gamecomm.EditMarketListingAmount:
+		err := z.editAmount(command.MarketListingId, command.CorporationId, command.Amount)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: "OK"}
+	case gamecomm.GetMarketListingHistory:
+		history, err := z.book.editHistory(command.MarketListingId)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: history}
+	case gamecomm.GetMarketListingsByResource:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: z.book.resourceListings(command.Resource)}
+	case gamecomm.GetMarketPrice:
+		marketPrice, err := z.getResourceMarketPrice(command.Resource)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: marketPrice}
+	case gamecomm.GetMarketPrices:
+		prices := make(map[string]float64, len(z.prices))
+		for name, price := range z.prices {
+			prices[name] = price
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: prices}
+	case gamecomm.BuyMarketListings:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: z.fillOrders(command.Orders, command.Budgets)}
+	case gamecomm.CreateAuction:
+		id, err := z.createAuction(command.CorporationId, command.Resource, command.Amount, command.Price, command.Duration, command.NotificationChan)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: id}
+	case gamecomm.PlaceBid:
+		err := z.placeBid(command.AuctionId, command.CorporationId, command.Price, command.NotificationChan)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: "OK"}
+	case gamecomm.GetAuctions:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: z.getAuctions()}
+	case gamecomm.OpenFuturesPosition:
+		id, err := z.openFuturesPosition(command.CorporationId, command.Resource, command.Amount, command.Days, command.Side, command.Settlement, command.NotificationChan)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: id}
+	case gamecomm.CloseFuturesPosition:
+		margin, err := z.closeFuturesPosition(command.ContractId, command.CorporationId)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: margin}
+	case gamecomm.GetFuturesPositions:
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: z.getFuturesPositions(command.CorporationId)}
+	case gamecomm.GetPriceForecast:
+		demand, err := z.zoneDemand(command.Resource)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		forecast, err := z.forecastPrice(command.Resource, command.Days, demand)
+		if err != nil {
+			command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			return
+		}
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: forecast}
+	default:
+		command.ResponseChannel <- gamecomm.ChanResponse{Err: fmt.Errorf("error: wrong action")}
+	}
+}
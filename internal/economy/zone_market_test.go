@@ -0,0 +1,154 @@
+package economy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// sendEconomyCommand sends the command through the economy router, the response channel is buffered so the caller
+// can queue commands without waiting for them
+func sendEconomyCommand(e *Economy, command gamecomm.EconomyCommand) chan gamecomm.ChanResponse {
+	command.ResponseChannel = make(chan gamecomm.ChanResponse, 1)
+	e.gameChannels.EconomyChannel <- command
+
+	return command.ResponseChannel
+}
+
+func receive(t *testing.T, resChan chan gamecomm.ChanResponse) gamecomm.ChanResponse {
+	t.Helper()
+
+	select {
+	case res := <-resChan:
+		return res
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the response")
+		return gamecomm.ChanResponse{}
+	}
+}
+
+func TestEconomyRoutesByZone(t *testing.T) {
+	e, _ := createTestEconomy(t, map[uint64]*testCorporation{
+		1: {resources: map[string]int{"iron": 100}},
+	}, "Zone-1", "Zone-2")
+
+	tests := []struct {
+		name             string
+		zoneId           string
+		expectedListings int
+		expectedError    string
+	}{
+		{name: "Zone Of The Listing", zoneId: "Zone-1", expectedListings: 1},
+		{name: "Other Zone", zoneId: "Zone-2", expectedListings: 0},
+		{name: "Zone Not Found", zoneId: "Zone-9", expectedError: "error: zone not found 'Zone-9'"},
+	}
+
+	res := receive(t, sendEconomyCommand(e, gamecomm.EconomyCommand{
+		Action:        gamecomm.AddMarketListing,
+		ZoneId:        "Zone-1",
+		CorporationId: 1,
+		Resource:      "iron",
+		Amount:        100,
+		Price:         100,
+	}))
+	assert.NilError(t, res.Err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := receive(t, sendEconomyCommand(e, gamecomm.EconomyCommand{Action: gamecomm.GetMarketListings, ZoneId: tt.zoneId}))
+			if tt.expectedError != "" {
+				assert.Error(t, res.Err)
+				assert.StringContains(t, res.Err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+			assert.Equal(t, len(res.Val.([]MarketListing)), tt.expectedListings)
+		})
+	}
+}
+
+func TestZoneMarketsRunConcurrently(t *testing.T) {
+	e, _ := createTestEconomy(t, map[uint64]*testCorporation{}, "Zone-1", "Zone-2")
+
+	// Zone-1 is busy until the test releases it
+	release := make(chan struct{})
+	e.zones["Zone-1"].tasks <- func(z *zoneMarket) {
+		<-release
+	}
+
+	busy := sendEconomyCommand(e, gamecomm.EconomyCommand{Action: gamecomm.GetMarketListings, ZoneId: "Zone-1"})
+
+	// The other zone answers while Zone-1 is blocked
+	res := receive(t, sendEconomyCommand(e, gamecomm.EconomyCommand{Action: gamecomm.GetMarketListings, ZoneId: "Zone-2"}))
+	assert.NilError(t, res.Err)
+
+	select {
+	case <-busy:
+		t.Fatal("Zone-1 answered while it was busy")
+	default:
+	}
+
+	close(release)
+
+	res = receive(t, busy)
+	assert.NilError(t, res.Err)
+}
+
+func TestZoneMarketRunsTasksInOrder(t *testing.T) {
+	e, tc := createTestEconomy(t, map[uint64]*testCorporation{
+		1: {resources: map[string]int{"iron": 100}},
+	}, "Zone-1")
+
+	// The commands are queued without waiting, the zone runs them in the order they were sent
+	added := sendEconomyCommand(e, gamecomm.EconomyCommand{
+		Action:        gamecomm.AddMarketListing,
+		ZoneId:        "Zone-1",
+		CorporationId: 1,
+		Resource:      "iron",
+		Amount:        100,
+		Price:         100,
+	})
+	edited := sendEconomyCommand(e, gamecomm.EconomyCommand{
+		Action:          gamecomm.EditMarketListingPrice,
+		ZoneId:          "Zone-1",
+		CorporationId:   1,
+		MarketListingId: "Zone-1-1",
+		Price:           120,
+	})
+	listed := sendEconomyCommand(e, gamecomm.EconomyCommand{Action: gamecomm.GetMarketListings, ZoneId: "Zone-1"})
+
+	res := receive(t, added)
+	assert.NilError(t, res.Err)
+	assert.Equal(t, res.Val.(string), "Zone-1-1")
+
+	res = receive(t, edited)
+	assert.NilError(t, res.Err)
+
+	res = receive(t, listed)
+	assert.NilError(t, res.Err)
+
+	listings := res.Val.([]MarketListing)
+	assert.Equal(t, len(listings), 1)
+	assert.Equal(t, listings[0].Price, 120.0)
+	assert.Equal(t, tc.get(1).resources["iron"], 0)
+}
+
+func TestCollectSnapshots(t *testing.T) {
+	e, _ := createTestEconomy(t, map[uint64]*testCorporation{}, "Zone-1", "Zone-2", "Zone-3")
+
+	// Every zone runs the task on its own goroutine before the snapshot is taken
+	snapshots := e.collectSnapshots(func(z *zoneMarket) {
+		if z.zoneId == "Zone-2" {
+			z.prices["iron"] = 200
+		}
+	}, e.gameClock.GetCurrentTime())
+
+	assert.Equal(t, len(snapshots), 3)
+	assert.Equal(t, snapshots["Zone-1"].zoneId, "Zone-1")
+	assert.Equal(t, snapshots["Zone-1"].prices["iron"], 100.0)
+	assert.Equal(t, snapshots["Zone-2"].prices["iron"], 200.0)
+	assert.Equal(t, snapshots["Zone-3"].prices["water"], 10.0)
+}
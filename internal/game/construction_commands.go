@@ -0,0 +1,49 @@
+package game
+
+import (
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func (g *Game) FoundBase(planetId string, corporationId uint64, baseName string, squadIds []int, notificationChan chan string) error {
+
+	mc := gamecomm.MissionCommand{
+		CorporationId:    corporationId,
+		Squads:           squadIds,
+		Type:             gamecomm.BaseConstructionMission,
+		NotificationChan: notificationChan,
+		PlanetId:         planetId,
+		BaseName:         baseName,
+	}
+
+	g.gameChannels.MissionChannel <- mc
+	return nil
+}
+
+func (g *Game) BuildFacility(corporationId uint64, baseIndex int, facility string, notificationChan chan string) error {
+
+	mc := gamecomm.MissionCommand{
+		CorporationId:    corporationId,
+		Type:             gamecomm.FacilityConstructionMission,
+		NotificationChan: notificationChan,
+		BaseIndex:        baseIndex,
+		Facility:         facility,
+	}
+
+	g.gameChannels.MissionChannel <- mc
+	return nil
+}
+
+func (g *Game) Produce(corporationId uint64, baseIndex int, recipe string, batches int, notificationChan chan string) error {
+
+	mc := gamecomm.MissionCommand{
+		CorporationId:    corporationId,
+		Type:             gamecomm.ProductionMission,
+		NotificationChan: notificationChan,
+		BaseIndex:        baseIndex,
+		Recipe:           recipe,
+		Amount:           batches,
+	}
+
+	g.gameChannels.MissionChannel <- mc
+	return nil
+}
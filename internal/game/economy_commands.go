@@ -1,6 +1,7 @@
 package game
 
 import (
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
 )
 
@@ -21,3 +22,457 @@ func (g *Game) SellResource(planetId string, corporationId uint64, squadId int,
 	g.gameChannels.MissionChannel <- mc
 	return nil
 }
+
+// GetPriceForecast returns the projected price of the resource in the zone for the next days
+func (g *Game) GetPriceForecast(zoneId string, resource string, days int) (gamecomm.PriceForecast, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetPriceForecast,
+		ZoneId:          zoneId,
+		Resource:        resource,
+		Days:            days,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.PriceForecast{}, res.Err
+	}
+
+	return res.Val.(gamecomm.PriceForecast), nil
+}
+
+// GetPriceSpreads returns the biggest price differences of a resource between zones
+func (g *Game) GetPriceSpreads(limit int) ([]gamecomm.PriceSpread, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetPriceSpreads,
+		Amount:          limit,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.([]gamecomm.PriceSpread), nil
+}
+
+// EditListingPrice changes the price of a market listing of the corporation
+func (g *Game) EditListingPrice(zoneId string, listingId string, corporationId uint64, price float64) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.EditMarketListingPrice,
+		ZoneId:          zoneId,
+		MarketListingId: listingId,
+		CorporationId:   corporationId,
+		Price:           price,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+
+	return res.Err
+}
+
+// EditListingAmount changes the amount of a market listing of the corporation
+func (g *Game) EditListingAmount(zoneId string, listingId string, corporationId uint64, amount int) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.EditMarketListingAmount,
+		ZoneId:          zoneId,
+		MarketListingId: listingId,
+		CorporationId:   corporationId,
+		Amount:          amount,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+
+	return res.Err
+}
+
+// GetListingHistory returns the edits made to a market listing
+func (g *Game) GetListingHistory(zoneId string, listingId string) ([]gamecomm.ListingEdit, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetMarketListingHistory,
+		ZoneId:          zoneId,
+		MarketListingId: listingId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.([]gamecomm.ListingEdit), nil
+}
+
+// CreateAuction puts up a lot from the corporation base, the auction ends after the duration in game hours
+func (g *Game) CreateAuction(zoneId string, corporationId uint64, resource string, amount int, reservePrice float64, duration gameclock.GameTimeDuration, notificationChan chan string) (string, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:           gamecomm.CreateAuction,
+		ZoneId:           zoneId,
+		CorporationId:    corporationId,
+		Resource:         resource,
+		Amount:           amount,
+		Price:            reservePrice,
+		Duration:         duration,
+		NotificationChan: notificationChan,
+		ResponseChannel:  resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return "", res.Err
+	}
+
+	return res.Val.(string), nil
+}
+
+// PlaceBid bids the credits on the auction, the credits are held until the corporation is outbid
+func (g *Game) PlaceBid(zoneId string, auctionId string, corporationId uint64, bid float64, notificationChan chan string) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:           gamecomm.PlaceBid,
+		ZoneId:           zoneId,
+		AuctionId:        auctionId,
+		CorporationId:    corporationId,
+		Price:            bid,
+		NotificationChan: notificationChan,
+		ResponseChannel:  resChan,
+	}
+
+	res := <-resChan
+
+	return res.Err
+}
+
+// GetAuctions returns the open auctions of the zone
+func (g *Game) GetAuctions(zoneId string) ([]gamecomm.Auction, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetAuctions,
+		ZoneId:          zoneId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.([]gamecomm.Auction), nil
+}
+
+// OpenFuturesPosition buys or sells standard contracts of the resource for delivery in the zone after the days
+func (g *Game) OpenFuturesPosition(zoneId string, corporationId uint64, resource string, side gamecomm.FuturesSide, contracts int, days int, settlement gamecomm.SettlementType, notificationChan chan string) (string, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:           gamecomm.OpenFuturesPosition,
+		ZoneId:           zoneId,
+		CorporationId:    corporationId,
+		Resource:         resource,
+		Side:             side,
+		Amount:           contracts,
+		Days:             days,
+		Settlement:       settlement,
+		NotificationChan: notificationChan,
+		ResponseChannel:  resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return "", res.Err
+	}
+
+	return res.Val.(string), nil
+}
+
+// CloseFuturesPosition closes the position before delivery and returns the margin given back
+func (g *Game) CloseFuturesPosition(zoneId string, positionId string, corporationId uint64) (float64, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.CloseFuturesPosition,
+		ZoneId:          zoneId,
+		ContractId:      positionId,
+		CorporationId:   corporationId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return 0, res.Err
+	}
+
+	return res.Val.(float64), nil
+}
+
+// GetFuturesPositions returns the open futures positions of the corporation in the zone
+func (g *Game) GetFuturesPositions(zoneId string, corporationId uint64) ([]gamecomm.FuturesPosition, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetFuturesPositions,
+		ZoneId:          zoneId,
+		CorporationId:   corporationId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.([]gamecomm.FuturesPosition), nil
+}
+
+// IssueShares creates new shares of the corporation and offers them on the exchange at the price
+func (g *Game) IssueShares(corporationId uint64, amount int, price float64, notificationChan chan string) (string, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:           gamecomm.IssueShares,
+		CorporationId:    corporationId,
+		Amount:           amount,
+		Price:            price,
+		NotificationChan: notificationChan,
+		ResponseChannel:  resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return "", res.Err
+	}
+
+	return res.Val.(string), nil
+}
+
+// PlaceShareOrder buys or sells shares of the issuer at the price or better, what isn't filled stays on the exchange
+func (g *Game) PlaceShareOrder(corporationId uint64, issuerId uint64, side gamecomm.OrderSide, amount int, price float64, notificationChan chan string) (string, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:           gamecomm.PlaceShareOrder,
+		CorporationId:    corporationId,
+		IssuerId:         issuerId,
+		OrderSide:        side,
+		Amount:           amount,
+		Price:            price,
+		NotificationChan: notificationChan,
+		ResponseChannel:  resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return "", res.Err
+	}
+
+	return res.Val.(string), nil
+}
+
+// CancelShareOrder takes the order of the corporation off the exchange
+func (g *Game) CancelShareOrder(corporationId uint64, orderId string) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.CancelShareOrder,
+		CorporationId:   corporationId,
+		OrderId:         orderId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+
+	return res.Err
+}
+
+// PayDividend pays the credits per share to the shareholders of the corporation and returns the credits paid
+func (g *Game) PayDividend(corporationId uint64, perShare float64) (float64, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.PayDividend,
+		CorporationId:   corporationId,
+		Price:           perShare,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return 0, res.Err
+	}
+
+	return res.Val.(float64), nil
+}
+
+// GetStocks returns the shares of every corporation listed on the exchange
+func (g *Game) GetStocks() ([]gamecomm.Stock, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetStocks,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.([]gamecomm.Stock), nil
+}
+
+// GetPortfolio returns the shares and open orders of the corporation
+func (g *Game) GetPortfolio(corporationId uint64) (gamecomm.Portfolio, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetPortfolio,
+		CorporationId:   corporationId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.Portfolio{}, res.Err
+	}
+
+	return res.Val.(gamecomm.Portfolio), nil
+}
+
+// GetLoanOffer returns the rate and monthly payment the bank asks the corporation for the loan
+func (g *Game) GetLoanOffer(corporationId uint64, principal float64, months int, collateral gamecomm.Collateral) (gamecomm.LoanOffer, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetLoanOffer,
+		CorporationId:   corporationId,
+		Credits:         principal,
+		Months:          months,
+		Collateral:      collateral,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.LoanOffer{}, res.Err
+	}
+
+	return res.Val.(gamecomm.LoanOffer), nil
+}
+
+// TakeLoan borrows the credits from the bank, the collateral is seized if the corporation defaults
+func (g *Game) TakeLoan(corporationId uint64, principal float64, months int, collateral gamecomm.Collateral, notificationChan chan string) (gamecomm.Loan, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:           gamecomm.TakeLoan,
+		CorporationId:    corporationId,
+		Credits:          principal,
+		Months:           months,
+		Collateral:       collateral,
+		NotificationChan: notificationChan,
+		ResponseChannel:  resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.Loan{}, res.Err
+	}
+
+	return res.Val.(gamecomm.Loan), nil
+}
+
+// RepayLoan pays part of the loan before it's due and returns the balance left
+func (g *Game) RepayLoan(corporationId uint64, loanId string, credits float64) (float64, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.RepayLoan,
+		CorporationId:   corporationId,
+		LoanId:          loanId,
+		Credits:         credits,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return 0, res.Err
+	}
+
+	return res.Val.(float64), nil
+}
+
+// GetLoans returns the loans of the corporation
+func (g *Game) GetLoans(corporationId uint64) ([]gamecomm.Loan, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetLoans,
+		CorporationId:   corporationId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.([]gamecomm.Loan), nil
+}
+
+// OpenCreditLine opens a credit line for the corporation or reviews its limit
+func (g *Game) OpenCreditLine(corporationId uint64, notificationChan chan string) (gamecomm.CreditLine, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:           gamecomm.OpenCreditLine,
+		CorporationId:    corporationId,
+		NotificationChan: notificationChan,
+		ResponseChannel:  resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.CreditLine{}, res.Err
+	}
+
+	return res.Val.(gamecomm.CreditLine), nil
+}
+
+// DrawCredit takes credits from the credit line and returns its balance
+func (g *Game) DrawCredit(corporationId uint64, credits float64) (float64, error) {
+	return g.creditLineCommand(gamecomm.DrawCredit, corporationId, credits)
+}
+
+// RepayCredit pays part of the credit line and returns its balance
+func (g *Game) RepayCredit(corporationId uint64, credits float64) (float64, error) {
+	return g.creditLineCommand(gamecomm.RepayCredit, corporationId, credits)
+}
+
+func (g *Game) creditLineCommand(action gamecomm.EconomyCommandType, corporationId uint64, credits float64) (float64, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          action,
+		CorporationId:   corporationId,
+		Credits:         credits,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return 0, res.Err
+	}
+
+	return res.Val.(float64), nil
+}
+
+// GetCreditLine returns the credit line of the corporation
+func (g *Game) GetCreditLine(corporationId uint64) (gamecomm.CreditLine, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	g.gameChannels.EconomyChannel <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetCreditLine,
+		CorporationId:   corporationId,
+		ResponseChannel: resChan,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.CreditLine{}, res.Err
+	}
+
+	return res.Val.(gamecomm.CreditLine), nil
+}
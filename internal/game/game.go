@@ -12,6 +12,7 @@ import (
 	"github.com/luisya22/galactic-exchange/internal/economy"
 	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
 	"github.com/luisya22/galactic-exchange/internal/mission"
 	"github.com/luisya22/galactic-exchange/internal/resource"
 	"github.com/luisya22/galactic-exchange/internal/ship"
@@ -20,14 +21,16 @@ import (
 
 // TODO: Gracefully shutdown game
 type Game struct {
-	World            *world.World
-	Corporations     *corporation.CorpGroup
-	PlayerState      *PlayerState
-	MissionScheduler *mission.MissionScheduler
-	gameChannels     *gamecomm.GameChannels
-	gameClock        *gameclock.GameClock
-	Resources        map[string]resource.Resource
-	Economy          *economy.Economy
+	World             *world.World
+	Corporations      *corporation.CorpGroup
+	PlayerState       *PlayerState
+	MissionScheduler  *mission.MissionScheduler
+	gameChannels      *gamecomm.GameChannels
+	gameClock         *gameclock.GameClock
+	Resources         map[string]resource.Resource
+	Economy           *economy.Economy
+	Ledger            *ledger.Ledger
+	corporationEvents chan gamecomm.CorporationEvent
 }
 
 /*
@@ -54,6 +57,27 @@ func (ps *PlayerState) listenNotifications() {
 	}
 }
 
+// listenCorporationEvents tells the player when a corporation gets in trouble
+func (g *Game) listenCorporationEvents() {
+	for event := range g.corporationEvents {
+		var message string
+		switch event.Type {
+		case gamecomm.CorporationInsolvent:
+			message = fmt.Sprintf("%v is insolvent with %.2f credits", event.Name, event.Credits)
+		case gamecomm.CorporationRecovered:
+			message = fmt.Sprintf("%v is solvent again", event.Name)
+		case gamecomm.CorporationLiquidated:
+			message = fmt.Sprintf("%v assets were liquidated, %.2f credits left", event.Name, event.Credits)
+		case gamecomm.CorporationRestructured:
+			message = fmt.Sprintf("%v went through restructuring, its debts were written off", event.Name)
+		case gamecomm.CorporationBankrupt:
+			message = fmt.Sprintf("%v went bankrupt", event.Name)
+		}
+
+		g.PlayerState.NotificationChan <- "Corporation Notification: " + message
+	}
+}
+
 func New() *Game {
 	gameChannels := &gamecomm.GameChannels{
 		WorldChannel:   make(chan gamecomm.WorldCommand, 100),
@@ -66,25 +90,37 @@ func New() *Game {
 
 	gc := gameclock.NewGameClock(0, 1)
 
-	w := world.New(gameChannels, resources, gc)
+	l := ledger.New(gc)
+
+	w := world.New(gameChannels, resources, gc, l)
 
 	playerState := newPlayer()
-	corporations := corporation.NewCorpGroup(gameChannels)
-	gameEconomy := economy.NewEconomy(*gameChannels, resources, w.GetZoneIds(), gc)
+	corporations := corporation.NewCorpGroup(gameChannels, gc, l)
+	gameEconomy := economy.NewEconomy(*gameChannels, resources, w.GetZoneLocations(), gc, l)
 
 	corporations.Corporations[1] = playerState.Corporation
+	err := corporations.OpenAccounts(1)
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	corporationEvents := make(chan gamecomm.CorporationEvent, 100)
+	corporations.SubscribeEvents(corporationEvents)
+	corporations.SubscribeEvents(gameEconomy.CorporationEvents())
 
 	missionScheduler := mission.NewMissionScheduler(gameChannels, gc)
 
 	return &Game{
-		World:            w,
-		PlayerState:      playerState,
-		Corporations:     corporations,
-		MissionScheduler: missionScheduler,
-		gameChannels:     gameChannels,
-		gameClock:        gc,
-		Resources:        resource.LoadWorldResources(),
-		Economy:          gameEconomy,
+		World:             w,
+		PlayerState:       playerState,
+		Corporations:      corporations,
+		MissionScheduler:  missionScheduler,
+		gameChannels:      gameChannels,
+		gameClock:         gc,
+		Resources:         resource.LoadWorldResources(),
+		Economy:           gameEconomy,
+		Ledger:            l,
+		corporationEvents: corporationEvents,
 	}
 }
 
@@ -96,6 +132,7 @@ func Start() error {
 	go game.MissionScheduler.Run()
 	go game.Corporations.Run()
 	go game.PlayerState.listenNotifications()
+	go game.listenCorporationEvents()
 	go game.gameClock.StartTime()
 	go game.Economy.Run()
 
@@ -125,8 +162,8 @@ func Start() error {
 				fmt.Println(err.Error())
 			}
 		case "harvest":
-			if len(command) != 3 {
-				fmt.Printf("Wrong command: the harvest command is 'harvets <planet> <squad>'")
+			if len(command) < 3 {
+				fmt.Printf("Wrong command: the harvest command is 'harvest <planet> <squad> [squad...]'")
 				continue
 			}
 
@@ -134,6 +171,263 @@ func Start() error {
 			if err != nil {
 				fmt.Println(err.Error())
 			}
+		case "found-base":
+			if len(command) < 4 {
+				fmt.Printf("Wrong command: the found-base command is 'found-base <planet> <name> <squad> [squad...]'")
+				continue
+			}
+
+			err := game.foundBase(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "build":
+			if len(command) != 3 {
+				fmt.Printf("Wrong command: the build command is 'build <base> <facility>'")
+				continue
+			}
+
+			err := game.buildFacility(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "produce":
+			if len(command) != 4 {
+				fmt.Printf("Wrong command: the produce command is 'produce <base> <recipe> <batches>'")
+				continue
+			}
+
+			err := game.produce(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "spreads":
+			if len(command) > 2 {
+				fmt.Printf("Wrong command: the spreads command is 'spreads [limit]'")
+				continue
+			}
+
+			err := game.priceSpreads(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "forecast":
+			if len(command) != 4 {
+				fmt.Printf("Wrong command: the forecast command is 'forecast <zone> <resource> <days>'")
+				continue
+			}
+
+			err := game.priceForecast(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "auction":
+			if len(command) != 6 {
+				fmt.Printf("Wrong command: the auction command is 'auction <zone> <resource> <amount> <reserve> <hours>'")
+				continue
+			}
+
+			err := game.createAuction(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "bid":
+			if len(command) != 4 {
+				fmt.Printf("Wrong command: the bid command is 'bid <zone> <auction> <credits>'")
+				continue
+			}
+
+			err := game.placeBid(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "auctions":
+			if len(command) != 2 {
+				fmt.Printf("Wrong command: the auctions command is 'auctions <zone>'")
+				continue
+			}
+
+			err := game.listAuctions(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "future":
+			if len(command) < 6 || len(command) > 7 {
+				fmt.Printf("Wrong command: the future command is 'future <zone> <resource> <long|short> <contracts> <days> [cash|physical]'")
+				continue
+			}
+
+			err := game.openFuturesPosition(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "close-future":
+			if len(command) != 3 {
+				fmt.Printf("Wrong command: the close-future command is 'close-future <zone> <position>'")
+				continue
+			}
+
+			err := game.closeFuturesPosition(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "futures":
+			if len(command) != 2 {
+				fmt.Printf("Wrong command: the futures command is 'futures <zone>'")
+				continue
+			}
+
+			err := game.listFuturesPositions(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "issue":
+			if len(command) != 3 {
+				fmt.Printf("Wrong command: the issue command is 'issue <shares> <price>'")
+				continue
+			}
+
+			err := game.issueShares(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "buy-shares":
+			if len(command) != 4 {
+				fmt.Printf("Wrong command: the buy-shares command is 'buy-shares <corporation> <shares> <price>'")
+				continue
+			}
+
+			err := game.buyShares(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "sell-shares":
+			if len(command) != 4 {
+				fmt.Printf("Wrong command: the sell-shares command is 'sell-shares <corporation> <shares> <price>'")
+				continue
+			}
+
+			err := game.sellShares(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "cancel-order":
+			if len(command) != 2 {
+				fmt.Printf("Wrong command: the cancel-order command is 'cancel-order <order>'")
+				continue
+			}
+
+			err := game.cancelShareOrder(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "dividend":
+			if len(command) != 2 {
+				fmt.Printf("Wrong command: the dividend command is 'dividend <credits per share>'")
+				continue
+			}
+
+			err := game.payDividend(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "stocks":
+			if len(command) != 1 {
+				fmt.Printf("Wrong command: the stocks command is 'stocks'")
+				continue
+			}
+
+			err := game.listStocks(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "portfolio":
+			if len(command) != 1 {
+				fmt.Printf("Wrong command: the portfolio command is 'portfolio'")
+				continue
+			}
+
+			err := game.showPortfolio(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "loan-offer", "loan":
+			if len(command) < 3 {
+				fmt.Printf("Wrong command: the %v command is '%v <credits> <months> [resource:amount|squad:index ...]'", command[0], command[0])
+				continue
+			}
+
+			err := game.loan(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "repay":
+			if len(command) != 3 {
+				fmt.Printf("Wrong command: the repay command is 'repay <loan> <credits>'")
+				continue
+			}
+
+			err := game.repayLoan(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "loans":
+			if len(command) != 1 {
+				fmt.Printf("Wrong command: the loans command is 'loans'")
+				continue
+			}
+
+			err := game.listLoans(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "credit-line":
+			if len(command) != 1 {
+				fmt.Printf("Wrong command: the credit-line command is 'credit-line'")
+				continue
+			}
+
+			err := game.creditLine(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "draw":
+			if len(command) != 2 {
+				fmt.Printf("Wrong command: the draw command is 'draw <credits>'")
+				continue
+			}
+
+			err := game.drawCredit(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "repay-credit":
+			if len(command) != 2 {
+				fmt.Printf("Wrong command: the repay-credit command is 'repay-credit <credits>'")
+				continue
+			}
+
+			err := game.repayCredit(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "ledger":
+			if len(command) < 2 || len(command) > 4 {
+				fmt.Printf("Wrong command: the ledger command is 'ledger <type:id> [from hour] [to hour]'")
+				continue
+			}
+
+			err := game.listLedgerEntries(command)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		case "ledger-check":
+			if len(command) != 1 {
+				fmt.Printf("Wrong command: the ledger-check command is 'ledger-check'")
+				continue
+			}
+
+			game.checkLedger(command)
 		default:
 			fmt.Printf("Wrong command %v\n", command)
 		}
@@ -159,15 +453,557 @@ func (g *Game) sellResource(command []string) error {
 	return g.SellResource(planetId, 1, squadId, amount, itemName, g.PlayerState.NotificationChan)
 }
 
-// harvest <planet> <squad>
+// harvest <planet> <squad> [squad...]
 func (g *Game) harvestPlanet(command []string) error {
 	planetId := command[1]
-	squadId, err := strconv.Atoi(command[2])
+
+	squadIds := []int{}
+	for _, arg := range command[2:] {
+		squadId, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("%v needs to be an integer", arg)
+		}
+
+		squadIds = append(squadIds, squadId)
+	}
+
+	return g.HarvestPlanet(planetId, 1, squadIds, g.PlayerState.NotificationChan)
+}
+
+// found-base <planet> <name> <squad> [squad...]
+func (g *Game) foundBase(command []string) error {
+	planetId := command[1]
+	baseName := command[2]
+
+	squadIds := []int{}
+	for _, arg := range command[3:] {
+		squadId, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("%v needs to be an integer", arg)
+		}
+
+		squadIds = append(squadIds, squadId)
+	}
+
+	return g.FoundBase(planetId, 1, baseName, squadIds, g.PlayerState.NotificationChan)
+}
+
+// build <base> <facility>
+func (g *Game) buildFacility(command []string) error {
+	baseIndex, err := strconv.Atoi(command[1])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[1])
+	}
+
+	return g.BuildFacility(1, baseIndex, command[2], g.PlayerState.NotificationChan)
+}
+
+// produce <base> <recipe> <batches>
+func (g *Game) produce(command []string) error {
+	baseIndex, err := strconv.Atoi(command[1])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[1])
+	}
+
+	batches, err := strconv.Atoi(command[3])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[3])
+	}
+
+	return g.Produce(1, baseIndex, command[2], batches, g.PlayerState.NotificationChan)
+}
+
+// forecast <zone> <resource> <days>
+func (g *Game) priceForecast(command []string) error {
+	days, err := strconv.Atoi(command[3])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[3])
+	}
+
+	forecast, err := g.GetPriceForecast(command[1], command[2], days)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%v in %v: current price %.2f\n", forecast.Resource, forecast.ZoneId, forecast.CurrentPrice)
+	for _, p := range forecast.Projections {
+		fmt.Printf("day %v: %.2f (%.2f - %.2f)\n", p.Day, p.Price, p.Low, p.High)
+	}
+
+	return nil
+}
+
+// spreads [limit]
+func (g *Game) priceSpreads(command []string) error {
+	limit := 0
+	if len(command) == 2 {
+		var err error
+		limit, err = strconv.Atoi(command[1])
+		if err != nil {
+			return fmt.Errorf("%v needs to be an integer", command[1])
+		}
+	}
+
+	spreads, err := g.GetPriceSpreads(limit)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range spreads {
+		fmt.Printf("%v: buy in %v at %.2f, sell in %v at %.2f (spread %.2f, distance %.0f)\n", s.Resource, s.LowZoneId, s.LowPrice, s.HighZoneId, s.HighPrice, s.Spread, s.Distance)
+	}
+
+	return nil
+}
+
+// auction <zone> <resource> <amount> <reserve> <hours>
+func (g *Game) createAuction(command []string) error {
+	amount, err := strconv.Atoi(command[3])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[3])
+	}
+
+	reservePrice, err := strconv.ParseFloat(command[4], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[4])
+	}
+
+	hours, err := strconv.Atoi(command[5])
+	if err != nil || hours <= 0 {
+		return fmt.Errorf("%v needs to be a positive integer", command[5])
+	}
+
+	id, err := g.CreateAuction(command[1], 1, command[2], amount, reservePrice, gameclock.GameTimeDuration(hours), g.PlayerState.NotificationChan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Auction %v created\n", id)
+
+	return nil
+}
+
+// bid <zone> <auction> <credits>
+func (g *Game) placeBid(command []string) error {
+	bid, err := strconv.ParseFloat(command[3], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[3])
+	}
+
+	return g.PlaceBid(command[1], command[2], 1, bid, g.PlayerState.NotificationChan)
+}
+
+// auctions <zone>
+func (g *Game) listAuctions(command []string) error {
+	auctions, err := g.GetAuctions(command[1])
+	if err != nil {
+		return err
+	}
+
+	for _, a := range auctions {
+		fmt.Printf("%v: %v %v, reserve %.2f, highest bid %.2f, ends at %v\n", a.Id, a.Amount, a.Resource, a.ReservePrice, a.HighestBid, a.EndTime)
+	}
+
+	return nil
+}
+
+// future <zone> <resource> <long|short> <contracts> <days> [cash|physical]
+func (g *Game) openFuturesPosition(command []string) error {
+	var side gamecomm.FuturesSide
+	switch command[3] {
+	case "long":
+		side = gamecomm.Long
+	case "short":
+		side = gamecomm.Short
+	default:
+		return fmt.Errorf("%v needs to be long or short", command[3])
+	}
+
+	contracts, err := strconv.Atoi(command[4])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[4])
+	}
+
+	days, err := strconv.Atoi(command[5])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[5])
+	}
+
+	settlement := gamecomm.CashSettlement
+	if len(command) == 7 {
+		switch command[6] {
+		case "cash":
+		case "physical":
+			settlement = gamecomm.PhysicalSettlement
+		default:
+			return fmt.Errorf("%v needs to be cash or physical", command[6])
+		}
+	}
+
+	id, err := g.OpenFuturesPosition(command[1], 1, command[2], side, contracts, days, settlement, g.PlayerState.NotificationChan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Futures position %v opened\n", id)
+
+	return nil
+}
+
+// close-future <zone> <position>
+func (g *Game) closeFuturesPosition(command []string) error {
+	margin, err := g.CloseFuturesPosition(command[1], command[2], 1)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Futures position %v closed, %.2f credits returned\n", command[2], margin)
+
+	return nil
+}
+
+// futures <zone>
+func (g *Game) listFuturesPositions(command []string) error {
+	positions, err := g.GetFuturesPositions(command[1], 1)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range positions {
+		fmt.Printf("%v: %v x%v %v, entry %.2f, mark %.2f, margin %.2f, delivery at %v\n", p.Id, p.Side, p.Contracts, p.Resource, p.EntryPrice, p.MarkPrice, p.Margin, p.DeliveryTime)
+	}
+
+	return nil
+}
+
+// issue <shares> <price>
+func (g *Game) issueShares(command []string) error {
+	amount, err := strconv.Atoi(command[1])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[1])
+	}
+
+	price, err := strconv.ParseFloat(command[2], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[2])
+	}
+
+	id, err := g.IssueShares(1, amount, price, g.PlayerState.NotificationChan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%v shares issued, offered on order %v\n", amount, id)
+
+	return nil
+}
+
+// buy-shares <corporation> <shares> <price>
+func (g *Game) buyShares(command []string) error {
+	return g.placeShareOrder(command, gamecomm.Buy)
+}
+
+// sell-shares <corporation> <shares> <price>
+func (g *Game) sellShares(command []string) error {
+	return g.placeShareOrder(command, gamecomm.Sell)
+}
+
+func (g *Game) placeShareOrder(command []string, side gamecomm.OrderSide) error {
+	issuerId, err := strconv.ParseUint(command[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a corporation id", command[1])
+	}
+
+	amount, err := strconv.Atoi(command[2])
 	if err != nil {
 		return fmt.Errorf("%v needs to be an integer", command[2])
 	}
 
-	return g.HarvestPlanet(planetId, 1, squadId, g.PlayerState.NotificationChan)
+	price, err := strconv.ParseFloat(command[3], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[3])
+	}
+
+	id, err := g.PlaceShareOrder(1, issuerId, side, amount, price, g.PlayerState.NotificationChan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Share order %v placed\n", id)
+
+	return nil
+}
+
+// cancel-order <order>
+func (g *Game) cancelShareOrder(command []string) error {
+	return g.CancelShareOrder(1, command[1])
+}
+
+// dividend <credits per share>
+func (g *Game) payDividend(command []string) error {
+	perShare, err := strconv.ParseFloat(command[1], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[1])
+	}
+
+	paid, err := g.PayDividend(1, perShare)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%.2f credits paid to the shareholders\n", paid)
+
+	return nil
+}
+
+// stocks
+func (g *Game) listStocks(command []string) error {
+	stocks, err := g.GetStocks()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stocks {
+		fmt.Printf("%v %v: %v shares at %.2f, market cap %.2f, net worth %.2f, dividend %.2f\n", s.IssuerId, s.Name, s.Shares, s.Price, s.MarketCap, s.NetWorth, s.Dividend)
+	}
+
+	return nil
+}
+
+// portfolio
+func (g *Game) showPortfolio(command []string) error {
+	portfolio, err := g.GetPortfolio(1)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range portfolio.Holdings {
+		fmt.Printf("%v %v: %v shares at %.2f, value %.2f\n", h.IssuerId, h.Name, h.Shares, h.Price, h.Value)
+	}
+
+	for _, o := range portfolio.Orders {
+		side := "buy"
+		if o.Side == gamecomm.Sell {
+			side = "sell"
+		}
+
+		fmt.Printf("%v: %v %v shares of %v at %.2f\n", o.Id, side, o.Amount, o.IssuerId, o.Price)
+	}
+
+	fmt.Printf("Portfolio value %.2f\n", portfolio.Value)
+
+	return nil
+}
+
+// loan-offer <credits> <months> [resource:amount|squad:index ...]
+// loan <credits> <months> [resource:amount|squad:index ...]
+func (g *Game) loan(command []string) error {
+	principal, err := strconv.ParseFloat(command[1], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[1])
+	}
+
+	months, err := strconv.Atoi(command[2])
+	if err != nil {
+		return fmt.Errorf("%v needs to be an integer", command[2])
+	}
+
+	collateral, err := parseCollateral(command[3:])
+	if err != nil {
+		return err
+	}
+
+	if command[0] == "loan-offer" {
+		offer, err := g.GetLoanOffer(1, principal, months, collateral)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%.2f credits for %v months at %.2f%% a month, %.2f credits a month, collateral worth %.2f\n", offer.Principal, offer.Months, offer.Rate*100, offer.Payment, offer.CollateralValue)
+
+		return nil
+	}
+
+	l, err := g.TakeLoan(1, principal, months, collateral, g.PlayerState.NotificationChan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Loan %v taken, %.2f credits a month\n", l.Id, l.Payment)
+
+	return nil
+}
+
+func parseCollateral(items []string) (gamecomm.Collateral, error) {
+	collateral := gamecomm.Collateral{Resources: make(map[string]int)}
+
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, ":")
+		if !ok {
+			return gamecomm.Collateral{}, fmt.Errorf("%v needs to be resource:amount or squad:index", item)
+		}
+
+		amount, err := strconv.Atoi(value)
+		if err != nil {
+			return gamecomm.Collateral{}, fmt.Errorf("%v needs to be an integer", value)
+		}
+
+		if name == "squad" {
+			collateral.Squads = append(collateral.Squads, amount)
+			continue
+		}
+
+		collateral.Resources[name] += amount
+	}
+
+	return collateral, nil
+}
+
+// repay <loan> <credits>
+func (g *Game) repayLoan(command []string) error {
+	credits, err := strconv.ParseFloat(command[2], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[2])
+	}
+
+	balance, err := g.RepayLoan(1, command[1], credits)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%.2f credits left on %v\n", balance, command[1])
+
+	return nil
+}
+
+// loans
+func (g *Game) listLoans(command []string) error {
+	loans, err := g.GetLoans(1)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range loans {
+		fmt.Printf("%v: balance %.2f of %.2f at %.2f%% a month, %v payments of %.2f left, next at %v, %v missed\n", l.Id, l.Balance, l.Principal, l.Rate*100, l.PaymentsLeft, l.Payment, l.NextPayment, l.MissedPayments)
+	}
+
+	return nil
+}
+
+// credit-line
+func (g *Game) creditLine(command []string) error {
+	cl, err := g.OpenCreditLine(1, g.PlayerState.NotificationChan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Credit line: %.2f of %.2f drawn at %.2f%% a month, next payment at %v\n", cl.Balance, cl.Limit, cl.Rate*100, cl.NextPayment)
+
+	return nil
+}
+
+// draw <credits>
+func (g *Game) drawCredit(command []string) error {
+	credits, err := strconv.ParseFloat(command[1], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[1])
+	}
+
+	balance, err := g.DrawCredit(1, credits)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Credit line balance %.2f\n", balance)
+
+	return nil
+}
+
+// repay-credit <credits>
+func (g *Game) repayCredit(command []string) error {
+	credits, err := strconv.ParseFloat(command[1], 64)
+	if err != nil {
+		return fmt.Errorf("%v needs to be a number", command[1])
+	}
+
+	balance, err := g.RepayCredit(1, credits)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Credit line balance %.2f\n", balance)
+
+	return nil
+}
+
+// ledger <type:id> [from hour] [to hour]
+func (g *Game) listLedgerEntries(command []string) error {
+	account, err := gamecomm.ParseAccount(command[1])
+	if err != nil {
+		return err
+	}
+
+	from := gameclock.GameTime(0)
+	to := g.gameClock.GetCurrentTime()
+
+	if len(command) > 2 {
+		hour, err := strconv.ParseUint(command[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%v needs to be a number", command[2])
+		}
+		from = gameclock.GameTime(hour)
+	}
+
+	if len(command) > 3 {
+		hour, err := strconv.ParseUint(command[3], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%v needs to be a number", command[3])
+		}
+		to = gameclock.GameTime(hour)
+	}
+
+	for _, e := range g.GetLedgerEntries(account, from, to) {
+		for _, p := range e.Postings {
+			if p.Account == account {
+				fmt.Printf("%v #%v %v: %+.2f %v\n", e.Time, e.Id, e.Memo, p.Amount, p.Asset)
+			}
+		}
+	}
+
+	for asset, amount := range g.GetLedgerBalance(account) {
+		fmt.Printf("Balance: %.2f %v\n", amount, asset)
+	}
+
+	return nil
+}
+
+// ledger-check
+func (g *Game) checkLedger(command []string) {
+	report := g.CheckLedger()
+
+	for _, id := range report.Unbalanced {
+		fmt.Printf("Entry #%v is unbalanced\n", id)
+	}
+
+	for _, m := range report.Mismatches {
+		fmt.Printf("%v holds %.2f %v but the ledger says %.2f\n", m.Account, m.Actual, m.Asset, m.Ledger)
+	}
+
+	for asset, amount := range report.Unattributed {
+		fmt.Printf("%.2f %v moved without a counterparty\n", amount, asset)
+	}
+
+	for account, assets := range report.External {
+		for asset, amount := range assets {
+			fmt.Printf("External %v: %+.2f %v\n", account, amount, asset)
+		}
+	}
+
+	if report.Consistent() {
+		fmt.Printf("Ledger is consistent, %v entries checked\n", report.Entries)
+	} else {
+		fmt.Printf("Ledger is NOT consistent, %v entries checked\n", report.Entries)
+	}
 }
 
 func newPlayer() *PlayerState {
@@ -179,14 +1015,23 @@ func newPlayer() *PlayerState {
 			Location:        world.Coordinates{X: 0, Y: 0},
 			StorageCapacity: 50_000,
 			StoredResources: map[string]int{
-				"iron":  100_000_000_000,
-				"gold":  100_000_000_000,
-				"water": 100_000_000_000,
-				"food":  100_000_000_000,
+				"iron":   10_000,
+				"gold":   2_000,
+				"water":  10_000,
+				"food":   10_000,
+				"energy": 5_000,
+			},
+			Facilities: []corporation.Facility{
+				{Type: corporation.PowerPlant, Level: 1},
+				{Type: corporation.WaterExtractor, Level: 1},
 			},
 		},
 	}
 
+	for _, b := range playerBases {
+		b.UpdateProduction()
+	}
+
 	crewMembers := []*corporation.CrewMember{
 		{
 			ID:         1,
@@ -5,11 +5,11 @@ import (
 )
 
 // TODO: Need to add inbound base when Base system is implemented
-func (g *Game) HarvestPlanet(planetId string, corporationId uint64, squadId int, notificationChan chan string) error {
+func (g *Game) HarvestPlanet(planetId string, corporationId uint64, squadIds []int, notificationChan chan string) error {
 
 	mc := gamecomm.MissionCommand{
 		CorporationId:    corporationId,
-		Squads:           []int{squadId},
+		Squads:           squadIds,
 		Type:             gamecomm.SquadMission,
 		Resources:        []string{string("iron")},
 		NotificationChan: notificationChan,
@@ -0,0 +1,29 @@
+package game
+
+import (
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// GetLedgerEntries returns the entries posted on the account between from and to, both included
+func (g *Game) GetLedgerEntries(account gamecomm.Account, from gameclock.GameTime, to gameclock.GameTime) []gamecomm.LedgerEntry {
+	return g.Ledger.Entries(account, from, to)
+}
+
+func (g *Game) GetLedgerBalance(account gamecomm.Account) map[string]float64 {
+	return g.Ledger.Balance(account)
+}
+
+// CheckLedger compares the ledger with what the corporations, the planets and the economy escrows actually hold
+func (g *Game) CheckLedger() gamecomm.LedgerReport {
+	holdings := g.Corporations.Holdings()
+	for account, assets := range g.World.Holdings() {
+		holdings[account] = assets
+	}
+
+	for account, assets := range g.Economy.Holdings() {
+		holdings[account] = assets
+	}
+
+	return g.Ledger.Check(holdings)
+}
@@ -27,6 +27,7 @@ type GameClock struct {
 	rw                  sync.RWMutex
 	newTickerMultiplier chan float64
 	dayChanSubcribers   []chan GameTime
+	hourChanSubcribers  []chan GameTime
 }
 
 func NewGameClock(initialTime GameTime, gameSpeedMultiplier float64) *GameClock {
@@ -34,6 +35,7 @@ func NewGameClock(initialTime GameTime, gameSpeedMultiplier float64) *GameClock
 		currentTime:         initialTime,
 		newTickerMultiplier: make(chan float64),
 		dayChanSubcribers:   []chan GameTime{},
+		hourChanSubcribers:  []chan GameTime{},
 	}
 
 	gc.setTickerInterval(gameSpeedMultiplier)
@@ -48,6 +50,13 @@ func (gc *GameClock) Update() {
 
 	gc.currentTime++
 
+	for _, subscriber := range gc.hourChanSubcribers {
+		select {
+		case subscriber <- gc.currentTime:
+		default:
+		}
+	}
+
 	if gc.currentTime%hoursPerDay == 0 {
 		for _, subscriber := range gc.dayChanSubcribers {
 			select {
@@ -62,6 +71,11 @@ func (gc *GameClock) Subscribe(subscriber chan GameTime) {
 	gc.dayChanSubcribers = append(gc.dayChanSubcribers, subscriber)
 }
 
+// SubscribeHour sends the time to the subscriber every game hour
+func (gc *GameClock) SubscribeHour(subscriber chan GameTime) {
+	gc.hourChanSubcribers = append(gc.hourChanSubcribers, subscriber)
+}
+
 func (gc *GameClock) GetCurrentTime() GameTime {
 	gc.rw.RLock()
 	defer gc.rw.RUnlock()
@@ -1,5 +1,7 @@
 package gamecomm
 
+import "github.com/luisya22/galactic-exchange/internal/gameclock"
+
 type Corporation struct {
 	ID                              uint64
 	Name                            string
@@ -10,6 +12,36 @@ type Corporation struct {
 	Squads                          []*Squad
 	IsPlayer                        bool
 	ReputationWithOtherCorporations map[string]int
+	Status                          CorporationStatus
+}
+
+type CorporationStatus int
+
+const (
+	Solvent CorporationStatus = iota
+	// Insolvent corporations have negative credits, they are liquidated if they don't recover in the grace period
+	Insolvent
+	Bankrupt
+)
+
+type CorporationEventType int
+
+const (
+	CorporationInsolvent CorporationEventType = iota
+	CorporationRecovered
+	CorporationLiquidated
+	CorporationRestructured
+	CorporationBankrupt
+)
+
+// CorporationEvent tells the other actors about a change in the solvency of a corporation
+type CorporationEvent struct {
+	Type          CorporationEventType
+	CorporationId uint64
+	Name          string
+	IsPlayer      bool
+	Credits       float64
+	Time          gameclock.GameTime
 }
 
 type Base struct {
@@ -17,8 +49,30 @@ type Base struct {
 	Name               string
 	Location           Coordinates
 	ResourceProduction map[string]int
+	ResourceUpkeep     map[string]int
+	CreditUpkeep       float64
 	StorageCapacity    float64
 	StoredResources    map[string]int
+	Facilities         []Facility
+	Constructions      []string
+	Jobs               []ProductionJob
+}
+
+type Facility struct {
+	Type  string
+	Level int
+}
+
+type ProductionJob struct {
+	Recipe   string
+	Batches  int
+	Duration gameclock.GameTimeDuration
+}
+
+type ConstructionCost struct {
+	Credits   float64
+	Materials map[string]int
+	BuildTime gameclock.GameTimeDuration
 }
 
 type CrewMember struct {
@@ -26,7 +80,36 @@ type CrewMember struct {
 	Name       string
 	Species    string
 	Skills     map[string]int
+	Experience map[string]int
+	Status     CrewStatus
 	AssignedTo uint64
+	History    []CrewRecord
+}
+
+type CrewStatus int
+
+const (
+	CrewActive CrewStatus = iota
+	CrewInjured
+	CrewDead
+)
+
+const (
+	HarvestingSkill = "harvesting"
+	PilotingSkill   = "piloting"
+	TradingSkill    = "trading"
+	CombatSkill     = "combat"
+)
+
+// CrewRecord is an entry of a crew member history. Every mission the crew member takes part in leaves
+// one record with the experience earned and what happened to them.
+type CrewRecord struct {
+	CrewId     uint64
+	MissionId  string
+	Skill      string
+	Experience int
+	LevelUp    bool
+	Status     CrewStatus
 }
 
 type Squad struct {
@@ -35,7 +118,48 @@ type Squad struct {
 	CrewMembers []CrewMember
 	Cargo       map[string]int
 	Location    Coordinates
-	// Officers []Officers   coming soon...
+	Officers    map[OfficerRole]CrewMember
+	Bonuses     SquadBonuses
+}
+
+type OfficerRole string
+
+const (
+	Captain       OfficerRole = "captain"
+	Engineer      OfficerRole = "engineer"
+	Quartermaster OfficerRole = "quartermaster"
+	Navigator     OfficerRole = "navigator"
+)
+
+// SquadBonuses are the squad-wide bonuses granted by its officers. Each value is a fraction added on top of
+// the base value, so 0.1 means 10% faster travel, 10% more harvested resources, 10% cheaper repairs or 10%
+// more crew experience.
+type SquadBonuses struct {
+	TravelSpeed  float64
+	HarvestYield float64
+	RepairCost   float64
+	Experience   float64
+}
+
+// SkillModifier returns the multiplier the squad crew applies to missions using the skill. Each average
+// level of the active crew adds 5% to the mission outcome.
+func (s Squad) SkillModifier(skill string) float64 {
+	total := 0
+	active := 0
+	for _, cm := range s.CrewMembers {
+		if cm.Status != CrewActive {
+			continue
+		}
+
+		total += cm.Skills[skill]
+		active++
+	}
+
+	if active == 0 {
+		return 1
+	}
+
+	return 1 + 0.05*float64(total)/float64(active)
 }
 
 type Ship struct {
@@ -1,29 +1,259 @@
 package gamecomm
 
+import "github.com/luisya22/galactic-exchange/internal/gameclock"
+
 // World Channels
 type EconomyCommand struct {
-	Action          EconomyCommandType
-	MarketListingId string
-	ZoneId          string
-	Amount          int
-	Resource        string
-	Price           float64
-	CorporationId   uint64
-	BuyerPlanetId   string
-	ResponseChannel chan ChanResponse
+	Action           EconomyCommandType
+	MarketListingId  string
+	ZoneId           string
+	Amount           int
+	Resource         string
+	Price            float64
+	CorporationId    uint64
+	BuyerPlanetId    string
+	Days             int
+	Orders           []PurchaseOrder
+	Budgets          map[string]float64
+	AuctionId        string
+	Duration         gameclock.GameTimeDuration
+	NotificationChan chan string
+	ContractId       string
+	Side             FuturesSide
+	Settlement       SettlementType
+	IssuerId         uint64
+	OrderSide        OrderSide
+	OrderId          string
+	LoanId           string
+	Credits          float64
+	Months           int
+	Collateral       Collateral
+	Assets           map[string]int
+	ResponseChannel  chan ChanResponse
 }
 
 type EconomyCommandType int
 
 const (
 	AddMarketListing EconomyCommandType = iota
-	BuyMarketListing
 	GetMarketListings
 	GetMarketListingsByResource
 	EditMarketListingPrice
 	GetMarketPrice
+	GetPriceSpreads
+	GetPriceForecast
+	GetMarketPrices
+	BuyMarketListings
+	EditMarketListingAmount
+	GetMarketListingHistory
+	CreateAuction
+	PlaceBid
+	GetAuctions
+	OpenFuturesPosition
+	CloseFuturesPosition
+	GetFuturesPositions
+	IssueShares
+	PlaceShareOrder
+	CancelShareOrder
+	PayDividend
+	GetStocks
+	GetPortfolio
+	GetLoanOffer
+	TakeLoan
+	RepayLoan
+	GetLoans
+	OpenCreditLine
+	DrawCredit
+	RepayCredit
+	GetCreditLine
+	LiquidateAssets
+)
+
+// Collateral is what a corporation pledges for a loan: goods of its base and the ships of its squads. The bank
+// seizes it when the loan defaults.
+type Collateral struct {
+	Resources map[string]int
+	Squads    []int
+}
+
+// LoanOffer is what the bank asks for a loan before the corporation takes it, the rate is monthly
+type LoanOffer struct {
+	CorporationId   uint64
+	Principal       float64
+	Months          int
+	Rate            float64
+	Payment         float64
+	CollateralValue float64
+}
+
+// Loan is credits a corporation owes the bank, paid back every month
+type Loan struct {
+	Id             string
+	CorporationId  uint64
+	Principal      float64
+	Balance        float64
+	Rate           float64
+	Payment        float64
+	PaymentsLeft   int
+	MissedPayments int
+	NextPayment    gameclock.GameTime
+	Collateral     Collateral
+}
+
+// CreditLine is credits a corporation can draw up to the limit, every month it pays the interest and part of the
+// balance
+type CreditLine struct {
+	CorporationId  uint64
+	Limit          float64
+	Balance        float64
+	Rate           float64
+	MissedPayments int
+	NextPayment    gameclock.GameTime
+	Frozen         bool
+}
+
+type OrderSide int
+
+const (
+	Buy OrderSide = iota
+	Sell
+)
+
+// Stock is the shares a corporation issued on the exchange
+type Stock struct {
+	IssuerId  uint64
+	Name      string
+	Shares    int
+	Price     float64
+	MarketCap float64
+	NetWorth  float64
+	Dividend  float64
+}
+
+// ShareOrder is an order to buy or sell shares of the issuer at the price or better
+type ShareOrder struct {
+	Id            string
+	IssuerId      uint64
+	CorporationId uint64
+	Side          OrderSide
+	Amount        int
+	Price         float64
+}
+
+// ShareHolding is the shares of an issuer a corporation owns, shares in open sell orders included
+type ShareHolding struct {
+	IssuerId uint64
+	Name     string
+	Shares   int
+	Price    float64
+	Value    float64
+}
+
+// Portfolio is every share a corporation owns and its open orders on the exchange
+type Portfolio struct {
+	CorporationId uint64
+	Holdings      []ShareHolding
+	Orders        []ShareOrder
+	Value         float64
+}
+
+type FuturesSide int
+
+const (
+	Long FuturesSide = iota
+	Short
+)
+
+type SettlementType int
+
+const (
+	CashSettlement SettlementType = iota
+	PhysicalSettlement
 )
 
+// FuturesPosition is a number of standard contracts to buy or sell a resource in a zone on the delivery date
+type FuturesPosition struct {
+	Id            string
+	ZoneId        string
+	CorporationId uint64
+	Resource      string
+	Contracts     int
+	ContractSize  int
+	Side          FuturesSide
+	Settlement    SettlementType
+	EntryPrice    float64
+	MarkPrice     float64
+	Margin        float64
+	DeliveryTime  gameclock.GameTime
+}
+
+// PriceSpread is the price difference of a resource between the cheapest and the most expensive of two zones
+type PriceSpread struct {
+	Resource   string
+	LowZoneId  string
+	LowPrice   float64
+	HighZoneId string
+	HighPrice  float64
+	Spread     float64
+	Distance   float64
+}
+
+// Auction is a lot of resources sold to the highest bidder when the auction ends
+type Auction struct {
+	Id              string
+	ZoneId          string
+	SellerId        uint64
+	Resource        string
+	Amount          int
+	ReservePrice    float64
+	HighestBid      float64
+	HighestBidderId uint64
+	EndTime         gameclock.GameTime
+}
+
+// ListingEdit is a change made by the owner of a market listing
+type ListingEdit struct {
+	ListingId     string
+	CorporationId uint64
+	Field         string
+	OldValue      float64
+	NewValue      float64
+	Time          gameclock.GameTime
+}
+
+// PurchaseOrder is what a planet wants to buy, ListingIds are the listings to buy from in order of preference
+type PurchaseOrder struct {
+	BuyerPlanetId string
+	Resource      string
+	Amount        int
+	ListingIds    []string
+}
+
+// PurchaseFill is what an order got from a listing, Order is the index of the order in the batch
+type PurchaseFill struct {
+	Order     int
+	ListingId string
+	Requested int
+	Amount    int
+	Price     float64
+}
+
+// PriceForecast is the projected price of a resource in a zone for the next days
+type PriceForecast struct {
+	ZoneId       string
+	Resource     string
+	CurrentPrice float64
+	Projections  []PriceProjection
+}
+
+// PriceProjection is the expected price of a day with its confidence band
+type PriceProjection struct {
+	Day   int
+	Price float64
+	Low   float64
+	High  float64
+}
+
 type MarketListing struct {
 	Id            string
 	ResourceName  string
@@ -32,6 +32,8 @@ type WorldCommand struct {
 	Amount          int
 	ResponseChannel chan ChanResponse
 	Resource        string
+	Counterparty    Account
+	Memo            string
 }
 
 type WorldCommandType int
@@ -41,6 +43,8 @@ const (
 	AddResourcesToPlanet
 	RemoveResourcesFromPlanet
 	GetZone
+	GetPlanetDemand
+	GetZoneDemand
 )
 
 // Corporation Channels
@@ -53,6 +57,18 @@ type CorpCommand struct {
 	Resource        string
 	Amount          int
 	AmountDecimal   float64
+	CrewId          uint64
+	Skill           string
+	DangerLevel     int
+	MissionId       string
+	OfficerRole     OfficerRole
+	Facility        string
+	Name            string
+	Location        Coordinates
+	Recipe          string
+	// Counterparty is the ledger account the credits or goods of the command come from or go to
+	Counterparty Account
+	Memo         string
 }
 
 type CommandType int
@@ -67,6 +83,22 @@ const (
 	RemoveResourcesFromBase
 	AddCredits
 	RemoveCredits
+	TrainSquadCrew
+	GetCrewMember
+	AssignOfficer
+	RemoveOfficer
+	GetBaseConstructionCost
+	StartBaseConstruction
+	FoundBase
+	StartFacilityConstruction
+	CompleteFacilityConstruction
+	StartProduction
+	CompleteProduction
+	SeizeShip
+	ChargeCredits
+	CancelBaseConstruction
+	CancelFacilityConstruction
+	CancelProduction
 )
 
 // Mission Channels
@@ -82,6 +114,10 @@ type MissionCommand struct {
 	Resources        []string
 	NotificationChan chan string
 	Amount           int
+	BaseIndex        int
+	BaseName         string
+	Facility         string
+	Recipe           string
 }
 
 type MissionType int
@@ -90,4 +126,7 @@ const (
 	SquadMission MissionType = iota
 	QuestMission
 	TransferMission
+	BaseConstructionMission
+	FacilityConstructionMission
+	ProductionMission
 )
@@ -0,0 +1,138 @@
+package gamecomm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+)
+
+// CreditsAsset is the asset name of credits on the ledger, goods use their resource name
+const CreditsAsset = "credits"
+
+type AccountType int
+
+const (
+	CorporationAccount AccountType = iota
+	BaseAccount
+	SquadAccount
+	PlanetAccount
+	// EscrowAccount holds credits and goods in transit, like a mission cargo or the bids of an auction
+	EscrowAccount
+	// ExternalAccount is where credits and goods come from or go to when they enter or leave the game, like
+	// production, upkeep or wages
+	ExternalAccount
+)
+
+var accountTypeNames = map[AccountType]string{
+	CorporationAccount: "corporation",
+	BaseAccount:        "base",
+	SquadAccount:       "squad",
+	PlanetAccount:      "planet",
+	EscrowAccount:      "escrow",
+	ExternalAccount:    "external",
+}
+
+// Account is who holds credits or goods on the ledger. The zero Account is unattributed, commands that don't say
+// where their credits or goods come from are posted against it.
+type Account struct {
+	Type AccountType
+	Id   string
+}
+
+func NewCorporationAccount(corporationId uint64) Account {
+	return Account{Type: CorporationAccount, Id: strconv.FormatUint(corporationId, 10)}
+}
+
+func NewBaseAccount(corporationId uint64, baseIndex int) Account {
+	return Account{Type: BaseAccount, Id: fmt.Sprintf("%d/%d", corporationId, baseIndex)}
+}
+
+func NewSquadAccount(corporationId uint64, squadIndex int) Account {
+	return Account{Type: SquadAccount, Id: fmt.Sprintf("%d/%d", corporationId, squadIndex)}
+}
+
+func NewPlanetAccount(planetId string) Account {
+	return Account{Type: PlanetAccount, Id: planetId}
+}
+
+func NewEscrowAccount(name string) Account {
+	return Account{Type: EscrowAccount, Id: name}
+}
+
+func NewExternalAccount(name string) Account {
+	return Account{Type: ExternalAccount, Id: name}
+}
+
+// BaseConstructionEscrow holds the materials of a new base from the moment they are paid until the squads load them
+func BaseConstructionEscrow(corporationId uint64) Account {
+	return NewEscrowAccount(fmt.Sprintf("base-construction/%d", corporationId))
+}
+
+// MarketEscrow holds the goods of the market listings of the zone and the credits planets pay for them until they
+// are delivered and the sellers are paid
+func MarketEscrow(zoneId string) Account {
+	return NewEscrowAccount(fmt.Sprintf("market/%v", zoneId))
+}
+
+func (a Account) IsZero() bool {
+	return a == Account{}
+}
+
+func (a Account) String() string {
+	return accountTypeNames[a.Type] + ":" + a.Id
+}
+
+// ParseAccount reads an account written as type:id, like corporation:1, base:1/0 or planet:Planet-1
+func ParseAccount(s string) (Account, error) {
+	name, id, ok := strings.Cut(s, ":")
+	if !ok || id == "" {
+		return Account{}, fmt.Errorf("error: account should be written as type:id")
+	}
+
+	for t, n := range accountTypeNames {
+		if n == name {
+			return Account{Type: t, Id: id}, nil
+		}
+	}
+
+	return Account{}, fmt.Errorf("error: invalid account type %v", name)
+}
+
+// Posting is the change of one asset on one account, the postings of an entry add up to zero for every asset
+type Posting struct {
+	Account Account
+	Asset   string
+	Amount  float64
+}
+
+type LedgerEntry struct {
+	Id       uint64
+	Time     gameclock.GameTime
+	Memo     string
+	Postings []Posting
+}
+
+// LedgerMismatch is an asset whose ledger balance doesn't match what the account actually holds
+type LedgerMismatch struct {
+	Account Account
+	Asset   string
+	Ledger  float64
+	Actual  float64
+}
+
+// LedgerReport is the result of the consistency check of the ledger. External has the net amount of every asset
+// that came into the game (negative) or left it (positive) through each external account.
+type LedgerReport struct {
+	Entries      int
+	Unbalanced   []uint64
+	Mismatches   []LedgerMismatch
+	Unattributed map[string]float64
+	External     map[string]map[string]float64
+}
+
+// Consistent is true when no credits or goods were created or destroyed outside of the external accounts
+func (r LedgerReport) Consistent() bool {
+	return len(r.Unbalanced) == 0 && len(r.Mismatches) == 0 && len(r.Unattributed) == 0
+}
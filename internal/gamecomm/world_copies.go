@@ -9,12 +9,18 @@ type Planet struct {
 	Location       Coordinates
 	Resources      map[string]int
 	Population     int
+	Treasury       float64
 	DangerLevel    int
-	ResourceDemand map[string]int
+	ResourceDemand map[string]ResourceDemand
 	IsHabitable    bool
 	IsHarvestable  bool
 }
 
+type ResourceDemand struct {
+	Quantity int
+	MaxPrice float64
+}
+
 type Coordinates struct {
 	X float64
 	Y float64
@@ -4,4 +4,5 @@ import "embed"
 
 //go:embed resourcedata/*
 //go:embed categorydata/*
+//go:embed recipedata/*
 var Files embed.FS
@@ -0,0 +1,350 @@
+package ledger
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// retention is how long entries are checked, older entries are folded into the opening balances and archived
+	retention = 30 * gameclock.Day
+	// tolerance is the relative difference under which two amounts are the same, it absorbs the rounding of credits
+	tolerance = 1e-6
+)
+
+// Unattributed is the account of the credits and goods moved by commands that don't say their counterparty
+var Unattributed = gamecomm.NewExternalAccount("unattributed")
+
+type balances map[gamecomm.Account]map[string]float64
+
+func (b balances) add(p gamecomm.Posting) {
+	assets, ok := b[p.Account]
+	if !ok {
+		assets = make(map[string]float64)
+		b[p.Account] = assets
+	}
+
+	assets[p.Asset] += p.Amount
+}
+
+func (b balances) copy() balances {
+	c := make(balances, len(b))
+	for account, assets := range b {
+		for asset, amount := range assets {
+			c.add(gamecomm.Posting{Account: account, Asset: asset, Amount: amount})
+		}
+	}
+
+	return c
+}
+
+// Ledger records every movement of credits and goods as balanced entries between accounts. A nil Ledger records
+// nothing, so the actors can run without one.
+type Ledger struct {
+	entries   []gamecomm.LedgerEntry
+	archive   []gamecomm.LedgerEntry
+	opening   balances
+	balances  balances
+	counter   uint64
+	gameClock *gameclock.GameClock
+	rw        sync.RWMutex
+}
+
+func New(gc *gameclock.GameClock) *Ledger {
+	return &Ledger{
+		entries:   []gamecomm.LedgerEntry{},
+		archive:   []gamecomm.LedgerEntry{},
+		opening:   make(balances),
+		balances:  make(balances),
+		gameClock: gc,
+	}
+}
+
+// Post records the postings as one entry. Entries whose postings don't add up to zero for every asset are refused.
+func (l *Ledger) Post(memo string, postings ...gamecomm.Posting) error {
+	if l == nil {
+		return nil
+	}
+
+	entryPostings := make([]gamecomm.Posting, 0, len(postings))
+	for _, p := range postings {
+		if p.Amount == 0 {
+			continue
+		}
+
+		if p.Account.IsZero() {
+			p.Account = Unattributed
+		}
+
+		entryPostings = append(entryPostings, p)
+	}
+
+	if len(entryPostings) == 0 {
+		return nil
+	}
+
+	if asset, ok := balanced(entryPostings); !ok {
+		return fmt.Errorf("error: entry '%v' is unbalanced for %v", memo, asset)
+	}
+
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	now := l.gameClock.GetCurrentTime()
+
+	l.counter++
+	l.entries = append(l.entries, gamecomm.LedgerEntry{
+		Id:       l.counter,
+		Time:     now,
+		Memo:     memo,
+		Postings: entryPostings,
+	})
+
+	for _, p := range entryPostings {
+		l.balances.add(p)
+	}
+
+	l.trim(now)
+
+	return nil
+}
+
+// Transfer moves the amount of the asset from one account to the other
+func (l *Ledger) Transfer(memo string, from gamecomm.Account, to gamecomm.Account, asset string, amount float64) {
+	err := l.Post(memo,
+		gamecomm.Posting{Account: from, Asset: asset, Amount: -amount},
+		gamecomm.Posting{Account: to, Asset: asset, Amount: amount},
+	)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+// trim folds the entries older than the retention into the opening balances and moves them to the archive, the
+// check starts from the opening balances and the range queries still read the archive
+func (l *Ledger) trim(now gameclock.GameTime) {
+	if now < gameclock.GameTime(retention) {
+		return
+	}
+
+	cutoff := now - gameclock.GameTime(retention)
+
+	i := 0
+	for i < len(l.entries) && l.entries[i].Time < cutoff {
+		for _, p := range l.entries[i].Postings {
+			l.opening.add(p)
+		}
+		i++
+	}
+
+	l.archive = append(l.archive, l.entries[:i]...)
+	l.entries = l.entries[i:]
+}
+
+// Entries returns the entries with a posting on the account between from and to, both included
+func (l *Ledger) Entries(account gamecomm.Account, from gameclock.GameTime, to gameclock.GameTime) []gamecomm.LedgerEntry {
+	if l == nil {
+		return []gamecomm.LedgerEntry{}
+	}
+
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	// Archived entries are older than the ones kept
+	entries := entriesBetween(l.archive, account, from, to)
+	entries = append(entries, entriesBetween(l.entries, account, from, to)...)
+
+	return entries
+}
+
+// entriesBetween returns copies of the entries with a posting on the account between from and to, the entries are
+// sorted by time
+func entriesBetween(entries []gamecomm.LedgerEntry, account gamecomm.Account, from gameclock.GameTime, to gameclock.GameTime) []gamecomm.LedgerEntry {
+	start := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Time >= from
+	})
+
+	result := []gamecomm.LedgerEntry{}
+	for _, e := range entries[start:] {
+		if e.Time > to {
+			break
+		}
+
+		for _, p := range e.Postings {
+			if p.Account == account {
+				result = append(result, copyEntry(e))
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// Balance returns what the ledger says the account holds of every asset
+func (l *Ledger) Balance(account gamecomm.Account) map[string]float64 {
+	balance := make(map[string]float64)
+	if l == nil {
+		return balance
+	}
+
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	for asset, amount := range l.balances[account] {
+		if amount != 0 {
+			balance[asset] = amount
+		}
+	}
+
+	return balance
+}
+
+// Check proves the ledger is consistent. It rebuilds the balances from the entries, checks every entry is balanced
+// and compares the balances with the holdings actually found on the corporations, bases, squads, planets and the
+// escrows of the economy. Accounts of those types missing from the holdings must be empty. Escrows nobody reports,
+// like the ones of missions in flight, can only be checked for holding less than nothing. Credits and goods are
+// only created or destroyed through external accounts, anything posted against the unattributed account is
+// reported.
+func (l *Ledger) Check(holdings map[gamecomm.Account]map[string]float64) gamecomm.LedgerReport {
+	report := gamecomm.LedgerReport{
+		Unbalanced:   []uint64{},
+		Mismatches:   []gamecomm.LedgerMismatch{},
+		Unattributed: make(map[string]float64),
+		External:     make(map[string]map[string]float64),
+	}
+
+	if l == nil {
+		return report
+	}
+
+	l.rw.RLock()
+	rebuilt := l.opening.copy()
+	for _, e := range l.entries {
+		if _, ok := balanced(e.Postings); !ok {
+			report.Unbalanced = append(report.Unbalanced, e.Id)
+		}
+
+		for _, p := range e.Postings {
+			rebuilt.add(p)
+		}
+	}
+	report.Entries = len(l.entries)
+	l.rw.RUnlock()
+
+	accounts := make(map[gamecomm.Account]bool, len(rebuilt)+len(holdings))
+	for account := range rebuilt {
+		accounts[account] = true
+	}
+	for account := range holdings {
+		accounts[account] = true
+	}
+
+	for account := range accounts {
+		switch account.Type {
+		case gamecomm.ExternalAccount:
+			external := make(map[string]float64)
+			for asset, amount := range rebuilt[account] {
+				if !near(amount, 0) {
+					external[asset] = amount
+				}
+			}
+
+			if account == Unattributed {
+				report.Unattributed = external
+				continue
+			}
+
+			if len(external) > 0 {
+				report.External[account.Id] = external
+			}
+		case gamecomm.EscrowAccount:
+			if actual, ok := holdings[account]; ok {
+				report.Mismatches = append(report.Mismatches, mismatches(account, rebuilt[account], actual)...)
+				continue
+			}
+
+			for asset, amount := range rebuilt[account] {
+				if amount < 0 && !near(amount, 0) {
+					report.Mismatches = append(report.Mismatches, gamecomm.LedgerMismatch{Account: account, Asset: asset, Ledger: amount})
+				}
+			}
+		default:
+			report.Mismatches = append(report.Mismatches, mismatches(account, rebuilt[account], holdings[account])...)
+		}
+	}
+
+	sort.Slice(report.Mismatches, func(i, j int) bool {
+		if report.Mismatches[i].Account != report.Mismatches[j].Account {
+			return report.Mismatches[i].Account.String() < report.Mismatches[j].Account.String()
+		}
+
+		return report.Mismatches[i].Asset < report.Mismatches[j].Asset
+	})
+
+	return report
+}
+
+func mismatches(account gamecomm.Account, ledger map[string]float64, actual map[string]float64) []gamecomm.LedgerMismatch {
+	result := []gamecomm.LedgerMismatch{}
+
+	assets := make(map[string]bool, len(ledger)+len(actual))
+	for asset := range ledger {
+		assets[asset] = true
+	}
+	for asset := range actual {
+		assets[asset] = true
+	}
+
+	for asset := range assets {
+		if !near(ledger[asset], actual[asset]) {
+			result = append(result, gamecomm.LedgerMismatch{
+				Account: account,
+				Asset:   asset,
+				Ledger:  ledger[asset],
+				Actual:  actual[asset],
+			})
+		}
+	}
+
+	return result
+}
+
+// balanced returns the first asset the postings don't add up to zero for
+func balanced(postings []gamecomm.Posting) (string, bool) {
+	sums := make(map[string]float64)
+	scale := make(map[string]float64)
+	for _, p := range postings {
+		sums[p.Asset] += p.Amount
+		scale[p.Asset] = max(scale[p.Asset], math.Abs(p.Amount))
+	}
+
+	for asset, sum := range sums {
+		if math.Abs(sum) > tolerance*max(scale[asset], 1) {
+			return asset, false
+		}
+	}
+
+	return "", true
+}
+
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) <= tolerance*max(math.Abs(a), math.Abs(b), 1)
+}
+
+func copyEntry(e gamecomm.LedgerEntry) gamecomm.LedgerEntry {
+	postings := make([]gamecomm.Posting, len(e.Postings))
+	copy(postings, e.Postings)
+
+	return gamecomm.LedgerEntry{
+		Id:       e.Id,
+		Time:     e.Time,
+		Memo:     e.Memo,
+		Postings: postings,
+	}
+}
@@ -0,0 +1,277 @@
+package ledger_test
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
+)
+
+var (
+	corporationAccount = gamecomm.NewCorporationAccount(1)
+	planetAccount      = gamecomm.NewPlanetAccount("Planet-1")
+	openingAccount     = gamecomm.NewExternalAccount("opening")
+	auctionAccount     = gamecomm.NewEscrowAccount("auction/1")
+)
+
+func TestPost(t *testing.T) {
+	tests := []struct {
+		name        string
+		postings    []gamecomm.Posting
+		wants       float64
+		shouldError bool
+	}{
+		{
+			name: "Balanced Entry",
+			postings: []gamecomm.Posting{
+				{Account: corporationAccount, Asset: gamecomm.CreditsAsset, Amount: 100},
+				{Account: openingAccount, Asset: gamecomm.CreditsAsset, Amount: -100},
+			},
+			wants: 100,
+		},
+		{
+			name: "Unbalanced Entry",
+			postings: []gamecomm.Posting{
+				{Account: corporationAccount, Asset: gamecomm.CreditsAsset, Amount: 100},
+				{Account: openingAccount, Asset: gamecomm.CreditsAsset, Amount: -90},
+			},
+			shouldError: true,
+		},
+		{
+			name: "Balanced Credits Unbalanced Goods",
+			postings: []gamecomm.Posting{
+				{Account: corporationAccount, Asset: gamecomm.CreditsAsset, Amount: 100},
+				{Account: openingAccount, Asset: "iron", Amount: -100},
+			},
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := ledger.New(gameclock.NewGameClock(0, 1))
+
+			err := l.Post("test", tt.postings...)
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Equal(t, len(l.Entries(corporationAccount, 0, 100)), 0)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, l.Balance(corporationAccount)[gamecomm.CreditsAsset], tt.wants)
+		})
+	}
+}
+
+func TestEntries(t *testing.T) {
+	gc := gameclock.NewGameClock(0, 1)
+	l := ledger.New(gc)
+
+	for i := 0; i < 3; i++ {
+		l.Transfer("sale", planetAccount, corporationAccount, gamecomm.CreditsAsset, 10)
+		l.Transfer("harvest", planetAccount, gamecomm.NewSquadAccount(1, 0), "iron", 5)
+
+		for h := 0; h < gameclock.Day; h++ {
+			gc.Update()
+		}
+	}
+
+	tests := []struct {
+		name    string
+		account gamecomm.Account
+		from    gameclock.GameTime
+		to      gameclock.GameTime
+		wants   int
+	}{
+		{
+			name:    "All Entries Of Account",
+			account: corporationAccount,
+			from:    0,
+			to:      3 * gameclock.Day,
+			wants:   3,
+		},
+		{
+			name:    "Entries In Range",
+			account: corporationAccount,
+			from:    gameclock.Day,
+			to:      2 * gameclock.Day,
+			wants:   2,
+		},
+		{
+			name:    "Entries Of Both Sides",
+			account: planetAccount,
+			from:    0,
+			to:      3 * gameclock.Day,
+			wants:   6,
+		},
+		{
+			name:    "Unknown Account",
+			account: gamecomm.NewPlanetAccount("Planet-2"),
+			from:    0,
+			to:      3 * gameclock.Day,
+			wants:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, len(l.Entries(tt.account, tt.from, tt.to)), tt.wants)
+		})
+	}
+}
+
+func TestEntriesOlderThanRetention(t *testing.T) {
+	gc := gameclock.NewGameClock(0, 1)
+	l := ledger.New(gc)
+
+	l.Transfer("opening", openingAccount, corporationAccount, gamecomm.CreditsAsset, 100)
+
+	for h := 0; h < 40*gameclock.Day; h++ {
+		gc.Update()
+	}
+
+	// Posting after the retention folds the first entry into the opening balances
+	l.Transfer("purchase", corporationAccount, planetAccount, gamecomm.CreditsAsset, 40)
+
+	entries := l.Entries(corporationAccount, 0, gameclock.Day)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Memo, "opening")
+
+	assert.Equal(t, len(l.Entries(corporationAccount, 0, 40*gameclock.Day)), 2)
+	assert.Equal(t, len(l.Entries(corporationAccount, 39*gameclock.Day, 40*gameclock.Day)), 1)
+
+	report := l.Check(map[gamecomm.Account]map[string]float64{
+		corporationAccount: {gamecomm.CreditsAsset: 60},
+		planetAccount:      {gamecomm.CreditsAsset: 40},
+	})
+
+	assert.Equal(t, report.Entries, 1)
+	assert.Equal(t, report.Consistent(), true)
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		unattributed bool
+		escrow       bool
+		bid          bool
+		holdings     map[gamecomm.Account]map[string]float64
+		mismatches   int
+		consistent   bool
+	}{
+		{
+			name: "Consistent",
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount: {gamecomm.CreditsAsset: 60},
+				planetAccount:      {gamecomm.CreditsAsset: 40},
+			},
+			consistent: true,
+		},
+		{
+			name: "Credits Created Outside The Ledger",
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount: {gamecomm.CreditsAsset: 80},
+				planetAccount:      {gamecomm.CreditsAsset: 40},
+			},
+			mismatches: 1,
+		},
+		{
+			name: "Unknown Holdings",
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount:                    {gamecomm.CreditsAsset: 60},
+				planetAccount:                         {gamecomm.CreditsAsset: 40},
+				gamecomm.NewPlanetAccount("Planet-2"): {"iron": 10},
+			},
+			mismatches: 1,
+		},
+		{
+			name:         "Unattributed Movement",
+			unattributed: true,
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount: {gamecomm.CreditsAsset: 70},
+				planetAccount:      {gamecomm.CreditsAsset: 40},
+			},
+		},
+		{
+			name:   "Overdrawn Escrow",
+			escrow: true,
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount: {gamecomm.CreditsAsset: 70},
+				planetAccount:      {gamecomm.CreditsAsset: 40},
+			},
+			mismatches: 1,
+		},
+		{
+			name: "Escrow Matches Its Holdings",
+			bid:  true,
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount: {gamecomm.CreditsAsset: 40},
+				planetAccount:      {gamecomm.CreditsAsset: 40},
+				auctionAccount:     {gamecomm.CreditsAsset: 20},
+			},
+			consistent: true,
+		},
+		{
+			// The auction is gone but its escrow still holds the bid
+			name: "Escrow Holds What Nobody Has",
+			bid:  true,
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount: {gamecomm.CreditsAsset: 40},
+				planetAccount:      {gamecomm.CreditsAsset: 40},
+				auctionAccount:     {},
+			},
+			mismatches: 1,
+		},
+		{
+			name: "Unreported Escrow",
+			bid:  true,
+			holdings: map[gamecomm.Account]map[string]float64{
+				corporationAccount: {gamecomm.CreditsAsset: 40},
+				planetAccount:      {gamecomm.CreditsAsset: 40},
+			},
+			consistent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := ledger.New(gameclock.NewGameClock(0, 1))
+
+			l.Transfer("opening", openingAccount, corporationAccount, gamecomm.CreditsAsset, 100)
+			l.Transfer("purchase", corporationAccount, planetAccount, gamecomm.CreditsAsset, 40)
+
+			if tt.unattributed {
+				l.Transfer("refund", gamecomm.Account{}, corporationAccount, gamecomm.CreditsAsset, 10)
+			}
+
+			if tt.escrow {
+				l.Transfer("refund", auctionAccount, corporationAccount, gamecomm.CreditsAsset, 10)
+			}
+
+			if tt.bid {
+				l.Transfer("auction bid", corporationAccount, auctionAccount, gamecomm.CreditsAsset, 20)
+			}
+
+			report := l.Check(tt.holdings)
+
+			assert.Equal(t, report.Entries, len(l.Entries(corporationAccount, 0, 0)))
+			assert.Equal(t, len(report.Unbalanced), 0)
+			assert.Equal(t, len(report.Mismatches), tt.mismatches)
+			assert.Equal(t, report.Unattributed[gamecomm.CreditsAsset] != 0, tt.unattributed)
+			assert.Equal(t, report.External["opening"][gamecomm.CreditsAsset], -100.0)
+			assert.Equal(t, report.Consistent(), tt.consistent)
+		})
+	}
+}
+
+func TestNilLedger(t *testing.T) {
+	var l *ledger.Ledger
+
+	assert.NilError(t, l.Post("test", gamecomm.Posting{Account: corporationAccount, Asset: "iron", Amount: 10}))
+	assert.Equal(t, len(l.Entries(corporationAccount, 0, 100)), 0)
+	assert.Equal(t, len(l.Balance(corporationAccount)), 0)
+	assert.Equal(t, l.Check(nil).Consistent(), true)
+}
@@ -0,0 +1,277 @@
+package mission
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// baseSiteDistance is how far from the planet new bases are built
+const baseSiteDistance = 1.0
+
+// baseSite returns the coordinates where a base near the planet is built
+func baseSite(planet gamecomm.Planet) gamecomm.Coordinates {
+	return gamecomm.Coordinates{X: planet.Location.X + baseSiteDistance, Y: planet.Location.Y}
+}
+
+// CreateBaseConstructionMission pays for a new base and sends the squads to deliver the materials to the site
+// near the planet. The base is founded once the construction time passes after the delivery.
+func (ms *MissionScheduler) CreateBaseConstructionMission(m Mission) error {
+
+	if m.BaseName == "" {
+		return fmt.Errorf("error: base should have a name")
+	}
+
+	f, err := getFleet(m.CorporationId, m.Squads, ms.GameChannels)
+	if err != nil {
+		return err
+	}
+
+	planet, err := getPlanet(m.PlanetId, ms.GameChannels)
+	if err != nil {
+		return err
+	}
+
+	site := baseSite(planet)
+
+	r, err := f.planRoute(site)
+	if err != nil {
+		return err
+	}
+
+	cost, err := getBaseConstructionCost(m.CorporationId, ms.GameChannels)
+	if err != nil {
+		return err
+	}
+
+	materials := 0
+	for _, amount := range cost.Materials {
+		materials += amount
+	}
+
+	if materials > sum(f.cargoCapacity()) {
+		return fmt.Errorf("error: not enough cargo space on squads %v", m.Squads)
+	}
+
+	cost, err = startBaseConstruction(m.CorporationId, ms.GameChannels)
+	if err != nil {
+		return err
+	}
+
+	err = ms.scheduleBaseConstruction(m, r, site, cost)
+	if err != nil {
+		refundErr := cancelBaseConstruction(m.CorporationId, ms.GameChannels)
+		if refundErr != nil {
+			return refundErr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// scheduleBaseConstruction schedules the events of a paid base construction, nothing is left scheduled if it
+// fails
+func (ms *MissionScheduler) scheduleBaseConstruction(m Mission, r route, site gamecomm.Coordinates, cost gamecomm.ConstructionCost) error {
+	rvId, err := ms.scheduleRendezvous(m, r)
+	if err != nil {
+		return err
+	}
+
+	rv := &Event{Id: rvId}
+
+	le := &Event{
+		MissionId: m.Id,
+		Time:      ms.GameClock.GetCurrentTime().Add(r.rendezvousTime),
+		Cancelled: false,
+		Execute:   bcLeavingEvent(cost.Materials),
+	}
+
+	_, err = ms.EventScheduler.Schedule(le)
+	if err != nil {
+		updateErr := ms.cancelEvents(rv)
+		if updateErr != nil {
+			return updateErr
+		}
+
+		return err
+	}
+
+	ae := &Event{
+		MissionId: m.Id,
+		Time:      le.Time.Add(r.travelTime),
+		Cancelled: false,
+		Execute:   bcArrivalEvent(cost.Materials),
+	}
+
+	_, err = ms.EventScheduler.Schedule(ae)
+	if err != nil {
+		updateErr := ms.cancelEvents(le, rv)
+		if updateErr != nil {
+			return updateErr
+		}
+
+		return err
+	}
+
+	ce := &Event{
+		MissionId: m.Id,
+		Time:      ae.Time.Add(cost.BuildTime),
+		Cancelled: false,
+		Execute:   bcCompletionEvent(site),
+	}
+
+	_, err = ms.EventScheduler.Schedule(ce)
+	if err != nil {
+		updateErr := ms.cancelEvents(ae, le, rv)
+		if updateErr != nil {
+			return updateErr
+		}
+
+		return err
+	}
+
+	bb := &Event{
+		MissionId: m.Id,
+		Time:      ae.Time.Add(r.travelTime),
+		Cancelled: false,
+		Execute:   bcBackToBase,
+	}
+
+	_, err = ms.EventScheduler.Schedule(bb)
+	if err != nil {
+		updateErr := ms.cancelEvents(ce, ae, le, rv)
+		if updateErr != nil {
+			return updateErr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// CreateFacilityConstructionMission pays for the facility and schedules the end of its construction
+func (ms *MissionScheduler) CreateFacilityConstructionMission(m Mission) error {
+
+	cost, err := startFacilityConstruction(m.CorporationId, m.BaseIndex, m.Facility, ms.GameChannels)
+	if err != nil {
+		return err
+	}
+
+	se := &Event{
+		MissionId: m.Id,
+		Time:      ms.GameClock.GetCurrentTime(),
+		Cancelled: false,
+		Execute:   fcStartedEvent,
+	}
+
+	_, err = ms.EventScheduler.Schedule(se)
+	if err != nil {
+		refundErr := cancelFacilityConstruction(m.CorporationId, m.BaseIndex, m.Facility, ms.GameChannels)
+		if refundErr != nil {
+			return refundErr
+		}
+
+		return err
+	}
+
+	ce := &Event{
+		MissionId: m.Id,
+		Time:      se.Time.Add(cost.BuildTime),
+		Cancelled: false,
+		Execute:   fcCompletionEvent,
+	}
+
+	_, err = ms.EventScheduler.Schedule(ce)
+	if err != nil {
+		updateErr := ms.cancelEvents(se)
+		if updateErr != nil {
+			return updateErr
+		}
+
+		refundErr := cancelFacilityConstruction(m.CorporationId, m.BaseIndex, m.Facility, ms.GameChannels)
+		if refundErr != nil {
+			return refundErr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// bcLeavingEvent loads the construction materials on the squads
+func bcLeavingEvent(materials map[string]int) func(*Mission, *gamecomm.GameChannels) {
+	return func(mission *Mission, gameChannels *gamecomm.GameChannels) {
+		f, err := getFleet(mission.CorporationId, mission.Squads, gameChannels)
+		if err != nil {
+			mission.ErrorChan <- err
+			return
+		}
+
+		capacity := f.cargoCapacity()
+		for resource, amount := range materials {
+			shares := distributeCargo(capacity, amount)
+			for i, squadIndex := range mission.Squads {
+				if shares[i] == 0 {
+					continue
+				}
+
+				err = addResourcesToSquad(mission.CorporationId, squadIndex, shares[i], resource, gamecomm.BaseConstructionEscrow(mission.CorporationId), gameChannels)
+				if err != nil {
+					mission.ErrorChan <- err
+				}
+			}
+		}
+
+		mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v, started travel to the construction site.", mission.Squads)
+	}
+}
+
+// bcArrivalEvent unloads the materials on the construction site
+func bcArrivalEvent(materials map[string]int) func(*Mission, *gamecomm.GameChannels) {
+	return func(mission *Mission, gameChannels *gamecomm.GameChannels) {
+		for resource := range materials {
+			for _, squadIndex := range mission.Squads {
+				_, err := removeAllResourcesFromSquad(mission.CorporationId, squadIndex, resource, constructionAccount, gameChannels)
+				if err != nil {
+					mission.ErrorChan <- err
+				}
+			}
+		}
+
+		mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v, delivered the materials. Construction of base %v started.", mission.Squads, mission.BaseName)
+	}
+}
+
+func bcCompletionEvent(site gamecomm.Coordinates) func(*Mission, *gamecomm.GameChannels) {
+	return func(mission *Mission, gameChannels *gamecomm.GameChannels) {
+		base, err := foundBase(mission.CorporationId, mission.BaseName, site, gameChannels)
+		if err != nil {
+			mission.ErrorChan <- err
+			return
+		}
+
+		mission.NotificationChan <- fmt.Sprintf("Mission Notification: Base %v founded at (%.2f, %.2f).", base.Name, base.Location.X, base.Location.Y)
+	}
+}
+
+func bcBackToBase(mission *Mission, gameChannels *gamecomm.GameChannels) {
+	mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v is back to base", mission.Squads)
+}
+
+func fcStartedEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
+	mission.NotificationChan <- fmt.Sprintf("Mission Notification: Base %v, construction of %v started.", mission.BaseIndex, mission.Facility)
+}
+
+func fcCompletionEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
+	base, err := completeFacilityConstruction(mission.CorporationId, mission.BaseIndex, mission.Facility, gameChannels)
+	if err != nil {
+		mission.ErrorChan <- err
+		return
+	}
+
+	mission.NotificationChan <- fmt.Sprintf("Mission Notification: Base %v, construction of %v completed.", base.Name, mission.Facility)
+}
@@ -0,0 +1,192 @@
+package mission
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// failingScheduler refuses every event
+type failingScheduler struct{}
+
+func (failingScheduler) Schedule(*Event) (string, error) {
+	return "", fmt.Errorf("error: test error")
+}
+
+func (failingScheduler) UpdateEvent(string, gameclock.GameTime, bool) error {
+	return nil
+}
+
+func (failingScheduler) Run() {}
+
+// recordingScheduler keeps the scheduled events so the tests can run them
+type recordingScheduler struct {
+	events []*Event
+}
+
+func (rs *recordingScheduler) Schedule(e *Event) (string, error) {
+	rs.events = append(rs.events, e)
+	e.Id = fmt.Sprint(len(rs.events))
+
+	return e.Id, nil
+}
+
+func (rs *recordingScheduler) UpdateEvent(string, gameclock.GameTime, bool) error {
+	return nil
+}
+
+func (rs *recordingScheduler) Run() {}
+
+func TestBaseCompletionEvent(t *testing.T) {
+	tests := []struct {
+		name              string
+		mission           Mission
+		foundBaseResponse gamecomm.ChanResponse
+		wants             string
+	}{
+		{
+			name: "Base Founded",
+			mission: Mission{
+				CorporationId: 1,
+				BaseName:      "Outpost",
+			},
+			foundBaseResponse: gamecomm.ChanResponse{Val: gamecomm.Base{Name: "Outpost", Location: gamecomm.Coordinates{X: 2, Y: 1}}},
+			wants:             "Mission Notification: Base Outpost founded at (2.00, 1.00).",
+		},
+		{
+			name: "Found Base Error",
+			mission: Mission{
+				CorporationId: 1,
+				BaseName:      "Outpost",
+			},
+			foundBaseResponse: gamecomm.ChanResponse{Err: fmt.Errorf("error: test error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand),
+			}
+
+			notificationChannel := make(chan string)
+			tt.mission.NotificationChan = notificationChannel
+
+			errorChannel := make(chan error)
+			tt.mission.ErrorChan = errorChannel
+
+			site := gamecomm.Coordinates{X: 2, Y: 1}
+			go bcCompletionEvent(site)(&tt.mission, gameChannels)
+
+			foundBaseCommand := <-gameChannels.CorpChannel
+			assert.Equal(t, foundBaseCommand.Action, gamecomm.FoundBase)
+			assert.Equal(t, foundBaseCommand.Name, tt.mission.BaseName)
+			assert.Equal(t, foundBaseCommand.Location, site)
+			foundBaseCommand.ResponseChannel <- tt.foundBaseResponse
+
+			if tt.foundBaseResponse.Err != nil {
+				waitForErrorOrTimeout(t, errorChannel, tt.foundBaseResponse.Err)
+				return
+			}
+
+			msg := <-notificationChannel
+			assert.Equal(t, msg, tt.wants)
+		})
+	}
+}
+
+func TestFacilityCompletionEvent(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand),
+	}
+
+	notificationChannel := make(chan string)
+	mission := Mission{
+		CorporationId:    1,
+		BaseIndex:        0,
+		Facility:         "refinery",
+		NotificationChan: notificationChannel,
+	}
+
+	go fcCompletionEvent(&mission, gameChannels)
+
+	completeCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, completeCommand.Action, gamecomm.CompleteFacilityConstruction)
+	assert.Equal(t, completeCommand.BaseIndex, 0)
+	assert.Equal(t, completeCommand.Facility, "refinery")
+	completeCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Base{Name: "Test Base"}}
+
+	msg := <-notificationChannel
+	assert.Equal(t, msg, "Mission Notification: Base Test Base, construction of refinery completed.")
+}
+
+func TestBaseSite(t *testing.T) {
+	planet := gamecomm.Planet{Location: gamecomm.Coordinates{X: 4, Y: 3}}
+
+	assert.Equal(t, baseSite(planet), gamecomm.Coordinates{X: 4 + baseSiteDistance, Y: 3})
+}
+
+func TestFacilityConstructionScheduleError(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand),
+	}
+
+	ms := &MissionScheduler{
+		EventScheduler: failingScheduler{},
+		GameClock:      gameclock.NewGameClock(0, 1),
+		GameChannels:   gameChannels,
+	}
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- ms.CreateFacilityConstructionMission(Mission{CorporationId: 1, BaseIndex: 0, Facility: "refinery"})
+	}()
+
+	startCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, startCommand.Action, gamecomm.StartFacilityConstruction)
+	startCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.ConstructionCost{BuildTime: gameclock.Day}}
+
+	cancelCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, cancelCommand.Action, gamecomm.CancelFacilityConstruction)
+	assert.Equal(t, cancelCommand.Facility, "refinery")
+	cancelCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.ConstructionCost{}}
+
+	assert.Error(t, <-errChan)
+}
+
+func TestFacilityConstructionStartedEvent(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand),
+	}
+
+	scheduler := &recordingScheduler{}
+	ms := &MissionScheduler{
+		EventScheduler: scheduler,
+		GameClock:      gameclock.NewGameClock(0, 1),
+		GameChannels:   gameChannels,
+	}
+
+	// Nobody reads the notifications while the mission is created
+	notificationChannel := make(chan string)
+	errChan := make(chan error)
+	go func() {
+		errChan <- ms.CreateFacilityConstructionMission(Mission{CorporationId: 1, BaseIndex: 0, Facility: "refinery", NotificationChan: notificationChannel})
+	}()
+
+	startCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, startCommand.Action, gamecomm.StartFacilityConstruction)
+	startCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.ConstructionCost{BuildTime: gameclock.Day}}
+
+	assert.NilError(t, <-errChan)
+	assert.Equal(t, len(scheduler.events), 2)
+	assert.Equal(t, scheduler.events[0].Time, ms.GameClock.GetCurrentTime())
+
+	mission := Mission{BaseIndex: 0, Facility: "refinery", NotificationChan: notificationChannel}
+	go scheduler.events[0].Execute(&mission, gameChannels)
+
+	msg := <-notificationChannel
+	assert.Equal(t, msg, "Mission Notification: Base 0, construction of refinery started.")
+}
@@ -0,0 +1,176 @@
+package mission
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// fleet groups the squads of a mission so they travel, harvest and fight together
+type fleet struct {
+	squadIndexes []int
+	squads       []gamecomm.Squad
+}
+
+// route is the path the fleet follows: squads meet at the rendezvous point and travel together to the planet
+type route struct {
+	rendezvous     gamecomm.Coordinates
+	rendezvousTime gameclock.GameTimeDuration
+	travelTime     gameclock.GameTimeDuration
+	distance       float64
+}
+
+func getFleet(corporationId uint64, squadIndexes []int, gameChannels *gamecomm.GameChannels) (fleet, error) {
+	if len(squadIndexes) == 0 {
+		return fleet{}, fmt.Errorf("error: should include squads")
+	}
+
+	f := fleet{
+		squadIndexes: squadIndexes,
+		squads:       make([]gamecomm.Squad, 0, len(squadIndexes)),
+	}
+
+	for _, squadIndex := range squadIndexes {
+		squad, err := getSquad(corporationId, squadIndex, gameChannels)
+		if err != nil {
+			return fleet{}, err
+		}
+
+		if squad.Ships == (gamecomm.Ship{}) {
+			return fleet{}, fmt.Errorf("error: squad %v has no ship", squadIndex)
+		}
+
+		f.squads = append(f.squads, squad)
+	}
+
+	return f, nil
+}
+
+// speed returns the speed of the slowest squad, the fleet can't travel faster than it
+func (f fleet) speed() float64 {
+	speed := math.Inf(1)
+	for _, s := range f.squads {
+		speed = min(speed, float64(s.Ships.Speed)*(1+s.Bonuses.TravelSpeed))
+	}
+
+	return speed
+}
+
+// rendezvousPoint returns the center of the squads positions
+func (f fleet) rendezvousPoint() gamecomm.Coordinates {
+	point := gamecomm.Coordinates{}
+	for _, s := range f.squads {
+		point.X += s.Location.X
+		point.Y += s.Location.Y
+	}
+
+	point.X /= float64(len(f.squads))
+	point.Y /= float64(len(f.squads))
+
+	return point
+}
+
+// rendezvousDistance returns the distance the farthest squad has to travel to reach the rendezvous point
+func (f fleet) rendezvousDistance(rendezvous gamecomm.Coordinates) float64 {
+	distance := 0.0
+	for _, s := range f.squads {
+		distance = max(distance, gamecomm.Distance(s.Location, rendezvous))
+	}
+
+	return distance
+}
+
+// cargoCapacity returns the free cargo space of each squad
+func (f fleet) cargoCapacity() []int {
+	capacity := make([]int, len(f.squads))
+	for i, s := range f.squads {
+		used := 0
+		for _, amount := range s.Cargo {
+			used += amount
+		}
+
+		capacity[i] = max(s.Ships.MaxCargo-used, 0)
+	}
+
+	return capacity
+}
+
+// skillModifier returns the average skill modifier of the fleet squads
+func (f fleet) skillModifier(skill string) float64 {
+	total := 0.0
+	for _, s := range f.squads {
+		total += s.SkillModifier(skill)
+	}
+
+	return total / float64(len(f.squads))
+}
+
+// harvestYield returns the average officers harvest bonus of the fleet squads
+func (f fleet) harvestYield() float64 {
+	total := 0.0
+	for _, s := range f.squads {
+		total += s.Bonuses.HarvestYield
+	}
+
+	return total / float64(len(f.squads))
+}
+
+// sharedDangerLevel splits the danger of the destination between the fleet squads. Every squad fights
+// together so each one faces a smaller part of the danger, and squads with combat experience take a bigger
+// part of it.
+func (f fleet) sharedDangerLevel(dangerLevel int) int {
+	strength := 0.0
+	for _, s := range f.squads {
+		strength += s.SkillModifier(gamecomm.CombatSkill)
+	}
+
+	return int(float64(dangerLevel) / strength)
+}
+
+// distributeCargo splits the amount between the squads depending on their free cargo space and takes the
+// space used out of capacity. Returns the amount loaded on each squad.
+func distributeCargo(capacity []int, amount int) []int {
+	shares := make([]int, len(capacity))
+
+	for i, c := range capacity {
+		shares[i] = min(c, amount)
+		capacity[i] -= shares[i]
+		amount -= shares[i]
+	}
+
+	return shares
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+
+	return total
+}
+
+func (f fleet) planRoute(destination gamecomm.Coordinates) (route, error) {
+	speed := f.speed()
+	if speed <= 0 {
+		return route{}, fmt.Errorf("error: fleet squads can't travel")
+	}
+
+	rendezvous := f.rendezvousPoint()
+	rendezvousDistance := f.rendezvousDistance(rendezvous)
+	destinationDistance := gamecomm.Distance(rendezvous, destination)
+
+	return route{
+		rendezvous:     rendezvous,
+		rendezvousTime: travelTime(rendezvousDistance, speed),
+		travelTime:     travelTime(destinationDistance, speed),
+		distance:       rendezvousDistance + destinationDistance,
+	}, nil
+}
+
+// travelTime returns the game hours needed to travel the distance
+func travelTime(distance float64, speed float64) gameclock.GameTimeDuration {
+	return gameclock.GameTimeDuration(math.Ceil(distance / speed))
+}
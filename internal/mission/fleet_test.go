@@ -0,0 +1,154 @@
+package mission
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestPlanRoute(t *testing.T) {
+	type testResult struct {
+		rendezvous     gamecomm.Coordinates
+		rendezvousTime gameclock.GameTimeDuration
+		travelTime     gameclock.GameTimeDuration
+		shouldError    bool
+	}
+
+	tests := []struct {
+		name        string
+		squads      []gamecomm.Squad
+		destination gamecomm.Coordinates
+		wants       testResult
+	}{
+		{
+			name: "Single Squad",
+			squads: []gamecomm.Squad{
+				{Ships: gamecomm.Ship{Speed: 10}},
+			},
+			destination: gamecomm.Coordinates{X: 100, Y: 0},
+			wants: testResult{
+				rendezvous:     gamecomm.Coordinates{X: 0, Y: 0},
+				rendezvousTime: 0,
+				travelTime:     10,
+			},
+		},
+		{
+			name: "Travel At Slowest Squad Speed",
+			squads: []gamecomm.Squad{
+				{Ships: gamecomm.Ship{Speed: 20}},
+				{Ships: gamecomm.Ship{Speed: 5}},
+			},
+			destination: gamecomm.Coordinates{X: 100, Y: 0},
+			wants: testResult{
+				rendezvous:     gamecomm.Coordinates{X: 0, Y: 0},
+				rendezvousTime: 0,
+				travelTime:     20,
+			},
+		},
+		{
+			name: "Navigator Bonus",
+			squads: []gamecomm.Squad{
+				{Ships: gamecomm.Ship{Speed: 10}, Bonuses: gamecomm.SquadBonuses{TravelSpeed: 1}},
+			},
+			destination: gamecomm.Coordinates{X: 100, Y: 0},
+			wants: testResult{
+				rendezvous:     gamecomm.Coordinates{X: 0, Y: 0},
+				rendezvousTime: 0,
+				travelTime:     5,
+			},
+		},
+		{
+			name: "Rendezvous From Different Positions",
+			squads: []gamecomm.Squad{
+				{Ships: gamecomm.Ship{Speed: 10}, Location: gamecomm.Coordinates{X: 0, Y: 0}},
+				{Ships: gamecomm.Ship{Speed: 10}, Location: gamecomm.Coordinates{X: 100, Y: 0}},
+			},
+			destination: gamecomm.Coordinates{X: 50, Y: 100},
+			wants: testResult{
+				rendezvous:     gamecomm.Coordinates{X: 50, Y: 0},
+				rendezvousTime: 5,
+				travelTime:     10,
+			},
+		},
+		{
+			name: "Squad Without Speed",
+			squads: []gamecomm.Squad{
+				{Ships: gamecomm.Ship{Speed: 10}},
+				{Ships: gamecomm.Ship{Speed: 0}},
+			},
+			wants: testResult{
+				shouldError: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fleet{squads: tt.squads}
+
+			r, err := f.planRoute(tt.destination)
+			if tt.wants.shouldError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, r.rendezvous, tt.wants.rendezvous)
+			assert.Equal(t, r.rendezvousTime, tt.wants.rendezvousTime)
+			assert.Equal(t, r.travelTime, tt.wants.travelTime)
+		})
+	}
+}
+
+func TestDistributeCargo(t *testing.T) {
+	tests := []struct {
+		name              string
+		capacity          []int
+		amount            int
+		wants             []int
+		wantsFreeCapacity []int
+	}{
+		{
+			name:              "Fits On First Squad",
+			capacity:          []int{100, 100},
+			amount:            50,
+			wants:             []int{50, 0},
+			wantsFreeCapacity: []int{50, 100},
+		},
+		{
+			name:              "Pools Cargo Space",
+			capacity:          []int{100, 100},
+			amount:            150,
+			wants:             []int{100, 50},
+			wantsFreeCapacity: []int{0, 50},
+		},
+		{
+			name:              "Fleet Is Full",
+			capacity:          []int{100, 100},
+			amount:            300,
+			wants:             []int{100, 100},
+			wantsFreeCapacity: []int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := distributeCargo(tt.capacity, tt.amount)
+
+			for i := range tt.wants {
+				assert.Equal(t, shares[i], tt.wants[i])
+				assert.Equal(t, tt.capacity[i], tt.wantsFreeCapacity[i])
+			}
+		})
+	}
+}
+
+func TestSharedDangerLevel(t *testing.T) {
+	single := fleet{squads: []gamecomm.Squad{{}}}
+	assert.Equal(t, single.sharedDangerLevel(60), 60)
+
+	double := fleet{squads: []gamecomm.Squad{{}, {}}}
+	assert.Equal(t, double.sharedDangerLevel(60), 30)
+}
@@ -31,6 +31,11 @@ type Mission struct {
 	Type             gamecomm.MissionType
 	Resources        []string
 	Amount           int // TODO: You should have and object for transfers {resource, amount}
+	DangerLevel      int
+	BaseIndex        int
+	BaseName         string
+	Facility         string
+	Recipe           string
 	NotificationChan chan string
 	ErrorChan        chan error
 }
@@ -99,6 +104,10 @@ func CreateMission(mc gamecomm.MissionCommand, errorChan chan error) (Mission, e
 		Resources:        mc.Resources,
 		NotificationChan: mc.NotificationChan,
 		Amount:           mc.Amount,
+		BaseIndex:        mc.BaseIndex,
+		BaseName:         mc.BaseName,
+		Facility:         mc.Facility,
+		Recipe:           mc.Recipe,
 		ErrorChan:        errorChan,
 	}
 
@@ -123,6 +132,24 @@ func (ms *MissionScheduler) StartMission(m Mission) {
 			delete(ms.Missions, m.Id)
 			m.NotificationChan <- err.Error()
 		}
+	case gamecomm.BaseConstructionMission:
+		err := ms.CreateBaseConstructionMission(m)
+		if err != nil {
+			delete(ms.Missions, m.Id)
+			m.NotificationChan <- err.Error()
+		}
+	case gamecomm.FacilityConstructionMission:
+		err := ms.CreateFacilityConstructionMission(m)
+		if err != nil {
+			delete(ms.Missions, m.Id)
+			m.NotificationChan <- err.Error()
+		}
+	case gamecomm.ProductionMission:
+		err := ms.CreateProductionMission(m)
+		if err != nil {
+			delete(ms.Missions, m.Id)
+			m.NotificationChan <- err.Error()
+		}
 	default:
 		ms.RW.Lock()
 		delete(ms.Missions, m.Id)
@@ -130,42 +157,63 @@ func (ms *MissionScheduler) StartMission(m Mission) {
 	}
 }
 
+// CalculateTravelDistance returns the distance the mission fleet travels to reach the planet, including the
+// rendezvous of squads starting from different positions
 func (msz *MissionScheduler) CalculateTravelDistance(corporationId uint64, squads []int, planetId string, gameChannels *gamecomm.GameChannels) (float64, error) {
 
-	if len(squads) == 0 {
-		return 0.0, fmt.Errorf("error: should include squads")
+	f, err := getFleet(corporationId, squads, gameChannels)
+	if err != nil {
+		return 0.0, err
 	}
 
-	squadId := squads[0]
-	squad, err := getSquad(corporationId, squadId, gameChannels)
+	planet, err := getPlanet(planetId, gameChannels)
 	if err != nil {
 		return 0.0, err
 	}
 
-	// GET PLANET
-	planetResChan := make(chan gamecomm.ChanResponse)
-	planetCommand := gamecomm.WorldCommand{
-		PlanetId:        planetId,
-		Action:          gamecomm.GetPlanet,
-		ResponseChannel: planetResChan,
+	r, err := f.planRoute(planet.Location)
+	if err != nil {
+		return 0.0, err
 	}
-	gameChannels.WorldChannel <- planetCommand
 
-	planetRes := <-planetResChan
-	if planetRes.Err != nil {
-		return 0.0, planetRes.Err
+	return r.distance, nil
+}
+
+// scheduleRendezvous schedules the event where the fleet squads meet before travelling together. Returns an
+// empty id if the squads are already together.
+func (ms *MissionScheduler) scheduleRendezvous(m Mission, r route) (string, error) {
+	if r.rendezvousTime == 0 {
+		return "", nil
 	}
 
-	planet := planetRes.Val.(gamecomm.Planet)
-	close(planetResChan)
+	e := &Event{
+		MissionId: m.Id,
+		Time:      ms.GameClock.GetCurrentTime().Add(r.rendezvousTime),
+		Cancelled: false,
+		Execute:   rendezvousEvent(r.rendezvous),
+	}
+
+	return ms.EventScheduler.Schedule(e)
+}
+
+// cancelEvents cancels the already scheduled events of a mission that couldn't be fully scheduled
+func (ms *MissionScheduler) cancelEvents(events ...*Event) error {
+	for _, e := range events {
+		if e == nil || e.Id == "" {
+			continue
+		}
 
-	// CALCULATE SHIP SPEED
-	shipSpeed := squad.Ships.Speed
-	squadLocation := gamecomm.Coordinates{X: squad.Location.X, Y: squad.Location.Y}
-	planetLocation := gamecomm.Coordinates{X: planet.Location.X, Y: planet.Location.Y}
+		err := ms.EventScheduler.UpdateEvent(e.Id, e.Time, true)
+		if err != nil {
+			return err
+		}
+	}
 
-	planetDistance := gamecomm.Distance(squadLocation, planetLocation)
-	_ = planetDistance / float64(shipSpeed)
+	return nil
+}
 
-	return planetDistance, nil
+func rendezvousEvent(rendezvous gamecomm.Coordinates) func(*Mission, *gamecomm.GameChannels) {
+	return func(mission *Mission, gameChannels *gamecomm.GameChannels) {
+		mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squads %v, gathered at (%.2f, %.2f).", mission.Squads, rendezvous.X, rendezvous.Y)
+	}
 }
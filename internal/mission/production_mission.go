@@ -0,0 +1,71 @@
+package mission
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+// CreateProductionMission starts the recipe on the base and schedules the end of the job
+func (ms *MissionScheduler) CreateProductionMission(m Mission) error {
+
+	job, err := startProduction(m.CorporationId, m.BaseIndex, m.Recipe, m.Amount, ms.GameChannels)
+	if err != nil {
+		return err
+	}
+
+	se := &Event{
+		MissionId: m.Id,
+		Time:      ms.GameClock.GetCurrentTime(),
+		Cancelled: false,
+		Execute:   productionStartedEvent,
+	}
+
+	_, err = ms.EventScheduler.Schedule(se)
+	if err != nil {
+		refundErr := cancelProduction(m.CorporationId, m.BaseIndex, m.Recipe, m.Amount, ms.GameChannels)
+		if refundErr != nil {
+			return refundErr
+		}
+
+		return err
+	}
+
+	pe := &Event{
+		MissionId: m.Id,
+		Time:      se.Time.Add(job.Duration),
+		Cancelled: false,
+		Execute:   productionCompletionEvent,
+	}
+
+	_, err = ms.EventScheduler.Schedule(pe)
+	if err != nil {
+		updateErr := ms.cancelEvents(se)
+		if updateErr != nil {
+			return updateErr
+		}
+
+		refundErr := cancelProduction(m.CorporationId, m.BaseIndex, m.Recipe, m.Amount, ms.GameChannels)
+		if refundErr != nil {
+			return refundErr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func productionStartedEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
+	mission.NotificationChan <- fmt.Sprintf("Mission Notification: Base %v, started making %v x%v.", mission.BaseIndex, mission.Recipe, mission.Amount)
+}
+
+func productionCompletionEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
+	produced, err := completeProduction(mission.CorporationId, mission.BaseIndex, mission.Recipe, mission.Amount, gameChannels)
+	if err != nil {
+		mission.ErrorChan <- err
+		return
+	}
+
+	mission.NotificationChan <- fmt.Sprintf("Mission Notification: Base %v, finished making %v. Stored: %v", mission.BaseIndex, mission.Recipe, produced)
+}
@@ -0,0 +1,128 @@
+package mission
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestProductionCompletionEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		response gamecomm.ChanResponse
+		wants    string
+	}{
+		{
+			name:     "Production Finished",
+			response: gamecomm.ChanResponse{Val: map[string]int{"alloys": 200}},
+			wants:    "Mission Notification: Base 0, finished making alloys. Stored: map[alloys:200]",
+		},
+		{
+			name:     "Complete Production Error",
+			response: gamecomm.ChanResponse{Err: fmt.Errorf("error: test error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameChannels := &gamecomm.GameChannels{
+				CorpChannel: make(chan gamecomm.CorpCommand),
+			}
+
+			notificationChannel := make(chan string)
+			errorChannel := make(chan error)
+
+			mission := Mission{
+				CorporationId:    1,
+				BaseIndex:        0,
+				Recipe:           "alloys",
+				Amount:           2,
+				NotificationChan: notificationChannel,
+				ErrorChan:        errorChannel,
+			}
+
+			go productionCompletionEvent(&mission, gameChannels)
+
+			completeCommand := <-gameChannels.CorpChannel
+			assert.Equal(t, completeCommand.Action, gamecomm.CompleteProduction)
+			assert.Equal(t, completeCommand.Recipe, "alloys")
+			assert.Equal(t, completeCommand.Amount, 2)
+			completeCommand.ResponseChannel <- tt.response
+
+			if tt.response.Err != nil {
+				waitForErrorOrTimeout(t, errorChannel, tt.response.Err)
+				return
+			}
+
+			msg := <-notificationChannel
+			assert.Equal(t, msg, tt.wants)
+		})
+	}
+}
+
+func TestProductionScheduleError(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand),
+	}
+
+	ms := &MissionScheduler{
+		EventScheduler: failingScheduler{},
+		GameClock:      gameclock.NewGameClock(0, 1),
+		GameChannels:   gameChannels,
+	}
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- ms.CreateProductionMission(Mission{CorporationId: 1, BaseIndex: 0, Recipe: "alloys", Amount: 2})
+	}()
+
+	startCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, startCommand.Action, gamecomm.StartProduction)
+	startCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.ProductionJob{Recipe: "alloys", Batches: 2, Duration: 48}}
+
+	cancelCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, cancelCommand.Action, gamecomm.CancelProduction)
+	assert.Equal(t, cancelCommand.Recipe, "alloys")
+	assert.Equal(t, cancelCommand.Amount, 2)
+	cancelCommand.ResponseChannel <- gamecomm.ChanResponse{Val: map[string]int{}}
+
+	assert.Error(t, <-errChan)
+}
+
+func TestProductionStartedEvent(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand),
+	}
+
+	scheduler := &recordingScheduler{}
+	ms := &MissionScheduler{
+		EventScheduler: scheduler,
+		GameClock:      gameclock.NewGameClock(0, 1),
+		GameChannels:   gameChannels,
+	}
+
+	// Nobody reads the notifications while the mission is created
+	notificationChannel := make(chan string)
+	errChan := make(chan error)
+	go func() {
+		errChan <- ms.CreateProductionMission(Mission{CorporationId: 1, BaseIndex: 0, Recipe: "alloys", Amount: 2, NotificationChan: notificationChannel})
+	}()
+
+	startCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, startCommand.Action, gamecomm.StartProduction)
+	startCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.ProductionJob{Recipe: "alloys", Batches: 2, Duration: 48}}
+
+	assert.NilError(t, <-errChan)
+	assert.Equal(t, len(scheduler.events), 2)
+	assert.Equal(t, scheduler.events[0].Time, ms.GameClock.GetCurrentTime())
+	assert.Equal(t, scheduler.events[1].Time, ms.GameClock.GetCurrentTime().Add(48))
+
+	mission := Mission{BaseIndex: 0, Recipe: "alloys", Amount: 2, NotificationChan: notificationChannel}
+	go scheduler.events[0].Execute(&mission, gameChannels)
+
+	msg := <-notificationChannel
+	assert.Equal(t, msg, "Mission Notification: Base 0, started making alloys x2.")
+}
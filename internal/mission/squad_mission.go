@@ -7,72 +7,74 @@ import (
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
 )
 
+const harvestingExperience = 50
+
 // TODO: let mission scheduler that a mission is completed so it can erase it
 func (ms *MissionScheduler) CreateSquadMission(m Mission) error {
 	// TODO: Random events could affect mission times
 
-	// TODO: What happen if squads are on different positions?
-
-	if len(m.Squads) == 0 {
-		return fmt.Errorf("error: should include squads")
-	}
-
-	// GET SQUAD
-	squadId := m.Squads[0]
-	squad, err := getSquad(m.CorporationId, squadId, ms.GameChannels)
+	// GET FLEET
+	f, err := getFleet(m.CorporationId, m.Squads, ms.GameChannels)
 	if err != nil {
 		return err
 	}
 
 	// GET PLANET
-	planetResChan := make(chan gamecomm.ChanResponse)
-	planetCommand := gamecomm.WorldCommand{
-		PlanetId:        m.PlanetId,
-		Action:          gamecomm.GetPlanet,
-		ResponseChannel: planetResChan,
+	planet, err := getPlanet(m.PlanetId, ms.GameChannels)
+	if err != nil {
+		return err
 	}
-	ms.GameChannels.WorldChannel <- planetCommand
 
-	planetRes := <-planetResChan
-	if planetRes.Err != nil {
-		return planetRes.Err
+	ms.RW.Lock()
+	if mission, ok := ms.Missions[m.Id]; ok {
+		mission.DangerLevel = f.sharedDangerLevel(planet.DangerLevel)
 	}
+	ms.RW.Unlock()
 
-	planet := planetRes.Val.(gamecomm.Planet)
-	close(planetResChan)
+	// CALCULATE ROUTE
+	r, err := f.planRoute(planet.Location)
+	if err != nil {
+		return err
+	}
 
-	// CALCULATE SHIP SPEED
-	shipSpeed := squad.Ships.Speed
-	squadLocation := gamecomm.Coordinates{X: squad.Location.X, Y: squad.Location.Y}
-	planetLocation := gamecomm.Coordinates{X: planet.Location.X, Y: planet.Location.Y}
+	// CREATE RENDEZVOUS EVENT
+	rvId, err := ms.scheduleRendezvous(m, r)
+	if err != nil {
+		return err
+	}
 
-	planetDistance := gamecomm.Distance(squadLocation, planetLocation)
-	_ = planetDistance / float64(shipSpeed)
+	rv := &Event{Id: rvId}
+	arrivalTime := ms.GameClock.GetCurrentTime().Add(r.rendezvousTime + r.travelTime)
 
 	// CREATE ARRIVE EVENT
-	ae := Event{
+	ae := &Event{
 		MissionId: m.Id,
-		Time:      ms.GameClock.GetCurrentTime(),
+		Time:      arrivalTime,
 		Cancelled: false,
 		Execute:   arrivingEvent,
 	}
 
-	aeId, err := ms.EventScheduler.Schedule(&ae)
+	_, err = ms.EventScheduler.Schedule(ae)
 	if err != nil {
+		updateErr := ms.cancelEvents(rv)
+		if updateErr != nil {
+			return updateErr
+		}
+
 		return err
 	}
 
 	// CREATE HARVESTING RESOURCES EVENT
-	he := Event{
+	he := &Event{
 		MissionId: m.Id,
-		Time:      ms.GameClock.GetCurrentTime().Add(2 * gameclock.Day),
+		Time:      arrivalTime.Add(2 * gameclock.Day),
 		Cancelled: false,
 		Execute:   harvestingEvent,
 	}
 
-	heId, err := ms.EventScheduler.Schedule(&he)
+	_, err = ms.EventScheduler.Schedule(he)
 	if err != nil {
-		updateErr := ms.EventScheduler.UpdateEvent(aeId, ae.Time, true)
+		updateErr := ms.cancelEvents(ae, rv)
 		if updateErr != nil {
 			return updateErr
 		}
@@ -81,21 +83,16 @@ func (ms *MissionScheduler) CreateSquadMission(m Mission) error {
 	}
 
 	// CREATE RETURN EVENT
-	re := Event{
+	re := &Event{
 		MissionId: m.Id,
-		Time:      ms.GameClock.GetCurrentTime().Add(3 * gameclock.Day),
+		Time:      he.Time.Add(gameclock.Day + r.travelTime),
 		Cancelled: false,
 		Execute:   returnEvent,
 	}
 
-	_, err = ms.EventScheduler.Schedule(&re)
+	_, err = ms.EventScheduler.Schedule(re)
 	if err != nil {
-		updateErr := ms.EventScheduler.UpdateEvent(heId, re.Time, true)
-		if updateErr != nil {
-			return updateErr
-		}
-
-		updateErr = ms.EventScheduler.UpdateEvent(aeId, ae.Time, true)
+		updateErr := ms.cancelEvents(he, ae, rv)
 		if updateErr != nil {
 			return updateErr
 		}
@@ -103,8 +100,6 @@ func (ms *MissionScheduler) CreateSquadMission(m Mission) error {
 		return err
 	}
 
-	// TODO: If error to schedule it should cancel previous events
-
 	return nil
 }
 
@@ -117,30 +112,40 @@ func arrivingEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 // - This would Gather the resources
 func harvestingEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 
-	// Get Squad
-	// squad, err := getSquad(mission.CorporationId, mission.Squads[0], gameChannels)
-	// if err != nil {
-	// 	fmt.Println(err.Error()) //TODO: Handle error
-	// }
+	// Get Fleet
+	f, err := getFleet(mission.CorporationId, mission.Squads, gameChannels)
+	if err != nil {
+		mission.ErrorChan <- err
+		return
+	}
 
-	for _, resource := range mission.Resources {
-		// Generate harvested resourcesAmount
+	skillModifier := f.skillModifier(gamecomm.HarvestingSkill) * (1 + f.harvestYield())
+	capacity := f.cargoCapacity()
 
-		// TODO: check this later
-		// bonus := squad.GetHarvestingBonus()
+	for _, resource := range mission.Resources {
+		// Generate harvested resourcesAmount, every squad of the fleet harvests until the fleet cargo is full
 		bonus := 2
-		resourceAmount := 100 * bonus
+		resourceAmount := int(float64(100*bonus*len(f.squads)) * skillModifier)
+		resourceAmount = min(resourceAmount, sum(capacity))
 
-		// Remove Resources from planet
-		err := removeResourceFromPlanet(mission.PlanetId, resourceAmount, resource, gameChannels)
+		// Remove Resources from planet, nothing is loaded when the planet can't give the amount
+		err := removeResourceFromPlanet(mission.PlanetId, resourceAmount, resource, mission.escrow(), gameChannels)
 		if err != nil {
 			mission.ErrorChan <- err
+			continue
 		}
 
-		// Add Resources to Squad
-		err = addResourcesToSquad(mission.CorporationId, resourceAmount, resource, gameChannels)
-		if err != nil {
-			mission.ErrorChan <- err
+		// Add Resources to Squads
+		shares := distributeCargo(capacity, resourceAmount)
+		for i, squadIndex := range f.squadIndexes {
+			if shares[i] == 0 {
+				continue
+			}
+
+			err = addResourcesToSquad(mission.CorporationId, squadIndex, shares[i], resource, mission.escrow(), gameChannels)
+			if err != nil {
+				mission.ErrorChan <- err
+			}
 		}
 	}
 
@@ -156,9 +161,14 @@ func returnEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 	for _, resource := range mission.Resources {
 		var amount int
 
-		removedAmount, err := removeAllResourcesFromSquad(mission.CorporationId, resource, gameChannels)
-		if err != nil {
-			mission.ErrorChan <- err
+		removedAmount := 0
+		for _, squadIndex := range mission.Squads {
+			squadAmount, err := removeAllResourcesFromSquad(mission.CorporationId, squadIndex, resource, mission.escrow(), gameChannels)
+			if err != nil {
+				mission.ErrorChan <- err
+			}
+
+			removedAmount += squadAmount
 		}
 
 		baseResChan := make(chan gamecomm.ChanResponse)
@@ -169,6 +179,7 @@ func returnEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 			Resource:        resource,
 			CorporationId:   mission.CorporationId,
 			BaseIndex:       0,
+			Counterparty:    mission.escrow(),
 		}
 
 		res := <-baseResChan
@@ -181,6 +192,16 @@ func returnEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 
 		mission.NotificationChan <- fmt.Sprintf("Mission Notification: Added to base %v -> #%v", resource, amount)
 	}
+
+	for _, squadIndex := range mission.Squads {
+		records, err := trainSquadCrew(mission, squadIndex, gamecomm.HarvestingSkill, harvestingExperience, gameChannels)
+		if err != nil {
+			mission.ErrorChan <- err
+			continue
+		}
+
+		notifyCrewRecords(mission, squadIndex, records)
+	}
 }
 
 // TODO: Change Commands channels
@@ -93,15 +93,14 @@ func TestHarvestingEvent(t *testing.T) {
 			},
 		},
 		{
-			name: "Remove Resources From Planet Error",
+			name: "Planet Can't Supply The Amount",
 			mission: Mission{
 				CorporationId: 1,
 				Squads:        []int{0},
 				PlanetId:      "Planet 1",
 				Resources:     []string{"iron"},
 			},
-			removeResourcesFromPlanetResponse: gamecomm.ChanResponse{Err: fmt.Errorf("error: test error")},
-			addResourcesToSquadResponse:       gamecomm.ChanResponse{Val: 1},
+			removeResourcesFromPlanetResponse: gamecomm.ChanResponse{Err: fmt.Errorf("error: not enough resources")},
 			wants: testResult{
 				removeResourceFromPlanetCommand: gamecomm.WorldCommand{
 					PlanetId: "Planet 1",
@@ -110,14 +109,7 @@ func TestHarvestingEvent(t *testing.T) {
 					Action:   gamecomm.RemoveResourcesFromPlanet,
 				},
 				removeResourceFromPlanetShouldError: true,
-				addResourceToSquad: gamecomm.CorpCommand{
-					CorporationId: 1,
-					Amount:        200,
-					Resource:      "iron",
-					Action:        gamecomm.AddResourcesToSquad,
-				},
-				addResourceToSquadShouldError: false,
-				notificationChanMsg:           "Mission Notification: Squad [0], finished harvesting.",
+				notificationChanMsg:                 "Mission Notification: Squad [0], finished harvesting.",
 			},
 		},
 		{
@@ -167,6 +159,11 @@ func TestHarvestingEvent(t *testing.T) {
 
 			go harvestingEvent(&tt.mission, gameChannels)
 
+			// should receive get squad
+			getSquadCommand := <-gameChannels.CorpChannel
+			assert.Equal(t, getSquadCommand.Action, gamecomm.GetSquad)
+			getSquadCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Squad{Ships: gamecomm.Ship{MaxCargo: 10_000}}}
+
 			// should receive remove from planet
 			removeResourcePlanetCommand := <-gameChannels.WorldChannel
 			assertWorldCommand(t, removeResourcePlanetCommand, tt.wants.removeResourceFromPlanetCommand)
@@ -174,19 +171,27 @@ func TestHarvestingEvent(t *testing.T) {
 
 			if tt.wants.removeResourceFromPlanetShouldError {
 				waitForErrorOrTimeout(t, errorChannel, tt.removeResourcesFromPlanetResponse.Err)
-			}
-
-			// should receive add resources to squad
-			addResourceToSquadCommand := <-gameChannels.CorpChannel
-			assertCorpCommand(t, addResourceToSquadCommand, tt.wants.addResourceToSquad)
-			addResourceToSquadCommand.ResponseChannel <- tt.addResourcesToSquadResponse
 
-			if tt.wants.addResourceToSquadShouldError {
-				waitForErrorOrTimeout(t, errorChannel, tt.addResourcesToSquadResponse.Err)
+				// nothing was taken from the planet so nothing is loaded on the squad
+				select {
+				case cmd := <-gameChannels.CorpChannel:
+					t.Fatalf("unexpected corporation command %v", cmd.Action)
+				case msg := <-notificationChannel:
+					assert.Equal(t, msg, tt.wants.notificationChanMsg)
+				}
+			} else {
+				// should receive add resources to squad
+				addResourceToSquadCommand := <-gameChannels.CorpChannel
+				assertCorpCommand(t, addResourceToSquadCommand, tt.wants.addResourceToSquad)
+				addResourceToSquadCommand.ResponseChannel <- tt.addResourcesToSquadResponse
+
+				if tt.wants.addResourceToSquadShouldError {
+					waitForErrorOrTimeout(t, errorChannel, tt.addResourcesToSquadResponse.Err)
+				}
+				// should receive mission notification
+				msg := <-notificationChannel
+				assert.Equal(t, msg, tt.wants.notificationChanMsg)
 			}
-			// should receive mission notification
-			msg := <-notificationChannel
-			assert.Equal(t, msg, tt.wants.notificationChanMsg)
 
 			close(gameChannels.WorldChannel)
 			close(gameChannels.CorpChannel)
@@ -344,6 +349,12 @@ func TestReturnEvent(t *testing.T) {
 			msg = <-notificationChannel
 			assert.Equal(t, msg, tt.wants.notificationChanMsg2)
 
+			// should receive trainSquadCrew
+			trainSquadCrewCommand := <-gameChannels.CorpChannel
+			assert.Equal(t, trainSquadCrewCommand.Action, gamecomm.TrainSquadCrew)
+			assert.Equal(t, trainSquadCrewCommand.Skill, gamecomm.HarvestingSkill)
+			trainSquadCrewCommand.ResponseChannel <- gamecomm.ChanResponse{Val: []gamecomm.CrewRecord{}}
+
 			close(gameChannels.WorldChannel)
 			close(gameChannels.CorpChannel)
 			close(gameChannels.MissionChannel)
@@ -386,3 +397,43 @@ func waitForErrorOrTimeout(t *testing.T, errorChannel chan error, resErr error)
 
 	}
 }
+
+func TestNotifyCrewRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []gamecomm.CrewRecord
+		wants   []string
+	}{
+		{
+			name: "Level Up, Injury and Death",
+			records: []gamecomm.CrewRecord{
+				{CrewId: 1, LevelUp: true, Status: gamecomm.CrewActive},
+				{CrewId: 2, Status: gamecomm.CrewActive},
+				{CrewId: 3, LevelUp: true, Status: gamecomm.CrewInjured},
+				{CrewId: 4, Status: gamecomm.CrewDead},
+			},
+			wants: []string{
+				"Mission Notification: Squad 0, crew member 1 leveled up.",
+				"Mission Notification: Squad 0, crew member 3 was injured.",
+				"Mission Notification: Squad 0, crew member 4 died.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationChannel := make(chan string)
+			mission := &Mission{
+				Squads:           []int{0},
+				NotificationChan: notificationChannel,
+			}
+
+			go notifyCrewRecords(mission, 0, tt.records)
+
+			for _, wants := range tt.wants {
+				msg := <-notificationChannel
+				assert.Equal(t, msg, wants)
+			}
+		})
+	}
+}
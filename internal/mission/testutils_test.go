@@ -188,7 +188,7 @@ func listenCorporationWorker(t *testing.T, errors corporationErrors, gamechannel
 					continue
 				}
 
-				squad := gamecomm.Squad{}
+				squad := gamecomm.Squad{Ships: gamecomm.Ship{Speed: 10, MaxCargo: 10_000}}
 
 				command.ResponseChannel <- gamecomm.ChanResponse{Val: squad}
 			case gamecomm.GetCorporation:
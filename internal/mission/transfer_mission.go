@@ -3,46 +3,74 @@ package mission
 import (
 	"fmt"
 
-	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
 )
 
 // TODO: Need to update squad positions for every event
 
+const tradingExperience = 30
+
 // amount int, itemName world.Resource, planetId string, corporationId uint64
 func (ms *MissionScheduler) CreateTransferMission(m Mission) error {
 
-	_, err := ms.CalculateTravelDistance(m.CorporationId, m.Squads, m.PlanetId, ms.GameChannels)
+	f, err := getFleet(m.CorporationId, m.Squads, ms.GameChannels)
+	if err != nil {
+		return err
+	}
+
+	planet, err := getPlanet(m.PlanetId, ms.GameChannels)
 	if err != nil {
 		return err
 	}
 
+	r, err := f.planRoute(planet.Location)
+	if err != nil {
+		return err
+	}
+
+	// The fleet pools the cargo space of all its squads
+	if m.Amount*len(m.Resources) > sum(f.cargoCapacity()) {
+		return fmt.Errorf("error: not enough cargo space on squads %v", m.Squads)
+	}
+
+	rvId, err := ms.scheduleRendezvous(m, r)
+	if err != nil {
+		return err
+	}
+
+	rv := &Event{Id: rvId}
+
 	// Leaving Event
 	// Rmove resources from corporation
 	// Add resources to squad
 
 	le := &Event{
 		MissionId: m.Id,
-		Time:      ms.GameClock.GetCurrentTime(),
+		Time:      ms.GameClock.GetCurrentTime().Add(r.rendezvousTime),
 		Cancelled: false,
 		Execute:   tsLeavingEvent,
 	}
 
-	leId, err := ms.EventScheduler.Schedule(le)
+	_, err = ms.EventScheduler.Schedule(le)
 	if err != nil {
+		updateErr := ms.cancelEvents(rv)
+		if updateErr != nil {
+			return updateErr
+		}
+
 		return err
 	}
 
 	ae := &Event{
 		MissionId: m.Id,
-		Time:      ms.GameClock.GetCurrentTime().Add(3 * gameclock.Day),
+		Time:      le.Time.Add(r.travelTime),
 		Cancelled: false,
 		Execute:   tsArrivalEvent,
 	}
 
-	aeId, err := ms.EventScheduler.Schedule(ae)
+	_, err = ms.EventScheduler.Schedule(ae)
 	if err != nil {
-		updateErr := ms.EventScheduler.UpdateEvent(leId, le.Time, true)
+		updateErr := ms.cancelEvents(le, rv)
 		if updateErr != nil {
 			return updateErr
 		}
@@ -55,22 +83,18 @@ func (ms *MissionScheduler) CreateTransferMission(m Mission) error {
 
 	bb := &Event{
 		MissionId: m.Id,
-		Time:      ms.GameClock.GetCurrentTime().Add(6 * gameclock.Day),
+		Time:      ae.Time.Add(r.travelTime),
 		Cancelled: false,
 		Execute:   tsBackToBase,
 	}
 
 	_, err = ms.EventScheduler.Schedule(bb)
 	if err != nil {
-		updateErr := ms.EventScheduler.UpdateEvent(aeId, ae.Time, true)
+		updateErr := ms.cancelEvents(ae, le, rv)
 		if updateErr != nil {
 			return updateErr
 		}
 
-		updateErr = ms.EventScheduler.UpdateEvent(leId, le.Time, true)
-		if updateErr != nil {
-			return updateErr
-		}
 		return err
 	}
 
@@ -80,19 +104,48 @@ func (ms *MissionScheduler) CreateTransferMission(m Mission) error {
 
 func tsLeavingEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 
+	f, err := getFleet(mission.CorporationId, mission.Squads, gameChannels)
+	if err != nil {
+		mission.ErrorChan <- err
+		return
+	}
+
+	capacity := f.cargoCapacity()
 	for _, resource := range mission.Resources {
 
-		removedAmount, err := removeResourcesFromCorporation(mission.CorporationId, mission.Amount, resource, gameChannels)
+		err := removeResourcesFromCorporation(mission.CorporationId, mission.Amount, resource, mission.escrow(), gameChannels)
 		if err != nil {
 			mission.ErrorChan <- err
-			removedAmount = mission.Amount
+			continue
 		}
 
-		err = addResourcesToSquad(mission.CorporationId, removedAmount, resource, gameChannels)
-		if err != nil {
-			mission.ErrorChan <- err
+		loaded := 0
+		shares := distributeCargo(capacity, mission.Amount)
+		for i, squadIndex := range mission.Squads {
+			if shares[i] == 0 {
+				continue
+			}
+
+			err = addResourcesToSquad(mission.CorporationId, squadIndex, shares[i], resource, mission.escrow(), gameChannels)
+			if err != nil {
+				mission.ErrorChan <- err
+				continue
+			}
+
+			loaded += shares[i]
 		}
 
+		// The squads may have less free space than when the mission was created, what they can't carry goes back
+		// to the base
+		if left := mission.Amount - loaded; left > 0 {
+			err = addResourcesToBase(mission.CorporationId, left, resource, mission.escrow(), gameChannels)
+			if err != nil {
+				mission.ErrorChan <- err
+				continue
+			}
+
+			mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v, left %v %v on the base.", mission.Squads, left, resource)
+		}
 	}
 
 	mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v, started travel.", mission.Squads)
@@ -102,20 +155,25 @@ func tsArrivalEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 
 	sumCredits := 0.0
 	for _, resource := range mission.Resources {
-		removedAmount, err := removeAllResourcesFromSquad(mission.CorporationId, resource, gameChannels)
-		if err != nil {
-			removedAmount = mission.Amount
-			mission.ErrorChan <- err
+		removedAmount := 0
+		for _, squadIndex := range mission.Squads {
+			squadAmount, err := removeAllResourcesFromSquad(mission.CorporationId, squadIndex, resource, mission.escrow(), gameChannels)
+			if err != nil {
+				squadAmount = mission.Amount / len(mission.Squads)
+				mission.ErrorChan <- err
+			}
+
+			removedAmount += squadAmount
 		}
 
-		err = addResourcesToPlanet(mission.PlanetId, removedAmount, resource, gameChannels)
+		err := addResourcesToPlanet(mission.PlanetId, removedAmount, resource, mission.escrow(), gameChannels)
 		if err != nil {
 			mission.ErrorChan <- err
 		}
 
 		// TODO fix prices, build a economy module that get the actual price. It would depend on various things (current contract between base and corporation, zone prices, base item price, sanctions)
 		credits := float64(removedAmount * 2)
-		err = addCreditsToCorporation(mission.CorporationId, credits, gameChannels)
+		err = addCreditsToCorporation(mission.CorporationId, credits, deliveriesAccount, gameChannels)
 		if err != nil {
 			mission.ErrorChan <- err
 			continue
@@ -130,4 +188,14 @@ func tsArrivalEvent(mission *Mission, gameChannels *gamecomm.GameChannels) {
 func tsBackToBase(mission *Mission, gameChannels *gamecomm.GameChannels) {
 	// TODO: In the future make the squad available again
 	mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v is back to base", mission.Squads[0])
+
+	for _, squadIndex := range mission.Squads {
+		records, err := trainSquadCrew(mission, squadIndex, gamecomm.TradingSkill, tradingExperience, gameChannels)
+		if err != nil {
+			mission.ErrorChan <- err
+			continue
+		}
+
+		notifyCrewRecords(mission, squadIndex, records)
+	}
 }
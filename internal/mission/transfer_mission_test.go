@@ -14,6 +14,8 @@ func TestLeavingEvent(t *testing.T) {
 		removeResourcesFromCorporationShouldError bool
 		addResourcesToSquadCommand                gamecomm.CorpCommand
 		addResourcesToSquadShouldError            bool
+		addResourcesToBaseCommand                 gamecomm.CorpCommand
+		returnedNotificationMsg                   string
 		notificationChanMsg                       string
 	}
 
@@ -61,7 +63,6 @@ func TestLeavingEvent(t *testing.T) {
 				Amount:        1,
 			},
 			removeResourceFromCorporationResponse: gamecomm.ChanResponse{Err: fmt.Errorf("error: test error")},
-			addResourcesToSquadResponse:           gamecomm.ChanResponse{Val: 1},
 			wants: testResult{
 				removeResourcesFromCorporationCommand: gamecomm.CorpCommand{
 					CorporationId: 1,
@@ -70,14 +71,7 @@ func TestLeavingEvent(t *testing.T) {
 					Action:        gamecomm.RemoveResourcesFromBase,
 				},
 				removeResourcesFromCorporationShouldError: true,
-				addResourcesToSquadCommand: gamecomm.CorpCommand{
-					CorporationId: 1,
-					Amount:        1,
-					Resource:      "iron",
-					Action:        gamecomm.AddResourcesToSquad,
-				},
-				addResourcesToSquadShouldError: false,
-				notificationChanMsg:            "Mission Notification: Squad [0], started travel.",
+				notificationChanMsg:                       "Mission Notification: Squad [0], started travel.",
 			},
 		},
 		{
@@ -105,7 +99,14 @@ func TestLeavingEvent(t *testing.T) {
 					Action:        gamecomm.AddResourcesToSquad,
 				},
 				addResourcesToSquadShouldError: true,
-				notificationChanMsg:            "Mission Notification: Squad [0], started travel.",
+				addResourcesToBaseCommand: gamecomm.CorpCommand{
+					CorporationId: 1,
+					Amount:        1,
+					Resource:      "iron",
+					Action:        gamecomm.AddResourcesToBase,
+				},
+				returnedNotificationMsg: "Mission Notification: Squad [0], left 1 iron on the base.",
+				notificationChanMsg:     "Mission Notification: Squad [0], started travel.",
 			},
 		},
 	}
@@ -126,6 +127,11 @@ func TestLeavingEvent(t *testing.T) {
 
 			go tsLeavingEvent(&tt.mission, gameChannels)
 
+			// should receive get squad
+			getSquadCommand := <-gameChannels.CorpChannel
+			assert.Equal(t, getSquadCommand.Action, gamecomm.GetSquad)
+			getSquadCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Squad{Ships: gamecomm.Ship{MaxCargo: 10_000}}}
+
 			// should receive remove from corporation
 			removeResourceFromCorporationCommand := <-gameChannels.CorpChannel
 			assertCorpCommand(t, removeResourceFromCorporationCommand, tt.wants.removeResourcesFromCorporationCommand)
@@ -133,15 +139,25 @@ func TestLeavingEvent(t *testing.T) {
 
 			if tt.wants.removeResourcesFromCorporationShouldError {
 				waitForErrorOrTimeout(t, errorChannel, tt.removeResourceFromCorporationResponse.Err)
+			} else {
+				// should receive add resources to squad
+				addResourcesToSquadCommand := <-gameChannels.CorpChannel
+				assertCorpCommand(t, addResourcesToSquadCommand, tt.wants.addResourcesToSquadCommand)
+				addResourcesToSquadCommand.ResponseChannel <- tt.addResourcesToSquadResponse
+
+				if tt.wants.addResourcesToSquadShouldError {
+					waitForErrorOrTimeout(t, errorChannel, tt.addResourcesToSquadResponse.Err)
+				}
 			}
 
-			// should receive add resources to squad
-			addResourcesToSquadCommand := <-gameChannels.CorpChannel
-			assertCorpCommand(t, addResourcesToSquadCommand, tt.wants.addResourcesToSquadCommand)
-			addResourcesToSquadCommand.ResponseChannel <- tt.addResourcesToSquadResponse
+			// what wasn't loaded should go back to the base
+			if tt.wants.returnedNotificationMsg != "" {
+				addResourcesToBaseCommand := <-gameChannels.CorpChannel
+				assertCorpCommand(t, addResourcesToBaseCommand, tt.wants.addResourcesToBaseCommand)
+				addResourcesToBaseCommand.ResponseChannel <- gamecomm.ChanResponse{Val: 1}
 
-			if tt.wants.addResourcesToSquadShouldError {
-				waitForErrorOrTimeout(t, errorChannel, tt.addResourcesToSquadResponse.Err)
+				msg := <-notificationChannel
+				assert.Equal(t, msg, tt.wants.returnedNotificationMsg)
 			}
 
 			// should receive mission notification
@@ -159,6 +175,93 @@ func TestLeavingEvent(t *testing.T) {
 
 }
 
+func TestLeavingEventMixedFleet(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand),
+	}
+
+	notificationChannel := make(chan string)
+	mission := Mission{
+		CorporationId:    1,
+		Squads:           []int{0, 1},
+		Resources:        []string{"iron"},
+		Amount:           30,
+		NotificationChan: notificationChannel,
+		ErrorChan:        make(chan error),
+	}
+
+	go tsLeavingEvent(&mission, gameChannels)
+
+	for _, maxCargo := range []int{5, 100} {
+		getSquadCommand := <-gameChannels.CorpChannel
+		assert.Equal(t, getSquadCommand.Action, gamecomm.GetSquad)
+		getSquadCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Squad{Ships: gamecomm.Ship{MaxCargo: maxCargo}}}
+	}
+
+	removeCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, removeCommand.Action, gamecomm.RemoveResourcesFromBase)
+	removeCommand.ResponseChannel <- gamecomm.ChanResponse{Val: 30}
+
+	for i, wants := range []int{5, 25} {
+		addCommand := <-gameChannels.CorpChannel
+		assert.Equal(t, addCommand.Action, gamecomm.AddResourcesToSquad)
+		assert.Equal(t, addCommand.SquadIndex, i)
+		assert.Equal(t, addCommand.Amount, wants)
+		addCommand.ResponseChannel <- gamecomm.ChanResponse{Val: wants}
+	}
+
+	msg := <-notificationChannel
+	assert.Equal(t, msg, "Mission Notification: Squad [0 1], started travel.")
+}
+
+func TestLeavingEventReturnsExcessToBase(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		CorpChannel: make(chan gamecomm.CorpCommand),
+	}
+
+	notificationChannel := make(chan string)
+	mission := Mission{
+		CorporationId:    1,
+		Squads:           []int{0},
+		Resources:        []string{"iron"},
+		Amount:           30,
+		NotificationChan: notificationChannel,
+		ErrorChan:        make(chan error),
+	}
+
+	go tsLeavingEvent(&mission, gameChannels)
+
+	// The squad filled up after the mission was created and only has room for 20
+	getSquadCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, getSquadCommand.Action, gamecomm.GetSquad)
+	getSquadCommand.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Squad{
+		Ships: gamecomm.Ship{MaxCargo: 100},
+		Cargo: map[string]int{"water": 80},
+	}}
+
+	removeCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, removeCommand.Action, gamecomm.RemoveResourcesFromBase)
+	assert.Equal(t, removeCommand.Amount, 30)
+	removeCommand.ResponseChannel <- gamecomm.ChanResponse{Val: 0}
+
+	addCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, addCommand.Action, gamecomm.AddResourcesToSquad)
+	assert.Equal(t, addCommand.Amount, 20)
+	addCommand.ResponseChannel <- gamecomm.ChanResponse{Val: 20}
+
+	returnCommand := <-gameChannels.CorpChannel
+	assert.Equal(t, returnCommand.Action, gamecomm.AddResourcesToBase)
+	assert.Equal(t, returnCommand.Resource, "iron")
+	assert.Equal(t, returnCommand.Amount, 10)
+	returnCommand.ResponseChannel <- gamecomm.ChanResponse{Val: 10}
+
+	msg := <-notificationChannel
+	assert.Equal(t, msg, "Mission Notification: Squad [0], left 10 iron on the base.")
+
+	msg = <-notificationChannel
+	assert.Equal(t, msg, "Mission Notification: Squad [0], started travel.")
+}
+
 func TestArrivalEvent(t *testing.T) {
 
 	type testResult struct {
@@ -411,6 +514,11 @@ func TestBackToBase(t *testing.T) {
 
 			assert.Equal(t, msg, tt.wants)
 
+			trainSquadCrewCommand := <-gameChannels.CorpChannel
+			assert.Equal(t, trainSquadCrewCommand.Action, gamecomm.TrainSquadCrew)
+			assert.Equal(t, trainSquadCrewCommand.Skill, gamecomm.TradingSkill)
+			trainSquadCrewCommand.ResponseChannel <- gamecomm.ChanResponse{Val: []gamecomm.CrewRecord{}}
+
 		})
 	}
 }
@@ -8,6 +8,18 @@ import (
 
 // TODO: Close channels on producers
 
+var (
+	// deliveriesAccount pays the credits of the resources delivered by transfer missions
+	deliveriesAccount = gamecomm.NewExternalAccount("deliveries")
+	// constructionAccount takes the materials unloaded on construction sites
+	constructionAccount = gamecomm.NewExternalAccount("construction")
+)
+
+// escrow is the ledger account of the goods the mission carries between planets, squads and bases
+func (m *Mission) escrow() gamecomm.Account {
+	return gamecomm.NewEscrowAccount("mission/" + m.Id)
+}
+
 func getSquad(corporationId uint64, squadId int, gameChannels *gamecomm.GameChannels) (gamecomm.Squad, error) {
 
 	squadResChan := make(chan gamecomm.ChanResponse)
@@ -35,7 +47,30 @@ func getSquad(corporationId uint64, squadId int, gameChannels *gamecomm.GameChan
 
 }
 
-func removeResourceFromPlanet(planetId string, resourceAmount int, resource string, gameChannels *gamecomm.GameChannels) error {
+func getPlanet(planetId string, gameChannels *gamecomm.GameChannels) (gamecomm.Planet, error) {
+	planetResChan := make(chan gamecomm.ChanResponse)
+	defer close(planetResChan)
+
+	gameChannels.WorldChannel <- gamecomm.WorldCommand{
+		PlanetId:        planetId,
+		Action:          gamecomm.GetPlanet,
+		ResponseChannel: planetResChan,
+	}
+
+	planetRes := <-planetResChan
+	if planetRes.Err != nil {
+		return gamecomm.Planet{}, planetRes.Err
+	}
+
+	planet, ok := planetRes.Val.(gamecomm.Planet)
+	if !ok {
+		return gamecomm.Planet{}, fmt.Errorf("world channel returned wrong planet object: %v", planetRes.Val)
+	}
+
+	return planet, nil
+}
+
+func removeResourceFromPlanet(planetId string, resourceAmount int, resource string, counterparty gamecomm.Account, gameChannels *gamecomm.GameChannels) error {
 	responseChan := make(chan gamecomm.ChanResponse)
 	defer close(responseChan)
 
@@ -45,6 +80,7 @@ func removeResourceFromPlanet(planetId string, resourceAmount int, resource stri
 		Amount:          resourceAmount,
 		ResponseChannel: responseChan,
 		Resource:        resource,
+		Counterparty:    counterparty,
 	}
 
 	responseChanRes := <-responseChan
@@ -57,7 +93,7 @@ func removeResourceFromPlanet(planetId string, resourceAmount int, resource stri
 	return nil
 }
 
-func addResourcesToSquad(corporationId uint64, resourceAmount int, resource string, gameChannels *gamecomm.GameChannels) error {
+func addResourcesToSquad(corporationId uint64, squadIndex int, resourceAmount int, resource string, counterparty gamecomm.Account, gameChannels *gamecomm.GameChannels) error {
 	squadResChan := make(chan gamecomm.ChanResponse)
 	defer close(squadResChan)
 
@@ -65,8 +101,10 @@ func addResourcesToSquad(corporationId uint64, resourceAmount int, resource stri
 		Action:          gamecomm.AddResourcesToSquad,
 		ResponseChannel: squadResChan,
 		CorporationId:   corporationId,
+		SquadIndex:      squadIndex,
 		Resource:        resource,
 		Amount:          resourceAmount,
+		Counterparty:    counterparty,
 	}
 
 	squadRes := <-squadResChan
@@ -97,13 +135,15 @@ func addResourcesToSquad(corporationId uint64, resourceAmount int, resource stri
 // 	return removedAmountRes.Val.(int), nil
 // }
 
-func removeAllResourcesFromSquad(corporationId uint64, resource string, gameChannels *gamecomm.GameChannels) (int, error) {
+func removeAllResourcesFromSquad(corporationId uint64, squadIndex int, resource string, counterparty gamecomm.Account, gameChannels *gamecomm.GameChannels) (int, error) {
 	removeResChan := make(chan gamecomm.ChanResponse)
 	gameChannels.CorpChannel <- gamecomm.CorpCommand{
 		Action:          gamecomm.RemoveAllResourcesFromSquad,
 		ResponseChannel: removeResChan,
 		CorporationId:   corporationId,
+		SquadIndex:      squadIndex,
 		Resource:        resource,
+		Counterparty:    counterparty,
 	}
 
 	removedAmountRes := <-removeResChan
@@ -115,7 +155,7 @@ func removeAllResourcesFromSquad(corporationId uint64, resource string, gameChan
 
 }
 
-func removeResourcesFromCorporation(corporationId uint64, amount int, resource string, gameChannels *gamecomm.GameChannels) (int, error) {
+func removeResourcesFromCorporation(corporationId uint64, amount int, resource string, counterparty gamecomm.Account, gameChannels *gamecomm.GameChannels) error {
 	removeResChan := make(chan gamecomm.ChanResponse)
 	gameChannels.CorpChannel <- gamecomm.CorpCommand{
 		Action:          gamecomm.RemoveResourcesFromBase,
@@ -123,17 +163,37 @@ func removeResourcesFromCorporation(corporationId uint64, amount int, resource s
 		CorporationId:   corporationId,
 		Resource:        resource,
 		Amount:          amount,
+		Counterparty:    counterparty,
 	}
 
 	removedAmountRes := <-removeResChan
 	if removedAmountRes.Err != nil {
-		return 0, removedAmountRes.Err
+		return removedAmountRes.Err
 	}
 
-	return removedAmountRes.Val.(int), nil
+	return nil
+}
+
+func addResourcesToBase(corporationId uint64, amount int, resource string, counterparty gamecomm.Account, gameChannels *gamecomm.GameChannels) error {
+	addResChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.AddResourcesToBase,
+		ResponseChannel: addResChan,
+		CorporationId:   corporationId,
+		Resource:        resource,
+		Amount:          amount,
+		Counterparty:    counterparty,
+	}
+
+	addRes := <-addResChan
+	if addRes.Err != nil {
+		return addRes.Err
+	}
+
+	return nil
 }
 
-func addResourcesToPlanet(planetId string, resourceAmount int, resource string, gameChannels *gamecomm.GameChannels) error {
+func addResourcesToPlanet(planetId string, resourceAmount int, resource string, counterparty gamecomm.Account, gameChannels *gamecomm.GameChannels) error {
 
 	addResChan := make(chan gamecomm.ChanResponse)
 	gameChannels.WorldChannel <- gamecomm.WorldCommand{
@@ -142,6 +202,7 @@ func addResourcesToPlanet(planetId string, resourceAmount int, resource string,
 		PlanetId:        planetId,
 		Resource:        resource,
 		Amount:          resourceAmount,
+		Counterparty:    counterparty,
 	}
 
 	addRes := <-addResChan
@@ -152,13 +213,14 @@ func addResourcesToPlanet(planetId string, resourceAmount int, resource string,
 	return nil
 }
 
-func addCreditsToCorporation(corporationId uint64, amount float64, gameChannels *gamecomm.GameChannels) error {
+func addCreditsToCorporation(corporationId uint64, amount float64, counterparty gamecomm.Account, gameChannels *gamecomm.GameChannels) error {
 	resChan := make(chan gamecomm.ChanResponse)
 	gameChannels.CorpChannel <- gamecomm.CorpCommand{
 		Action:          gamecomm.AddCredits,
 		ResponseChannel: resChan,
 		CorporationId:   corporationId,
 		AmountDecimal:   amount,
+		Counterparty:    counterparty,
 	}
 
 	res := <-resChan
@@ -169,6 +231,206 @@ func addCreditsToCorporation(corporationId uint64, amount float64, gameChannels
 	return nil
 }
 
+func trainSquadCrew(mission *Mission, squadIndex int, skill string, experience int, gameChannels *gamecomm.GameChannels) ([]gamecomm.CrewRecord, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.TrainSquadCrew,
+		ResponseChannel: resChan,
+		CorporationId:   mission.CorporationId,
+		SquadIndex:      squadIndex,
+		Skill:           skill,
+		Amount:          experience,
+		DangerLevel:     mission.DangerLevel,
+		MissionId:       mission.Id,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.([]gamecomm.CrewRecord), nil
+}
+
+func getBaseConstructionCost(corporationId uint64, gameChannels *gamecomm.GameChannels) (gamecomm.ConstructionCost, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.GetBaseConstructionCost,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.ConstructionCost{}, res.Err
+	}
+
+	return res.Val.(gamecomm.ConstructionCost), nil
+}
+
+func startBaseConstruction(corporationId uint64, gameChannels *gamecomm.GameChannels) (gamecomm.ConstructionCost, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.StartBaseConstruction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.ConstructionCost{}, res.Err
+	}
+
+	return res.Val.(gamecomm.ConstructionCost), nil
+}
+
+func cancelBaseConstruction(corporationId uint64, gameChannels *gamecomm.GameChannels) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.CancelBaseConstruction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+	}
+
+	res := <-resChan
+	return res.Err
+}
+
+func foundBase(corporationId uint64, name string, location gamecomm.Coordinates, gameChannels *gamecomm.GameChannels) (gamecomm.Base, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.FoundBase,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+		Name:            name,
+		Location:        location,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.Base{}, res.Err
+	}
+
+	return res.Val.(gamecomm.Base), nil
+}
+
+func startFacilityConstruction(corporationId uint64, baseIndex int, facility string, gameChannels *gamecomm.GameChannels) (gamecomm.ConstructionCost, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.StartFacilityConstruction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+		BaseIndex:       baseIndex,
+		Facility:        facility,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.ConstructionCost{}, res.Err
+	}
+
+	return res.Val.(gamecomm.ConstructionCost), nil
+}
+
+func cancelFacilityConstruction(corporationId uint64, baseIndex int, facility string, gameChannels *gamecomm.GameChannels) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.CancelFacilityConstruction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+		BaseIndex:       baseIndex,
+		Facility:        facility,
+	}
+
+	res := <-resChan
+	return res.Err
+}
+
+func completeFacilityConstruction(corporationId uint64, baseIndex int, facility string, gameChannels *gamecomm.GameChannels) (gamecomm.Base, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.CompleteFacilityConstruction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+		BaseIndex:       baseIndex,
+		Facility:        facility,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.Base{}, res.Err
+	}
+
+	return res.Val.(gamecomm.Base), nil
+}
+
+func startProduction(corporationId uint64, baseIndex int, recipe string, batches int, gameChannels *gamecomm.GameChannels) (gamecomm.ProductionJob, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.StartProduction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+		BaseIndex:       baseIndex,
+		Recipe:          recipe,
+		Amount:          batches,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return gamecomm.ProductionJob{}, res.Err
+	}
+
+	return res.Val.(gamecomm.ProductionJob), nil
+}
+
+func cancelProduction(corporationId uint64, baseIndex int, recipe string, batches int, gameChannels *gamecomm.GameChannels) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.CancelProduction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+		BaseIndex:       baseIndex,
+		Recipe:          recipe,
+		Amount:          batches,
+	}
+
+	res := <-resChan
+	return res.Err
+}
+
+func completeProduction(corporationId uint64, baseIndex int, recipe string, batches int, gameChannels *gamecomm.GameChannels) (map[string]int, error) {
+	resChan := make(chan gamecomm.ChanResponse)
+	gameChannels.CorpChannel <- gamecomm.CorpCommand{
+		Action:          gamecomm.CompleteProduction,
+		ResponseChannel: resChan,
+		CorporationId:   corporationId,
+		BaseIndex:       baseIndex,
+		Recipe:          recipe,
+		Amount:          batches,
+	}
+
+	res := <-resChan
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	return res.Val.(map[string]int), nil
+}
+
+// notifyCrewRecords lets the player know about level ups, injuries and deaths on the squad crew
+func notifyCrewRecords(mission *Mission, squadIndex int, records []gamecomm.CrewRecord) {
+	for _, r := range records {
+		switch {
+		case r.Status == gamecomm.CrewDead:
+			mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v, crew member %v died.", squadIndex, r.CrewId)
+		case r.Status == gamecomm.CrewInjured:
+			mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v, crew member %v was injured.", squadIndex, r.CrewId)
+		case r.LevelUp:
+			mission.NotificationChan <- fmt.Sprintf("Mission Notification: Squad %v, crew member %v leveled up.", squadIndex, r.CrewId)
+		}
+	}
+}
+
 // func removeCreditsFromCorporation(corporationId uint64, amount float64, gameChannels *gamecomm.GameChannels) error {
 // 	resChan := make(chan gamecomm.ChanResponse)
 // 	gameChannels.CorpChannel <- gamecomm.CorpCommand{
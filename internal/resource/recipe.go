@@ -0,0 +1,37 @@
+package resource
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamedata"
+)
+
+// Recipe turns the input resources into the output resources on a base facility. Duration is the game hours
+// each batch takes.
+type Recipe struct {
+	Name     string                     `json:"name"`
+	Inputs   map[string]int             `json:"inputs"`
+	Outputs  map[string]int             `json:"outputs"`
+	Duration gameclock.GameTimeDuration `json:"duration"`
+	Facility string                     `json:"facility"`
+}
+
+func LoadRecipes() map[string]Recipe {
+
+	recipes := make(map[string]Recipe, 2)
+
+	file, err := gamedata.Files.Open("recipedata/recipes.json")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(&recipes)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	return recipes
+}
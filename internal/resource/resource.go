@@ -8,9 +8,21 @@ import (
 )
 
 type Resource struct {
-	Name      string  `json:"name"`
-	BasePrice float64 `json:"basePrice"`
-	Rarity    Rarity  `json:"rarity"`
+	Name         string     `json:"name"`
+	BasePrice    float64    `json:"basePrice"`
+	Rarity       Rarity     `json:"rarity"`
+	Manufactured bool       `json:"manufactured"`
+	PriceModel   PriceModel `json:"priceModel"`
+}
+
+// PriceModel sets how the market price of a resource reacts to supply and demand. Elasticity is how much the
+// price moves for the imbalance between supply and demand, Volatility is the biggest daily move as a fraction
+// of the price, and the price factors are the floor and ceiling of the price relative to the base price.
+type PriceModel struct {
+	Elasticity     float64 `json:"elasticity"`
+	Volatility     float64 `json:"volatility"`
+	MinPriceFactor float64 `json:"minPriceFactor"`
+	MaxPriceFactor float64 `json:"maxPriceFactor"`
 }
 
 type Rarity int
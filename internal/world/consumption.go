@@ -0,0 +1,117 @@
+package world
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
+)
+
+// zoneDay is the daily simulation of the planets of a zone. Zones run in parallel, each one with its own random
+// source, and the purchases of the zone are sent to the economy in one batch at the end of the day.
+type zoneDay struct {
+	zoneId    string
+	planets   []*Planet
+	random    *rand.Rand
+	purchases []purchaseRequest
+	ledger    *ledger.Ledger
+}
+
+func (zd *zoneDay) randomInt(min, max int) int {
+	return randomInt(zd.random, min, max)
+}
+
+func (w *World) simulateConsumption() {
+	for range w.newDayChan {
+		w.consumeResources()
+		w.simulateRegeneration()
+	}
+}
+
+// consumeResources runs the day of every zone on as many goroutines as there are cores
+func (w *World) consumeResources() {
+	w.RW.RLock()
+	zoneDays := make([]*zoneDay, 0, len(w.Zones))
+	for _, zone := range w.Zones {
+		zd := &zoneDay{
+			zoneId: zone.Name,
+			random: rand.New(rand.NewSource(w.RandomNumber.Int63())),
+			ledger: w.ledger,
+		}
+
+		for _, planet := range zone.Planets {
+			if planet.IsHabitable {
+				zd.planets = append(zd.planets, planet)
+			}
+		}
+
+		zoneDays = append(zoneDays, zd)
+	}
+	w.RW.RUnlock()
+
+	zoneDayChan := make(chan *zoneDay)
+
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for zd := range zoneDayChan {
+				for _, planet := range zd.planets {
+					w.consumePlanetResources(zd, planet)
+				}
+
+				zd.buyResources(w.economyChan, w.corpChan)
+			}
+		}()
+	}
+
+	for _, zd := range zoneDays {
+		zoneDayChan <- zd
+	}
+	close(zoneDayChan)
+
+	wg.Wait()
+}
+
+func (w *World) consumePlanetResources(zd *zoneDay, planet *Planet) {
+	planet.RW.RLock()
+	cp := planet.CategoryProfile
+	population := planet.Population
+	planet.RW.RUnlock()
+
+	demand := make(map[string]int)
+
+	for _, profile := range []categoryProfile{cp.mainProfile, cp.secondaryProfile} {
+		scale := profile.consumptionScale(population, cp.basePopulation)
+
+		for resourceName, rc := range profile.resourceConsumption {
+			minConsumption := int(float64(rc.minConsumption) * scale)
+			maxConsumption := int(float64(rc.maxConsumption) * scale)
+
+			demand[resourceName] += zd.processResourceConsumption(planet, resourceName, minConsumption, maxConsumption)
+		}
+	}
+
+	foodRemaining, foodSupply := w.consumeSupply(planet, "food")
+	// TODO: food restock should happen only if food production * 30 < actual stock
+	zd.basicSupplyRestock(planet, "food", cp.foodMonthlyProduction, foodRemaining)
+
+	waterRemaining, waterSupply := w.consumeSupply(planet, "water")
+	zd.basicSupplyRestock(planet, "water", cp.waterMonthlyProduction, waterRemaining)
+
+	demand["food"] += dailySupply(population)
+	demand["water"] += dailySupply(population)
+
+	income := planet.collectIncome()
+	w.ledger.Transfer("taxes", taxesAccount, planet.account(), gamecomm.CreditsAsset, income)
+
+	supply := min(foodSupply, waterSupply)
+	w.updatePopulation(planet, supply)
+	planet.evolveCategories(zd.random, supply >= 1)
+
+	planet.updateDemand(demand, w.AllResources)
+}
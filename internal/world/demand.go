@@ -0,0 +1,105 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+const (
+	// demandDays is the days of consumption a planet wants to keep in stock
+	demandDays = 30
+	// maxPriceMarkup is how much over the base price a planet pays for a resource it ran out of
+	maxPriceMarkup = 1.0
+)
+
+// ResourceDemand is the quantity of a resource a planet wants and the maximum price it pays for it
+type ResourceDemand struct {
+	Quantity int
+	MaxPrice float64
+}
+
+// resourceDemand returns what the planet wants to reach demandDays of stock. The bigger the shortage, the
+// more the planet is willing to pay over the base price.
+func resourceDemand(dailyConsumption int, stock int, basePrice float64) ResourceDemand {
+	target := dailyConsumption * demandDays
+	if target <= 0 || stock >= target {
+		return ResourceDemand{}
+	}
+
+	quantity := target - stock
+	shortage := float64(quantity) / float64(target)
+
+	return ResourceDemand{
+		Quantity: quantity,
+		MaxPrice: basePrice * (1 + maxPriceMarkup*shortage),
+	}
+}
+
+// updateDemand recalculates the planet demand profile from its daily consumption and stock
+func (p *Planet) updateDemand(consumption map[string]int, resources map[string]resource.Resource) {
+	p.RW.Lock()
+	defer p.RW.Unlock()
+
+	demand := make(map[string]ResourceDemand, len(consumption))
+	for name, quantity := range consumption {
+		rd := resourceDemand(quantity, p.Resources[name], resources[name].BasePrice)
+		if rd.Quantity == 0 {
+			continue
+		}
+
+		demand[name] = rd
+	}
+
+	p.ResourceDemand = demand
+}
+
+// demandCopy should be called with the planet lock held
+func (p *Planet) demandCopy() map[string]gamecomm.ResourceDemand {
+	demand := make(map[string]gamecomm.ResourceDemand, len(p.ResourceDemand))
+	for name, rd := range p.ResourceDemand {
+		demand[name] = gamecomm.ResourceDemand{Quantity: rd.Quantity, MaxPrice: rd.MaxPrice}
+	}
+
+	return demand
+}
+
+func (w *World) GetPlanetDemand(planetId string) (map[string]gamecomm.ResourceDemand, error) {
+	w.RW.RLock()
+	planet, err := w.getPlanetReference(planetId)
+	w.RW.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	planet.RW.RLock()
+	defer planet.RW.RUnlock()
+
+	return planet.demandCopy(), nil
+}
+
+// GetZoneDemand adds up the demand of every planet in the zone. The max price is the best price offered by
+// any of the planets.
+func (w *World) GetZoneDemand(zoneId string) (map[string]gamecomm.ResourceDemand, error) {
+	w.RW.RLock()
+	zone, ok := w.Zones[zoneId]
+	w.RW.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("error: zone not found: %v", zoneId)
+	}
+
+	demand := make(map[string]gamecomm.ResourceDemand)
+	for _, planet := range zone.Planets {
+		planet.RW.RLock()
+		for name, rd := range planet.ResourceDemand {
+			zd := demand[name]
+			zd.Quantity += rd.Quantity
+			zd.MaxPrice = max(zd.MaxPrice, rd.MaxPrice)
+			demand[name] = zd
+		}
+		planet.RW.RUnlock()
+	}
+
+	return demand, nil
+}
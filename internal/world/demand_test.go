@@ -0,0 +1,47 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+)
+
+func TestResourceDemand(t *testing.T) {
+	tests := []struct {
+		name             string
+		dailyConsumption int
+		stock            int
+		wants            ResourceDemand
+	}{
+		{
+			name:             "Out Of Stock",
+			dailyConsumption: 10,
+			stock:            0,
+			wants:            ResourceDemand{Quantity: 300, MaxPrice: 200},
+		},
+		{
+			name:             "Half Stocked",
+			dailyConsumption: 10,
+			stock:            150,
+			wants:            ResourceDemand{Quantity: 150, MaxPrice: 150},
+		},
+		{
+			name:             "Fully Stocked",
+			dailyConsumption: 10,
+			stock:            500,
+			wants:            ResourceDemand{},
+		},
+		{
+			name:             "No Consumption",
+			dailyConsumption: 0,
+			stock:            0,
+			wants:            ResourceDemand{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, resourceDemand(tt.dailyConsumption, tt.stock, 100), tt.wants)
+		})
+	}
+}
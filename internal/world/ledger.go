@@ -0,0 +1,86 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+var (
+	openingAccount      = gamecomm.NewExternalAccount("opening")
+	consumptionAccount  = gamecomm.NewExternalAccount("consumption")
+	regenerationAccount = gamecomm.NewExternalAccount("regeneration")
+	discoveryAccount    = gamecomm.NewExternalAccount("discovery")
+	taxesAccount        = gamecomm.NewExternalAccount("taxes")
+)
+
+var commandMemos = map[gamecomm.WorldCommandType]string{
+	gamecomm.AddResourcesToPlanet:      "add resources to planet",
+	gamecomm.RemoveResourcesFromPlanet: "remove resources from planet",
+}
+
+func commandMemo(command gamecomm.WorldCommand) string {
+	if command.Memo != "" {
+		return command.Memo
+	}
+
+	return commandMemos[command.Action]
+}
+
+// openAccounts posts the credits and goods every planet starts with
+func (w *World) openAccounts() {
+	for _, planet := range w.Planets {
+		planet.RW.RLock()
+		postings := []gamecomm.Posting{}
+		for account, assets := range planet.holdings() {
+			for asset, amount := range assets {
+				postings = append(postings,
+					gamecomm.Posting{Account: account, Asset: asset, Amount: amount},
+					gamecomm.Posting{Account: openingAccount, Asset: asset, Amount: -amount},
+				)
+			}
+		}
+		planet.RW.RUnlock()
+
+		err := w.ledger.Post(fmt.Sprintf("opening balance of %v", planet.Name), postings...)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+}
+
+// Holdings returns what every planet actually holds on its ledger account
+func (w *World) Holdings() map[gamecomm.Account]map[string]float64 {
+	w.RW.RLock()
+	planets := make([]*Planet, 0, len(w.Planets))
+	for _, planet := range w.Planets {
+		planets = append(planets, planet)
+	}
+	w.RW.RUnlock()
+
+	holdings := make(map[gamecomm.Account]map[string]float64, len(planets))
+	for _, planet := range planets {
+		planet.RW.RLock()
+		for account, assets := range planet.holdings() {
+			holdings[account] = assets
+		}
+		planet.RW.RUnlock()
+	}
+
+	return holdings
+}
+
+// holdings returns the treasury and the resources of the planet. The caller must hold the planet lock.
+func (p *Planet) holdings() map[gamecomm.Account]map[string]float64 {
+	assets := make(map[string]float64, len(p.Resources)+1)
+	for r, amount := range p.Resources {
+		assets[r] = float64(amount)
+	}
+	assets[gamecomm.CreditsAsset] = p.Treasury
+
+	return map[gamecomm.Account]map[string]float64{p.account(): assets}
+}
+
+func (p *Planet) account() gamecomm.Account {
+	return gamecomm.NewPlanetAccount(p.Name)
+}
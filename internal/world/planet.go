@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
 )
 
 type Planet struct {
@@ -14,8 +15,11 @@ type Planet struct {
 	Location        Coordinates
 	Resources       map[string]int
 	Population      int
+	Treasury        float64
 	DangerLevel     int
-	ResourceDemand  map[string]int
+	ResourceDemand  map[string]ResourceDemand
+	Deposits        map[string]int
+	SupplierRecords map[uint64]supplierRecord
 	IsHabitable     bool
 	IsHarvestable   bool
 	CategoryProfile planetCategories
@@ -51,6 +55,7 @@ func (w *World) GeneratePlanetsInZone(numPlanets int, zone Zone, zoneType ZoneTy
 			Name:          fmt.Sprintf("%s-Planet-%d", zone.Name, i+1),
 			Location:      planetLocation,
 			Population:    population,
+			Treasury:      dailyIncome(population) * initialTreasuryDays,
 			DangerLevel:   w.randomInt(zone.DangerRange[0], zone.DangerRange[1]),
 			IsHabitable:   isHabitable,
 			IsHarvestable: !isHabitable,
@@ -60,7 +65,7 @@ func (w *World) GeneratePlanetsInZone(numPlanets int, zone Zone, zoneType ZoneTy
 		planet.RW.Lock()
 
 		if planet.IsHabitable {
-			planet.CategoryProfile = w.generatePlanetCategoryProfile()
+			planet.CategoryProfile = w.generatePlanetCategoryProfile(population)
 		}
 		GeneratePlanetResources(w, zone, planet)
 		planet.RW.Unlock()
@@ -88,16 +93,19 @@ func GeneratePlanetResources(world *World, zone Zone, planet *Planet) {
 	resources[zone.ResourceProfile.Secondary] = resources[zone.ResourceProfile.Secondary]*world.RandomNumber.Intn(3) + 1
 
 	planet.Resources = resources
+	planet.setDeposits(world.AllResources)
 }
 
 func (p *Planet) copy() gamecomm.Planet {
 	return gamecomm.Planet{
-		Name:          p.Name,
-		Location:      gamecomm.Coordinates{X: p.Location.X, Y: p.Location.Y},
-		Population:    p.Population,
-		DangerLevel:   p.DangerLevel,
-		IsHabitable:   p.IsHabitable,
-		IsHarvestable: p.IsHarvestable,
+		Name:           p.Name,
+		Location:       gamecomm.Coordinates{X: p.Location.X, Y: p.Location.Y},
+		Population:     p.Population,
+		Treasury:       p.Treasury,
+		DangerLevel:    p.DangerLevel,
+		IsHabitable:    p.IsHabitable,
+		IsHarvestable:  p.IsHarvestable,
+		ResourceDemand: p.demandCopy(),
 	}
 }
 
@@ -112,6 +120,9 @@ func (w *World) GetPlanet(planetId string) (gamecomm.Planet, error) {
 
 	defer w.RW.RUnlock()
 
+	planet.RW.RLock()
+	defer planet.RW.RUnlock()
+
 	return planet.copy(), nil
 }
 
@@ -166,13 +177,26 @@ func (w *World) DepletePlanetResource(planetId string, resourceName string, amou
 	}
 	w.RW.Unlock()
 
+	return planet.consume(resourceName, amount, w.ledger), nil
+}
+
+// consume depletes the resource of the planet and posts what was used on the ledger
+func (planet *Planet) consume(resourceName string, amount int, l *ledger.Ledger) int {
+	remaining, taken := planet.deplete(resourceName, amount)
+	l.Transfer("consumption", planet.account(), consumptionAccount, resourceName, float64(taken))
+
+	return remaining
+}
+
+// deplete takes up to amount of the resource from the planet and returns what is left and what was taken
+func (planet *Planet) deplete(resourceName string, amount int) (int, int) {
 	planet.RW.Lock()
 	defer planet.RW.Unlock()
 
-	remainingQty := planet.Resources[resourceName] - amount
-	planet.Resources[resourceName] = max(remainingQty, 0)
+	taken := min(planet.Resources[resourceName], max(amount, 0))
+	planet.Resources[resourceName] -= taken
 
-	return planet.Resources[resourceName], nil
+	return planet.Resources[resourceName], taken
 }
 
 func (w *World) AddResourcesToPlanet(planetId string, resourceName string, amount int) (int, error) {
@@ -13,11 +13,16 @@ type planetCategories struct {
 	secondaryProfile       categoryProfile
 	foodMonthlyProduction  int
 	waterMonthlyProduction int
+	basePopulation         int
+	scoringWeights         ScoringWeights
 }
 
+// categoryProfile keeps the level the consumption was generated with, the consumption follows the level as
+// it evolves
 type categoryProfile struct {
 	category            string
 	level               uint
+	baseLevel           uint
 	resourceConsumption resourceConsumption
 }
 
@@ -29,9 +34,10 @@ type consumptionValues struct {
 }
 
 type Category struct {
-	Name        string
-	Description string
-	Resources   resourceSlice
+	Name           string
+	Description    string
+	Resources      resourceSlice
+	ScoringWeights ScoringWeights
 }
 
 type resourceSlice []string
@@ -63,7 +69,7 @@ func loadCategories() map[string]Category {
 	return categories
 }
 
-func (w *World) generatePlanetCategoryProfile() planetCategories {
+func (w *World) generatePlanetCategoryProfile(population int) planetCategories {
 
 	categories := []string{}
 	for categoryId := range w.Categories {
@@ -117,12 +123,14 @@ func (w *World) generatePlanetCategoryProfile() planetCategories {
 	mainCategoryProfile := categoryProfile{
 		category:            mainCategory,
 		level:               uint(mainLevel),
+		baseLevel:           uint(mainLevel),
 		resourceConsumption: mainConsumption,
 	}
 
 	secondaryCategoryProfile := categoryProfile{
 		category:            secondaryCategory,
 		level:               uint(secondaryLevel),
+		baseLevel:           uint(secondaryLevel),
 		resourceConsumption: secondaryConsumption,
 	}
 
@@ -135,6 +143,8 @@ func (w *World) generatePlanetCategoryProfile() planetCategories {
 		secondaryProfile:       secondaryCategoryProfile,
 		foodMonthlyProduction:  foodMonthlyProduction,
 		waterMonthlyProduction: waterMonthlyProduction,
+		basePopulation:         population,
+		scoringWeights:         w.Categories[mainCategory].ScoringWeights,
 	}
 }
 
@@ -143,32 +153,3 @@ func (w *World) generatePlanetCategoryProfile() planetCategories {
 // TODO: It would be basics based on population.
 // TODO: Also by technology
 // TODO: Add bonus consumptions, this would have resource and endTime
-
-func (w *World) simulateConsumption() {
-	for range w.newDayChan {
-		w.consumeResources()
-	}
-}
-
-func (w *World) consumeResources() {
-	for _, planet := range w.Planets {
-
-		if !planet.IsHabitable {
-			continue
-		}
-
-		cp := planet.CategoryProfile
-
-		for resourceName, rc := range cp.mainProfile.resourceConsumption {
-			w.processResourceConsumption(planet.Name, resourceName, rc.minConsumption, rc.maxConsumption)
-		}
-
-		for resourceName, rc := range cp.secondaryProfile.resourceConsumption {
-			w.processResourceConsumption(planet.Name, resourceName, rc.minConsumption, rc.maxConsumption)
-		}
-
-		remaining, _ := w.DepletePlanetResource(planet.Name, "food", planet.Population)
-		// TODO: food restock should happen only if food production * 30 < actual stock
-		w.basicSupplyRestock(planet.Name, "food", cp.foodMonthlyProduction, remaining)
-	}
-}
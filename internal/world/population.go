@@ -0,0 +1,97 @@
+package world
+
+import "math/rand"
+
+const (
+	// peoplePerSupplyUnit is how many people a unit of food or water feeds for a day
+	peoplePerSupplyUnit = 10_000
+	// growthRate is the daily population growth of a planet with enough food and water
+	growthRate = 0.0005
+	// starvationRate is the daily population loss of a planet without any food or water
+	starvationRate = 0.01
+
+	levelUpProbability   = 0.05
+	levelDownProbability = 0.02
+	maxMainLevel         = 99
+	maxSecondaryLevel    = 20
+)
+
+// dailySupply returns the food or water the population needs each day
+func dailySupply(population int) int {
+	return population / peoplePerSupplyUnit
+}
+
+// nextPopulation returns the population after a day. Supply is the fraction of the food and water needs
+// covered; fed planets grow and starving ones shrink as much as their shortage.
+func nextPopulation(population int, supply float64) int {
+	if supply >= 1 {
+		return population + int(float64(population)*growthRate)
+	}
+
+	return population - int(float64(population)*starvationRate*(1-supply))
+}
+
+// consumeSupply takes the daily food or water of the population from the planet. Returns the remaining stock
+// and the fraction of the needs covered.
+func (w *World) consumeSupply(planet *Planet, resourceName string) (int, float64) {
+	planet.RW.RLock()
+	needed := dailySupply(planet.Population)
+	available := planet.Resources[resourceName]
+	planet.RW.RUnlock()
+
+	remaining := planet.consume(resourceName, needed, w.ledger)
+	if needed == 0 {
+		return remaining, 1
+	}
+
+	return remaining, min(float64(available)/float64(needed), 1)
+}
+
+// updatePopulation grows or shrinks the planet population with its supply of food and water
+func (w *World) updatePopulation(planet *Planet, supply float64) {
+	planet.RW.Lock()
+	defer planet.RW.Unlock()
+
+	planet.Population = max(nextPopulation(planet.Population, supply), 0)
+}
+
+// evolveLevel moves the category level up or down by one. Thriving planets tend to develop their
+// industries and starving ones tend to lose them.
+func evolveLevel(random *rand.Rand, level uint, maxLevel uint, thriving bool) uint {
+	up, down := levelUpProbability, levelDownProbability
+	if !thriving {
+		up, down = down, up
+	}
+
+	roll := random.Float64()
+
+	switch {
+	case roll < up && level < maxLevel:
+		return level + 1
+	case roll >= up && roll < up+down && level > 0:
+		return level - 1
+	default:
+		return level
+	}
+}
+
+func (planet *Planet) evolveCategories(random *rand.Rand, thriving bool) {
+	planet.RW.Lock()
+	defer planet.RW.Unlock()
+
+	cp := &planet.CategoryProfile
+	cp.mainProfile.level = evolveLevel(random, cp.mainProfile.level, maxMainLevel, thriving)
+	cp.secondaryProfile.level = evolveLevel(random, cp.secondaryProfile.level, maxSecondaryLevel, thriving)
+}
+
+// consumptionScale returns how much the category consumption changed since the planet was generated. It
+// follows the population and the category level.
+func (cp categoryProfile) consumptionScale(population int, basePopulation int) float64 {
+	scale := float64(cp.level+1) / float64(cp.baseLevel+1)
+
+	if basePopulation > 0 {
+		scale *= float64(population) / float64(basePopulation)
+	}
+
+	return scale
+}
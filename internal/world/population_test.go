@@ -0,0 +1,128 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+)
+
+func TestNextPopulation(t *testing.T) {
+	tests := []struct {
+		name       string
+		population int
+		supply     float64
+		wants      int
+	}{
+		{
+			name:       "Fed Planet Grows",
+			population: 1_000_000,
+			supply:     1,
+			wants:      1_000_500,
+		},
+		{
+			name:       "Starving Planet Shrinks",
+			population: 1_000_000,
+			supply:     0,
+			wants:      990_000,
+		},
+		{
+			name:       "Shortage Shrinks Less",
+			population: 1_000_000,
+			supply:     0.5,
+			wants:      995_000,
+		},
+		{
+			name:       "Empty Planet",
+			population: 0,
+			supply:     1,
+			wants:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, nextPopulation(tt.population, tt.supply), tt.wants)
+		})
+	}
+}
+
+func TestConsumeSupply(t *testing.T) {
+	w := &World{Planets: make(map[string]*Planet)}
+
+	planet := &Planet{
+		Name:       "Planet-1",
+		Population: 1_000_000,
+		Resources:  map[string]int{"food": 50, "water": 1_000},
+	}
+	w.Planets[planet.Name] = planet
+
+	remaining, supply := w.consumeSupply(planet, "food")
+	assert.Equal(t, remaining, 0)
+	assert.Equal(t, supply, 0.5)
+
+	remaining, supply = w.consumeSupply(planet, "water")
+	assert.Equal(t, remaining, 900)
+	assert.Equal(t, supply, 1.0)
+}
+
+func TestConsumptionScale(t *testing.T) {
+	tests := []struct {
+		name           string
+		profile        categoryProfile
+		population     int
+		basePopulation int
+		wants          float64
+	}{
+		{
+			name:           "Unchanged Planet",
+			profile:        categoryProfile{level: 9, baseLevel: 9},
+			population:     1_000,
+			basePopulation: 1_000,
+			wants:          1,
+		},
+		{
+			name:           "Population Doubled",
+			profile:        categoryProfile{level: 9, baseLevel: 9},
+			population:     2_000,
+			basePopulation: 1_000,
+			wants:          2,
+		},
+		{
+			name:           "Level Increased",
+			profile:        categoryProfile{level: 19, baseLevel: 9},
+			population:     1_000,
+			basePopulation: 1_000,
+			wants:          2,
+		},
+		{
+			name:           "No Base Population",
+			profile:        categoryProfile{level: 9, baseLevel: 9},
+			population:     1_000,
+			basePopulation: 0,
+			wants:          1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.profile.consumptionScale(tt.population, tt.basePopulation), tt.wants)
+		})
+	}
+}
+
+func TestEvolveLevel(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+
+	level := uint(10)
+	for i := 0; i < 1_000; i++ {
+		level = evolveLevel(random, level, maxSecondaryLevel, true)
+		assert.Equal(t, level <= maxSecondaryLevel, true)
+	}
+
+	level = uint(0)
+	for i := 0; i < 1_000; i++ {
+		level = evolveLevel(random, level, maxSecondaryLevel, false)
+		assert.Equal(t, level <= maxSecondaryLevel, true)
+	}
+}
@@ -0,0 +1,116 @@
+package world
+
+import (
+	"github.com/luisya22/galactic-exchange/internal/maputils"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+const (
+	// discoveryProbability is the daily chance of finding a new deposit on a harvestable planet
+	discoveryProbability = 0.001
+	minDiscoverySize     = 100_000
+	maxDiscoverySize     = 1_000_000
+)
+
+// regenerationRates is the fraction of a deposit that regenerates each day, rare resources take longer
+var regenerationRates = map[resource.Rarity]float64{
+	resource.Abundant: 0.02,
+	resource.Common:   0.01,
+	resource.Scarce:   0.005,
+	resource.Rare:     0.002,
+}
+
+// maxDepositSizes is the biggest a deposit can grow through discoveries, rare resources have smaller deposits
+var maxDepositSizes = map[resource.Rarity]int{
+	resource.Abundant: 5_000_000,
+	resource.Common:   4_000_000,
+	resource.Scarce:   3_000_000,
+	resource.Rare:     2_000_000,
+}
+
+// dailyRegeneration returns the amount of the deposit that regenerates in a day
+func dailyRegeneration(deposit int, rarity resource.Rarity, zoneMultiplier float64) int {
+	return int(float64(deposit) * regenerationRates[rarity] * zoneMultiplier)
+}
+
+func (w *World) simulateRegeneration() {
+	for _, zone := range w.Zones {
+		zoneType := w.AllZoneTypes[zone.ZoneType]
+
+		for _, planet := range zone.Planets {
+			if !planet.IsHarvestable {
+				continue
+			}
+
+			w.regeneratePlanetResources(planet, zoneType.RegenerationMultiplier)
+			w.discoverDeposit(planet)
+		}
+	}
+}
+
+// regeneratePlanetResources refills the planet resources up to their deposit size
+func (w *World) regeneratePlanetResources(planet *Planet, zoneMultiplier float64) {
+	planet.RW.Lock()
+	defer planet.RW.Unlock()
+
+	for name, deposit := range planet.Deposits {
+		res, ok := w.AllResources[name]
+		if !ok || planet.Resources[name] >= deposit {
+			continue
+		}
+
+		regenerated := min(dailyRegeneration(deposit, res.Rarity, zoneMultiplier), deposit-planet.Resources[name])
+		planet.Resources[name] += regenerated
+
+		w.ledger.Transfer("regeneration", regenerationAccount, planet.account(), name, float64(regenerated))
+	}
+}
+
+// discoverDeposit occasionally finds a new deposit of a resource the planet danger level allows, growing the
+// planet deposit size up to the maximum deposit size of the resource
+func (w *World) discoverDeposit(planet *Planet) {
+	if w.RandomNumber.Float64() >= discoveryProbability {
+		return
+	}
+
+	planet.RW.Lock()
+	defer planet.RW.Unlock()
+
+	candidates := []string{}
+	for name, res := range w.AllResources {
+		if shouldIncludeResource(w, res, planet) && planet.Deposits[name] < maxDepositSizes[res.Rarity] {
+			candidates = append(candidates, name)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	name := candidates[w.RandomNumber.Intn(len(candidates))]
+	size := min(w.randomInt(minDiscoverySize, maxDiscoverySize), maxDepositSizes[w.AllResources[name].Rarity]-planet.Deposits[name])
+
+	if planet.Deposits == nil {
+		planet.Deposits = make(map[string]int)
+	}
+
+	planet.Deposits[name] += size
+	planet.Resources[name] += size
+
+	w.ledger.Transfer("deposit discovery", discoveryAccount, planet.account(), name, float64(size))
+}
+
+// setDeposits takes the generated resources as the maximum deposit size of the planet, neither grows over the
+// maximum deposit size of the resource
+func (p *Planet) setDeposits(allResources map[string]resource.Resource) {
+	for name, amount := range p.Resources {
+		res, ok := allResources[name]
+		if !ok {
+			continue
+		}
+
+		p.Resources[name] = min(amount, maxDepositSizes[res.Rarity])
+	}
+
+	p.Deposits = maputils.CopyMap(p.Resources)
+}
@@ -0,0 +1,105 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/resource"
+)
+
+func TestRegeneratePlanetResources(t *testing.T) {
+	w := &World{
+		AllResources: map[string]resource.Resource{
+			"water": {Name: "water", Rarity: resource.Abundant},
+			"gold":  {Name: "gold", Rarity: resource.Rare},
+			"iron":  {Name: "iron", Rarity: resource.Common},
+		},
+	}
+
+	planet := &Planet{
+		Name:          "Planet-1",
+		IsHarvestable: true,
+		Resources:     map[string]int{"water": 0, "gold": 0, "iron": 999_000},
+		Deposits:      map[string]int{"water": 1_000_000, "gold": 1_000_000, "iron": 1_000_000},
+	}
+
+	w.regeneratePlanetResources(planet, 2)
+
+	assert.Equal(t, planet.Resources["water"], 40_000)
+	assert.Equal(t, planet.Resources["gold"], 4_000)
+
+	// Resources don't grow over the deposit size
+	assert.Equal(t, planet.Resources["iron"], 1_000_000)
+}
+
+func TestDiscoverDeposit(t *testing.T) {
+	w := &World{
+		AllResources: map[string]resource.Resource{
+			"water": {Name: "water", Rarity: resource.Abundant},
+		},
+		RandomNumber: rand.New(rand.NewSource(0)),
+	}
+
+	planet := &Planet{
+		Name:          "Planet-1",
+		IsHarvestable: true,
+		Resources:     map[string]int{"water": 0},
+	}
+
+	for i := 0; i < 100_000 && planet.Resources["water"] == 0; i++ {
+		w.discoverDeposit(planet)
+	}
+
+	assert.Greater(t, planet.Resources["water"], minDiscoverySize-1)
+	assert.Equal(t, planet.Deposits["water"], planet.Resources["water"])
+}
+
+func TestDiscoverDepositMaxSize(t *testing.T) {
+	w := &World{
+		AllResources: map[string]resource.Resource{
+			"water": {Name: "water", Rarity: resource.Abundant},
+		},
+		RandomNumber: rand.New(rand.NewSource(0)),
+	}
+
+	maxSize := maxDepositSizes[resource.Abundant]
+
+	planet := &Planet{
+		Name:          "Planet-1",
+		IsHarvestable: true,
+		Resources:     map[string]int{"water": maxSize - 10},
+		Deposits:      map[string]int{"water": maxSize - 10},
+	}
+
+	for i := 0; i < 100_000; i++ {
+		w.discoverDeposit(planet)
+	}
+
+	assert.Equal(t, planet.Deposits["water"], maxSize)
+	assert.Equal(t, planet.Resources["water"], maxSize)
+}
+
+func TestSetDeposits(t *testing.T) {
+	allResources := map[string]resource.Resource{
+		"water": {Name: "water", Rarity: resource.Abundant},
+		"gold":  {Name: "gold", Rarity: resource.Rare},
+		"iron":  {Name: "iron", Rarity: resource.Common},
+	}
+
+	planet := &Planet{
+		Name:      "Planet-1",
+		Resources: map[string]int{"water": 900_000, "gold": 3_996_001, "iron": 4_000_000},
+	}
+
+	planet.setDeposits(allResources)
+
+	assert.Equal(t, planet.Resources["water"], 900_000)
+	assert.Equal(t, planet.Deposits["water"], 900_000)
+
+	// Primary resources can be generated over the maximum deposit size
+	assert.Equal(t, planet.Resources["gold"], maxDepositSizes[resource.Rare])
+	assert.Equal(t, planet.Deposits["gold"], maxDepositSizes[resource.Rare])
+	assert.Equal(t, planet.Resources["iron"], maxDepositSizes[resource.Common])
+	assert.Equal(t, planet.Deposits["iron"], maxDepositSizes[resource.Common])
+}
@@ -18,6 +18,11 @@ type ResourceInfo struct {
 
 func shouldIncludeResource(world *World, res resource.Resource, planet *Planet) bool {
 
+	// Manufactured resources are only made on bases
+	if res.Manufactured {
+		return false
+	}
+
 	switch res.Rarity {
 	case resource.Abundant:
 		return true
@@ -37,6 +42,10 @@ func GenerateResourceProfile(worldResources map[string]resource.Resource) Resour
 	// TODO: improve this later to not use []string but map[string]Resource
 	resources := []string{}
 	for _, r := range worldResources {
+		if r.Manufactured {
+			continue
+		}
+
 		resources = append(resources, r.Name)
 	}
 
@@ -2,6 +2,8 @@ package world
 
 import (
 	"fmt"
+	"maps"
+	"sort"
 
 	"github.com/luisya22/galactic-exchange/internal/economy"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
@@ -14,29 +16,30 @@ type listingScore struct {
 
 // TODO: After doing buy move everything to it's own package
 
-func (w *World) basicSupplyRestock(planetId string, resourceName string, monthlyProduction int, actualStock int) {
-	if actualStock/30 < monthlyProduction {
-		// TODO: Buy
-		w.RW.RLock()
-		defer w.RW.RUnlock()
-
-		planet := w.Planets[planetId]
-
-		wantsToBuy := monthlyProduction * w.randomInt(1, 3)
-		planet.buyResource(resourceName, wantsToBuy, w.economyChan)
-
-		// fmt.Printf("%v wants to buy %v %v\n", planetId, wantsToBuy, resourceName)
+// purchaseRequest is a resource a planet wants to buy, the zone sends all of them to the economy at once
+type purchaseRequest struct {
+	planet   *Planet
+	resource string
+	amount   int
+}
 
+func (zd *zoneDay) basicSupplyRestock(planet *Planet, resourceName string, monthlyProduction int, actualStock int) {
+	if actualStock/30 < monthlyProduction {
+		wantsToBuy := monthlyProduction * zd.randomInt(1, 3)
+		zd.requestPurchase(planet, resourceName, wantsToBuy)
 	}
 }
 
-func (w *World) processResourceConsumption(planetId string, resourceName string, minConsumption int, maxConsumption int) {
-	quantity := w.randomInt(minConsumption, maxConsumption)
-	remaning, _ := w.DepletePlanetResource(planetId, resourceName, quantity)
+// processResourceConsumption consumes a random quantity of the resource and returns it
+func (zd *zoneDay) processResourceConsumption(planet *Planet, resourceName string, minConsumption int, maxConsumption int) int {
+	quantity := zd.randomInt(minConsumption, maxConsumption)
+	remaning := planet.consume(resourceName, quantity, zd.ledger)
 
 	weeklyConsumption := quantity * 7
 
-	w.restockResources(planetId, resourceName, weeklyConsumption, remaning)
+	zd.restockResources(planet, resourceName, weeklyConsumption, remaning)
+
+	return quantity
 }
 
 func classifyResourceLevel(weeklyConsumption int, totalStorage int) string {
@@ -73,149 +76,256 @@ func purchaseProbability(resourceLevel string) float64 {
 	}
 }
 
-func (w *World) restockResources(planetId string, resource string, weeklyConsumption int, totalStorage int) {
+func (zd *zoneDay) restockResources(planet *Planet, resource string, weeklyConsumption int, totalStorage int) {
 	resourceLevel := classifyResourceLevel(weeklyConsumption, totalStorage)
 	purchaseProb := purchaseProbability(resourceLevel)
 
-	randomFloat := w.RandomNumber.Float64()
+	randomFloat := zd.random.Float64()
 
-	wantsToBuy := w.randomInt(weeklyConsumption, weeklyConsumption*4)
+	wantsToBuy := zd.randomInt(weeklyConsumption, weeklyConsumption*4)
 
 	if randomFloat <= purchaseProb {
-		// TODO: Make purchase
-		w.RW.RLock()
-		defer w.RW.RUnlock()
-
-		planet := w.Planets[planetId]
-		planet.buyResource(resource, wantsToBuy, w.economyChan)
+		zd.requestPurchase(planet, resource, wantsToBuy)
+	}
+}
 
+func (zd *zoneDay) requestPurchase(planet *Planet, resource string, amount int) {
+	if amount <= 0 {
+		return
 	}
+
+	zd.purchases = append(zd.purchases, purchaseRequest{
+		planet:   planet,
+		resource: resource,
+		amount:   amount,
+	})
 }
 
-func (planet *Planet) buyResource(resource string, amount int, economyChan chan gamecomm.EconomyCommand) {
-	planet.RW.RLock()
-	defer planet.RW.RUnlock()
+// buyResources sends the purchases of the zone to the economy in one batch. Every planet ranks the listings of the
+// zone with its own weights and the economy buys from them in that order.
+func (zd *zoneDay) buyResources(economyChan chan gamecomm.EconomyCommand, corpChan chan gamecomm.CorpCommand) {
+	if len(zd.purchases) == 0 {
+		return
+	}
 
-	// Get Market Price
+	// Get Market Prices
 	resChan := make(chan gamecomm.ChanResponse)
-	command := gamecomm.EconomyCommand{
-		Action:          gamecomm.GetMarketPrice,
-		ZoneId:          planet.ZoneId,
-		Resource:        resource,
+	economyChan <- gamecomm.EconomyCommand{
+		Action:          gamecomm.GetMarketPrices,
+		ZoneId:          zd.zoneId,
 		ResponseChannel: resChan,
 	}
 
-	economyChan <- command
-
 	res := <-resChan
 	if res.Err != nil {
 		fmt.Println(res.Err.Error())
 		return
 	}
 
-	marketValue := res.Val.(float64)
+	marketValues := res.Val.(map[string]float64)
 
-	// Get all offers
-	resChan = make(chan gamecomm.ChanResponse)
-	command = gamecomm.EconomyCommand{
-		Action:          gamecomm.GetMarketListingsByResource,
-		Resource:        resource,
-		ZoneId:          planet.ZoneId,
-		ResponseChannel: resChan,
+	// Get the offers of the resources the planets want
+	resourceListings := make(map[string][]economy.MarketListing)
+	marketListings := []economy.MarketListing{}
+	for _, pr := range zd.purchases {
+		if _, ok := resourceListings[pr.resource]; ok {
+			continue
+		}
+
+		resChan = make(chan gamecomm.ChanResponse)
+		economyChan <- gamecomm.EconomyCommand{
+			Action:          gamecomm.GetMarketListingsByResource,
+			ZoneId:          zd.zoneId,
+			Resource:        pr.resource,
+			ResponseChannel: resChan,
+		}
+
+		res = <-resChan
+		if res.Err != nil {
+			fmt.Println(res.Err.Error())
+			return
+		}
+
+		listings := res.Val.([]economy.MarketListing)
+		resourceListings[pr.resource] = listings
+		marketListings = append(marketListings, listings...)
 	}
 
-	economyChan <- command
-	res = <-resChan
-	if res.Err != nil {
-		fmt.Println(res.Err.Error())
+	if len(marketListings) == 0 {
 		return
 	}
 
-	marketListings := res.Val.([]economy.MarketListing)
+	listingSellers := make(map[string]uint64, len(marketListings))
+	for _, ml := range marketListings {
+		listingSellers[ml.Id] = ml.CorporationId
+	}
 
-	// Evaluate and score each one
-	listingScores := []listingScore{}
-	for i, ml := range marketListings {
-		score := scoreListing(ml, amount, marketValue)
+	corporations := findCorporations(marketListings, corpChan)
 
-		ls := listingScore{
-			score: score,
-			index: i,
-		}
+	orders := make([]gamecomm.PurchaseOrder, 0, len(zd.purchases))
+	requests := make([]purchaseRequest, 0, len(zd.purchases))
+	budgets := make(map[string]float64)
+	planetSellers := make(map[string]map[uint64]seller)
 
-		listingScores = insertSorted(listingScores, ls)
-	}
+	for _, pr := range zd.purchases {
+		listings := resourceListings[pr.resource]
+		if len(listings) == 0 {
+			continue
+		}
 
-	for i := range listingScores {
+		pr.planet.RW.RLock()
+		treasury := pr.planet.Treasury
+		weights := pr.planet.CategoryProfile.scoringWeights.orDefault()
+		pr.planet.RW.RUnlock()
 
-		fmt.Println(i)
-		ml := marketListings[i]
+		// Planets refuse listings they can't afford
+		if treasury <= 0 {
+			continue
+		}
 
-		resChan := make(chan gamecomm.ChanResponse)
-		command := gamecomm.EconomyCommand{
-			Action:          gamecomm.BuyMarketListing,
-			ZoneId:          planet.ZoneId,
-			MarketListingId: ml.Id,
-			ResponseChannel: resChan,
+		sellers, ok := planetSellers[pr.planet.Name]
+		if !ok {
+			sellers = pr.planet.sellers(corporations)
+			planetSellers[pr.planet.Name] = sellers
 		}
 
-		economyChan <- command
-		fmt.Println("Buy Sent")
-		res = <-resChan
-		fmt.Println("Buy received", res)
-		if res.Err != nil {
-			return
+		ranked := rankListings(listings, pr.amount, marketValues[pr.resource], sellers, weights)
+
+		listingIds := make([]string, 0, len(ranked))
+		for _, ml := range ranked {
+			listingIds = append(listingIds, ml.Id)
 		}
 
-		amount -= res.Val.(int)
-		if amount <= 0 {
-			break
+		budgets[pr.planet.Name] = treasury
+		orders = append(orders, gamecomm.PurchaseOrder{
+			BuyerPlanetId: pr.planet.Name,
+			Resource:      pr.resource,
+			Amount:        pr.amount,
+			ListingIds:    listingIds,
+		})
+		requests = append(requests, pr)
+	}
+
+	if len(orders) == 0 {
+		return
+	}
+
+	market := gamecomm.MarketEscrow(zd.zoneId)
+
+	// Planets pay their budget into the market escrow before buying, so every fill is already paid for. The credits
+	// they don't spend are given back after the purchase.
+	planets := make(map[string]*Planet, len(budgets))
+	for _, pr := range requests {
+		planets[pr.planet.Name] = pr.planet
+	}
+
+	for name, planet := range planets {
+		err := planet.spend(budgets[name])
+		if err != nil {
+			fmt.Println(err.Error())
+			budgets[name] = 0
+			continue
 		}
+
+		zd.ledger.Transfer("market budget", planet.account(), market, gamecomm.CreditsAsset, budgets[name])
 	}
 
-	fmt.Printf("%v bought %v %v", planet.Name, amount, resource)
+	unspent := maps.Clone(budgets)
 
-}
+	resChan = make(chan gamecomm.ChanResponse)
+	economyChan <- gamecomm.EconomyCommand{
+		Action:          gamecomm.BuyMarketListings,
+		ZoneId:          zd.zoneId,
+		Orders:          orders,
+		Budgets:         budgets,
+		ResponseChannel: resChan,
+	}
 
-func scoreListing(marketListing economy.MarketListing, amountToBuy int, marketValue float64) float64 {
-	priceScore := marketValue / marketListing.Price
+	res = <-resChan
+	if res.Err != nil {
+		fmt.Println(res.Err.Error())
+		zd.refundBudgets(planets, unspent, market)
+		return
+	}
+
+	payments := make(map[uint64]float64)
+	for _, fill := range res.Val.([]gamecomm.PurchaseFill) {
+		pr := requests[fill.Order]
+		corporationId := listingSellers[fill.ListingId]
+
+		// Only the sellers that took the order are scored on it
+		if fill.Amount == 0 {
+			continue
+		}
+
+		pr.planet.RW.Lock()
+		pr.planet.recordDelivery(corporationId, fill.Requested, fill.Amount)
+		pr.planet.RW.Unlock()
 
-	fullAmountScore := 0.0
-	if marketListing.Amount >= amountToBuy {
-		fullAmountScore = 1
+		credits := float64(fill.Amount) * fill.Price
+		unspent[pr.planet.Name] -= credits
+
+		pr.planet.addResources(pr.resource, fill.Amount)
+		payments[corporationId] += credits
+
+		zd.ledger.Transfer("market purchase", market, pr.planet.account(), pr.resource, float64(fill.Amount))
 	}
 
-	priceWeight := 0.7
-	amountWeight := 0.3
+	zd.refundBudgets(planets, unspent, market)
 
-	score := (priceWeight * priceScore) + (amountWeight * fullAmountScore)
-	return score
+	for corporationId, credits := range payments {
+		err := paySeller(corporationId, credits, market, corpChan)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
 }
 
-func binarySearch(listingScores []listingScore, ls listingScore) int {
-	low, high := 0, len(listingScores)
-	for low < high {
-		mid := low + (high-low)/2
-		if listingScores[mid].score < ls.score {
-			low = mid + 1
-		} else {
-			high = mid
+// refundBudgets gives the planets back the credits of their budget they didn't spend
+func (zd *zoneDay) refundBudgets(planets map[string]*Planet, unspent map[string]float64, market gamecomm.Account) {
+	for name, credits := range unspent {
+		if credits <= 0 {
+			continue
 		}
-	}
 
-	return low
+		planets[name].deposit(credits)
+		zd.ledger.Transfer("market budget refund", market, planets[name].account(), gamecomm.CreditsAsset, credits)
+	}
 }
 
-func insertSorted(listingScores []listingScore, ls listingScore) []listingScore {
-	index := binarySearch(listingScores, ls)
+// rankListings returns the listings sorted from the best to the worst score, listings with the same score keep
+// their order
+func rankListings(marketListings []economy.MarketListing, amount int, marketValue float64, sellers map[uint64]seller, weights ScoringWeights) []economy.MarketListing {
+	listingScores := make([]listingScore, 0, len(marketListings))
+	for i, ml := range marketListings {
+		listingScores = append(listingScores, listingScore{
+			score: scoreListing(ml, amount, marketValue, sellers[ml.CorporationId], weights),
+			index: i,
+		})
+	}
 
-	listingScores = append(listingScores, listingScore{})
+	sort.SliceStable(listingScores, func(i, j int) bool {
+		return listingScores[i].score > listingScores[j].score
+	})
 
-	copy(listingScores[index+1:], listingScores[index:])
+	ranked := make([]economy.MarketListing, 0, len(listingScores))
+	for _, ls := range listingScores {
+		ranked = append(ranked, marketListings[ls.index])
+	}
 
-	listingScores[index] = ls
+	return ranked
+}
+
+// addResources stores the bought resources on the planet
+func (planet *Planet) addResources(resource string, amount int) {
+	planet.RW.Lock()
+	defer planet.RW.Unlock()
+
+	if planet.Resources == nil {
+		planet.Resources = make(map[string]int)
+	}
 
-	return listingScores
+	planet.Resources[resource] += amount
 }
 
 // TODO: planets should analyze their resource scarcity
@@ -0,0 +1,257 @@
+package world
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/economy"
+	"github.com/luisya22/galactic-exchange/internal/gameclock"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
+)
+
+func TestRankListings(t *testing.T) {
+	marketListings := []economy.MarketListing{
+		{Id: "expensive", Amount: 1_000, Price: 200},
+		{Id: "cheap", Amount: 1_000, Price: 50},
+		{Id: "market", Amount: 1_000, Price: 100},
+	}
+
+	ranked := rankListings(marketListings, 100, 100, nil, defaultScoringWeights)
+
+	assert.Equal(t, len(ranked), 3)
+	assert.Equal(t, ranked[0].Id, "cheap")
+	assert.Equal(t, ranked[1].Id, "market")
+	assert.Equal(t, ranked[2].Id, "expensive")
+}
+
+func TestRankListingsBySeller(t *testing.T) {
+	marketListings := []economy.MarketListing{
+		{Id: "far", Amount: 1_000, Price: 95, CorporationId: 1},
+		{Id: "near", Amount: 1_000, Price: 100, CorporationId: 2},
+	}
+
+	sellers := map[uint64]seller{
+		1: {distance: 1_000, reputation: -50, reliability: 0.2},
+		2: {distance: 5, reputation: 50, reliability: 1},
+	}
+
+	ranked := rankListings(marketListings, 100, 100, sellers, defaultScoringWeights)
+	assert.Equal(t, ranked[0].Id, "near")
+
+	// Planets that only care about price still buy from the cheapest seller
+	ranked = rankListings(marketListings, 100, 100, sellers, ScoringWeights{Price: 1})
+	assert.Equal(t, ranked[0].Id, "far")
+}
+
+func TestBuyResources(t *testing.T) {
+	economyChan := make(chan gamecomm.EconomyCommand)
+	corpChan := make(chan gamecomm.CorpCommand)
+
+	planet := &Planet{
+		Name:      "Planet-1",
+		ZoneId:    "Zone-1",
+		Treasury:  10_000,
+		Resources: map[string]int{"iron": 0},
+	}
+
+	marketListings := []economy.MarketListing{
+		{Id: "expensive", ResourceName: "iron", Amount: 1_000, Price: 200, CorporationId: 2},
+		{Id: "sold-out", ResourceName: "iron", Amount: 1_000, Price: 40, CorporationId: 3},
+		{Id: "cheap", ResourceName: "iron", Amount: 30, Price: 50, CorporationId: 1},
+	}
+
+	zd := &zoneDay{zoneId: "Zone-1"}
+	zd.requestPurchase(planet, "iron", 50)
+	// Nothing is listed for gold so the request is dropped
+	zd.requestPurchase(planet, "gold", 10)
+
+	done := make(chan struct{})
+	go func() {
+		zd.buyResources(economyChan, corpChan)
+		close(done)
+	}()
+
+	command := <-economyChan
+	assert.Equal(t, command.Action, gamecomm.GetMarketPrices)
+	assert.Equal(t, command.ZoneId, "Zone-1")
+	command.ResponseChannel <- gamecomm.ChanResponse{Val: map[string]float64{"iron": 100}}
+
+	// Only the listings of the wanted resources are fetched
+	command = <-economyChan
+	assert.Equal(t, command.Action, gamecomm.GetMarketListingsByResource)
+	assert.Equal(t, command.Resource, "iron")
+	command.ResponseChannel <- gamecomm.ChanResponse{Val: marketListings}
+
+	command = <-economyChan
+	assert.Equal(t, command.Action, gamecomm.GetMarketListingsByResource)
+	assert.Equal(t, command.Resource, "gold")
+	command.ResponseChannel <- gamecomm.ChanResponse{Val: []economy.MarketListing{}}
+
+	// Every seller is looked up once
+	for _, corporationId := range []uint64{2, 3, 1} {
+		lookup := <-corpChan
+		assert.Equal(t, lookup.Action, gamecomm.GetCorporation)
+		assert.Equal(t, lookup.CorporationId, corporationId)
+		lookup.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Corporation{ID: corporationId}}
+	}
+
+	command = <-economyChan
+	assert.Equal(t, command.Action, gamecomm.BuyMarketListings)
+	assert.Equal(t, len(command.Orders), 1)
+	assert.Equal(t, command.Orders[0].BuyerPlanetId, "Planet-1")
+	assert.Equal(t, command.Orders[0].Amount, 50)
+	assert.Equal(t, fmt.Sprint(command.Orders[0].ListingIds), "[sold-out cheap expensive]")
+	assert.Equal(t, command.Budgets["Planet-1"], 10_000.0)
+
+	// The best listing was sold before the planet could buy it
+	command.ResponseChannel <- gamecomm.ChanResponse{Val: []gamecomm.PurchaseFill{
+		{Order: 0, ListingId: "cheap", Requested: 30, Amount: 30, Price: 50},
+		{Order: 0, ListingId: "expensive", Requested: 20, Amount: 20, Price: 200},
+	}}
+
+	// Sellers are paid once for everything they sold to the zone
+	payments := make(map[uint64]float64)
+	for i := 0; i < 2; i++ {
+		payment := <-corpChan
+		assert.Equal(t, payment.Action, gamecomm.AddCredits)
+		payments[payment.CorporationId] = payment.AmountDecimal
+		payment.ResponseChannel <- gamecomm.ChanResponse{Val: payment.AmountDecimal}
+	}
+
+	<-done
+
+	assert.Equal(t, payments[1], 1_500.0)
+	assert.Equal(t, payments[2], 4_000.0)
+	assert.Equal(t, planet.Resources["iron"], 50)
+	assert.Equal(t, planet.Treasury, 4_500.0)
+	_, ok := planet.SupplierRecords[3]
+	assert.Equal(t, ok, false)
+	assert.Equal(t, planet.SupplierRecords[1], supplierRecord{ordered: 30, delivered: 30})
+}
+
+func TestBuyResourcesRefundsBudget(t *testing.T) {
+	economyChan := make(chan gamecomm.EconomyCommand)
+	corpChan := make(chan gamecomm.CorpCommand)
+
+	planet := &Planet{Name: "Planet-1", ZoneId: "Zone-1", Treasury: 10_000}
+
+	zd := &zoneDay{zoneId: "Zone-1"}
+	zd.requestPurchase(planet, "iron", 50)
+
+	done := make(chan struct{})
+	go func() {
+		zd.buyResources(economyChan, corpChan)
+		close(done)
+	}()
+
+	command := <-economyChan
+	command.ResponseChannel <- gamecomm.ChanResponse{Val: map[string]float64{"iron": 100}}
+
+	command = <-economyChan
+	command.ResponseChannel <- gamecomm.ChanResponse{Val: []economy.MarketListing{
+		{Id: "cheap", ResourceName: "iron", Amount: 1_000, Price: 50, CorporationId: 1},
+	}}
+
+	lookup := <-corpChan
+	lookup.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Corporation{ID: 1}}
+
+	// The budget is paid before the purchase
+	command = <-economyChan
+	assert.Equal(t, command.Action, gamecomm.BuyMarketListings)
+	assert.Equal(t, planet.Treasury, 0.0)
+	command.ResponseChannel <- gamecomm.ChanResponse{Err: fmt.Errorf("error: zone not found")}
+
+	<-done
+
+	assert.Equal(t, planet.Treasury, 10_000.0)
+	assert.Equal(t, planet.Resources["iron"], 0)
+}
+
+func TestBuyResourcesLedger(t *testing.T) {
+	tests := []struct {
+		name       string
+		escrowed   float64
+		mismatches int
+	}{
+		{
+			name:       "Goods Listed",
+			escrowed:   50,
+			mismatches: 0,
+		},
+		{
+			// The listing sold goods that never left a base
+			name:       "Goods Not Listed",
+			escrowed:   30,
+			mismatches: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			economyChan := make(chan gamecomm.EconomyCommand)
+			corpChan := make(chan gamecomm.CorpCommand)
+
+			l := ledger.New(gameclock.NewGameClock(0, 1))
+			market := gamecomm.MarketEscrow("Zone-1")
+
+			planet := &Planet{Name: "Planet-1", ZoneId: "Zone-1", Treasury: 10_000}
+			l.Transfer("opening", gamecomm.NewExternalAccount("opening"), planet.account(), gamecomm.CreditsAsset, 10_000)
+			l.Transfer("market listing", gamecomm.NewExternalAccount("seller"), market, "iron", tt.escrowed)
+
+			zd := &zoneDay{zoneId: "Zone-1", ledger: l}
+			zd.requestPurchase(planet, "iron", 50)
+
+			done := make(chan struct{})
+			go func() {
+				zd.buyResources(economyChan, corpChan)
+				close(done)
+			}()
+
+			command := <-economyChan
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: map[string]float64{"iron": 100}}
+
+			command = <-economyChan
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: []economy.MarketListing{
+				{Id: "cheap", ResourceName: "iron", Amount: 1_000, Price: 50, CorporationId: 1},
+			}}
+
+			lookup := <-corpChan
+			lookup.ResponseChannel <- gamecomm.ChanResponse{Val: gamecomm.Corporation{ID: 1}}
+
+			command = <-economyChan
+			command.ResponseChannel <- gamecomm.ChanResponse{Val: []gamecomm.PurchaseFill{
+				{Order: 0, ListingId: "cheap", Requested: 50, Amount: 50, Price: 50},
+			}}
+
+			payment := <-corpChan
+			payment.ResponseChannel <- gamecomm.ChanResponse{Val: payment.AmountDecimal}
+
+			<-done
+
+			report := l.Check(planet.holdings())
+			assert.Equal(t, len(report.Mismatches), tt.mismatches)
+			assert.Equal(t, l.Balance(planet.account())["iron"], 50.0)
+			assert.Equal(t, l.Balance(planet.account())[gamecomm.CreditsAsset], 7_500.0)
+		})
+	}
+}
+
+func TestRankListingsTies(t *testing.T) {
+	marketListings := []economy.MarketListing{
+		{Id: "first", Amount: 1_000, Price: 100},
+		{Id: "second", Amount: 1_000, Price: 100},
+		{Id: "cheap", Amount: 1_000, Price: 50},
+		{Id: "third", Amount: 1_000, Price: 100},
+	}
+
+	ranked := rankListings(marketListings, 100, 100, nil, defaultScoringWeights)
+
+	ids := []string{}
+	for _, ml := range ranked {
+		ids = append(ids, ml.Id)
+	}
+
+	assert.Equal(t, fmt.Sprint(ids), "[cheap first second third]")
+}
@@ -0,0 +1,159 @@
+package world
+
+import (
+	"github.com/luisya22/galactic-exchange/internal/economy"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// distanceScale is the distance at which a seller gets half of the distance score
+	distanceScale = 100.0
+	maxReputation = 100
+	// unknownReliability is the reliability of sellers the planet never bought from
+	unknownReliability = 0.5
+)
+
+// ScoringWeights is how much each factor counts when a planet scores market listings
+type ScoringWeights struct {
+	Price       float64
+	Amount      float64
+	Distance    float64
+	Reputation  float64
+	Reliability float64
+}
+
+var defaultScoringWeights = ScoringWeights{
+	Price:       0.5,
+	Amount:      0.2,
+	Distance:    0.1,
+	Reputation:  0.1,
+	Reliability: 0.1,
+}
+
+func (sw ScoringWeights) orDefault() ScoringWeights {
+	if sw == (ScoringWeights{}) {
+		return defaultScoringWeights
+	}
+
+	return sw
+}
+
+// seller is what the planet knows about the corporation selling a listing
+type seller struct {
+	distance    float64
+	reputation  int
+	reliability float64
+}
+
+// supplierRecord keeps how much the planet ordered from a seller and how much was delivered
+type supplierRecord struct {
+	ordered   int
+	delivered int
+}
+
+func (sr supplierRecord) reliability() float64 {
+	if sr.ordered == 0 {
+		return unknownReliability
+	}
+
+	return float64(sr.delivered) / float64(sr.ordered)
+}
+
+// recordDelivery should be called with the planet lock held
+func (planet *Planet) recordDelivery(corporationId uint64, ordered int, delivered int) {
+	if planet.SupplierRecords == nil {
+		planet.SupplierRecords = make(map[uint64]supplierRecord)
+	}
+
+	sr := planet.SupplierRecords[corporationId]
+	sr.ordered += ordered
+	sr.delivered += delivered
+	planet.SupplierRecords[corporationId] = sr
+}
+
+func (planet *Planet) sellerReliability(corporationId uint64) float64 {
+	planet.RW.RLock()
+	defer planet.RW.RUnlock()
+
+	return planet.SupplierRecords[corporationId].reliability()
+}
+
+// findCorporations gets every seller of the listings once
+func findCorporations(marketListings []economy.MarketListing, corpChan chan gamecomm.CorpCommand) map[uint64]gamecomm.Corporation {
+	corporations := make(map[uint64]gamecomm.Corporation)
+
+	for _, ml := range marketListings {
+		if _, ok := corporations[ml.CorporationId]; ok {
+			continue
+		}
+
+		resChan := make(chan gamecomm.ChanResponse)
+		corpChan <- gamecomm.CorpCommand{
+			Action:          gamecomm.GetCorporation,
+			CorporationId:   ml.CorporationId,
+			ResponseChannel: resChan,
+		}
+
+		res := <-resChan
+
+		corp, ok := res.Val.(gamecomm.Corporation)
+		if !ok || res.Err != nil {
+			corp = gamecomm.Corporation{ID: ml.CorporationId}
+		}
+
+		corporations[ml.CorporationId] = corp
+	}
+
+	return corporations
+}
+
+// sellers returns the distance to the closest base, the reputation and the reliability of every seller
+func (planet *Planet) sellers(corporations map[uint64]gamecomm.Corporation) map[uint64]seller {
+	sellers := make(map[uint64]seller, len(corporations))
+
+	for id, corp := range corporations {
+		sellers[id] = seller{
+			distance:    closestBaseDistance(planet.Location, corp.Bases),
+			reputation:  corp.Reputation,
+			reliability: planet.sellerReliability(id),
+		}
+	}
+
+	return sellers
+}
+
+func closestBaseDistance(location Coordinates, bases []*gamecomm.Base) float64 {
+	if len(bases) == 0 {
+		return distanceScale
+	}
+
+	planetLocation := gamecomm.Coordinates{X: location.X, Y: location.Y}
+
+	distance := gamecomm.Distance(planetLocation, bases[0].Location)
+	for _, b := range bases[1:] {
+		distance = min(distance, gamecomm.Distance(planetLocation, b.Location))
+	}
+
+	return distance
+}
+
+func scoreListing(marketListing economy.MarketListing, amountToBuy int, marketValue float64, s seller, weights ScoringWeights) float64 {
+	priceScore := marketValue / marketListing.Price
+
+	fullAmountScore := 0.0
+	if marketListing.Amount >= amountToBuy {
+		fullAmountScore = 1
+	}
+
+	distanceScore := 1 / (1 + s.distance/distanceScale)
+
+	reputationScore := (float64(max(min(s.reputation, maxReputation), -maxReputation)) + maxReputation) / (2 * maxReputation)
+
+	score := (weights.Price * priceScore) +
+		(weights.Amount * fullAmountScore) +
+		(weights.Distance * distanceScore) +
+		(weights.Reputation * reputationScore) +
+		(weights.Reliability * s.reliability)
+
+	return score
+}
@@ -0,0 +1,49 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestSellerReliability(t *testing.T) {
+	planet := &Planet{}
+
+	assert.Equal(t, planet.sellerReliability(1), unknownReliability)
+
+	planet.recordDelivery(1, 100, 100)
+	planet.recordDelivery(1, 100, 0)
+
+	assert.Equal(t, planet.sellerReliability(1), 0.5)
+	assert.Equal(t, planet.sellerReliability(2), unknownReliability)
+}
+
+func TestClosestBaseDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		bases    []*gamecomm.Base
+		expected float64
+	}{
+		{
+			name:     "No Bases",
+			bases:    nil,
+			expected: distanceScale,
+		},
+		{
+			name: "Closest Base",
+			bases: []*gamecomm.Base{
+				{Location: gamecomm.Coordinates{X: 30, Y: 40}},
+				{Location: gamecomm.Coordinates{X: 3, Y: 4}},
+			},
+			expected: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance := closestBaseDistance(Coordinates{}, tt.bases)
+			assert.Equal(t, distance, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+const (
+	// incomePerCapita is the daily credits each inhabitant adds to the planet treasury
+	incomePerCapita = 0.0001
+	// initialTreasuryDays is the days of income planets start with
+	initialTreasuryDays = 30
+)
+
+func dailyIncome(population int) float64 {
+	return float64(population) * incomePerCapita
+}
+
+// collectIncome adds the daily income of the population to the planet treasury and returns it
+func (p *Planet) collectIncome() float64 {
+	p.RW.Lock()
+	defer p.RW.Unlock()
+
+	income := dailyIncome(p.Population)
+	p.Treasury += income
+
+	return income
+}
+
+// spend takes the credits out of the planet treasury, the planet can't spend more than it has
+func (p *Planet) spend(credits float64) error {
+	p.RW.Lock()
+	defer p.RW.Unlock()
+
+	if credits > p.Treasury {
+		return fmt.Errorf("error: planet %v can't afford %v credits", p.Name, credits)
+	}
+
+	p.Treasury -= credits
+
+	return nil
+}
+
+// deposit gives the credits back to the planet treasury
+func (p *Planet) deposit(credits float64) {
+	p.RW.Lock()
+	defer p.RW.Unlock()
+
+	p.Treasury += credits
+}
+
+// paySeller gives the credits of a purchase to the corporation that sold the resources, the credits come from the
+// market escrow
+func paySeller(corporationId uint64, credits float64, market gamecomm.Account, corpChan chan gamecomm.CorpCommand) error {
+	resChan := make(chan gamecomm.ChanResponse)
+	corpChan <- gamecomm.CorpCommand{
+		Action:          gamecomm.AddCredits,
+		CorporationId:   corporationId,
+		AmountDecimal:   credits,
+		ResponseChannel: resChan,
+		Counterparty:    market,
+		Memo:            "market sale",
+	}
+
+	res := <-resChan
+
+	return res.Err
+}
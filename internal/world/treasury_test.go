@@ -0,0 +1,44 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/luisya22/galactic-exchange/internal/assert"
+	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+)
+
+func TestCollectIncome(t *testing.T) {
+	planet := &Planet{Name: "Planet-1", Population: 1_000_000, Treasury: 50}
+
+	planet.collectIncome()
+
+	assert.Equal(t, planet.Treasury, 150.0)
+}
+
+func TestSpend(t *testing.T) {
+	planet := &Planet{Name: "Planet-1", Treasury: 100}
+
+	err := planet.spend(150)
+	assert.Error(t, err)
+	assert.Equal(t, planet.Treasury, 100.0)
+
+	err = planet.spend(60)
+	assert.NilError(t, err)
+	assert.Equal(t, planet.Treasury, 40.0)
+}
+
+func TestPaySeller(t *testing.T) {
+	corpChan := make(chan gamecomm.CorpCommand)
+
+	go func() {
+		command := <-corpChan
+		assert.Equal(t, command.Action, gamecomm.AddCredits)
+		assert.Equal(t, command.CorporationId, uint64(7))
+		assert.Equal(t, command.AmountDecimal, 250.0)
+
+		command.ResponseChannel <- gamecomm.ChanResponse{Val: 250.0}
+	}()
+
+	err := paySeller(7, 250, gamecomm.MarketEscrow("Zone-1"), corpChan)
+	assert.NilError(t, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/luisya22/galactic-exchange/internal/gameclock"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/ledger"
 	"github.com/luisya22/galactic-exchange/internal/resource"
 )
 
@@ -22,13 +23,15 @@ type World struct {
 	Workers         int
 	WorldChan       chan gamecomm.WorldCommand
 	economyChan     chan gamecomm.EconomyCommand
+	corpChan        chan gamecomm.CorpCommand
 	Size            float64
 	Categories      map[string]Category
 	gameClock       *gameclock.GameClock
 	newDayChan      chan gameclock.GameTime
+	ledger          *ledger.Ledger
 }
 
-func New(gameChannels *gamecomm.GameChannels, resources map[string]resource.Resource, gc *gameclock.GameClock) *World {
+func New(gameChannels *gamecomm.GameChannels, resources map[string]resource.Resource, gc *gameclock.GameClock, l *ledger.Ledger) *World {
 
 	randomnumber := rand.New(rand.NewSource(time.Now().UnixNano()))
 	newDayChan := make(chan gameclock.GameTime)
@@ -43,10 +46,12 @@ func New(gameChannels *gamecomm.GameChannels, resources map[string]resource.Reso
 		Workers:      100,
 		WorldChan:    gameChannels.WorldChannel,
 		economyChan:  gameChannels.EconomyChannel,
+		corpChan:     gameChannels.CorpChannel,
 		Size:         10_000,
 		Categories:   allCategories,
 		gameClock:    gc,
 		newDayChan:   newDayChan,
+		ledger:       l,
 	}
 
 	world.Zones = make(map[string]*Zone, 1000)
@@ -55,6 +60,7 @@ func New(gameChannels *gamecomm.GameChannels, resources map[string]resource.Reso
 	world.LayerBoundaries = GenerateLayerBoundaries(world)
 
 	world.GenerateZones(1000)
+	world.openAccounts()
 	world.gameClock.Subscribe(newDayChan)
 
 	go world.Listen()
@@ -64,58 +70,66 @@ func New(gameChannels *gamecomm.GameChannels, resources map[string]resource.Reso
 }
 
 func (w *World) randomInt(min, max int) int {
-	return min + w.RandomNumber.Intn(max-min+1)
+	return randomInt(w.RandomNumber, min, max)
+}
+
+func randomInt(random *rand.Rand, min, max int) int {
+	return min + random.Intn(max-min+1)
 }
 
 func CreateZoneTypes() map[LayerName]ZoneType {
 	return map[LayerName]ZoneType{
 		SectorOne: {
-			Name:                 SectorOne,
-			LowerDanger:          0,
-			HigherDanger:         10,
-			LowerPopulation:      0,
-			HigherPopulation:     7_000_000_000,
-			LowerPlanetsAmount:   1,
-			HigherPlanetsAmount:  10,
-			HabitableProbability: .75,
-			Index:                0,
-			MapPercentage:        .15,
+			Name:                   SectorOne,
+			LowerDanger:            0,
+			HigherDanger:           10,
+			LowerPopulation:        0,
+			HigherPopulation:       7_000_000_000,
+			LowerPlanetsAmount:     1,
+			HigherPlanetsAmount:    10,
+			HabitableProbability:   .75,
+			Index:                  0,
+			MapPercentage:          .15,
+			RegenerationMultiplier: 0.5,
 		},
 		SectorTwo: {
-			Name:                 SectorTwo,
-			LowerDanger:          10,
-			HigherDanger:         25,
-			LowerPopulation:      0,
-			HigherPopulation:     5_000_000_000,
-			LowerPlanetsAmount:   1,
-			HigherPlanetsAmount:  10,
-			HabitableProbability: .50,
-			Index:                1,
-			MapPercentage:        .20,
+			Name:                   SectorTwo,
+			LowerDanger:            10,
+			HigherDanger:           25,
+			LowerPopulation:        0,
+			HigherPopulation:       5_000_000_000,
+			LowerPlanetsAmount:     1,
+			HigherPlanetsAmount:    10,
+			HabitableProbability:   .50,
+			Index:                  1,
+			MapPercentage:          .20,
+			RegenerationMultiplier: 1,
 		},
 		SectorThree: {
-			Name:                 SectorThree,
-			LowerDanger:          25,
-			HigherDanger:         50,
-			LowerPopulation:      0,
-			HigherPopulation:     1_000_000,
-			LowerPlanetsAmount:   1,
-			HigherPlanetsAmount:  15,
-			HabitableProbability: .20,
-			Index:                2,
-			MapPercentage:        .20,
+			Name:                   SectorThree,
+			LowerDanger:            25,
+			HigherDanger:           50,
+			LowerPopulation:        0,
+			HigherPopulation:       1_000_000,
+			LowerPlanetsAmount:     1,
+			HigherPlanetsAmount:    15,
+			HabitableProbability:   .20,
+			Index:                  2,
+			MapPercentage:          .20,
+			RegenerationMultiplier: 1.5,
 		},
 		SectorFour: {
-			Name:                 SectorFour,
-			LowerDanger:          50,
-			HigherDanger:         100,
-			LowerPopulation:      0,
-			HigherPopulation:     0,
-			LowerPlanetsAmount:   1,
-			HigherPlanetsAmount:  20,
-			HabitableProbability: 0,
-			Index:                3,
-			MapPercentage:        .45,
+			Name:                   SectorFour,
+			LowerDanger:            50,
+			HigherDanger:           100,
+			LowerPopulation:        0,
+			HigherPopulation:       0,
+			LowerPlanetsAmount:     1,
+			HigherPlanetsAmount:    20,
+			HabitableProbability:   0,
+			Index:                  3,
+			MapPercentage:          .45,
+			RegenerationMultiplier: 2,
 		},
 	}
 
@@ -145,6 +159,16 @@ func (w *World) GetZoneByIndex(index int) (*ZoneType, error) {
 	return nil, fmt.Errorf("error: zone not found with inde %v", index)
 }
 
+// GetZoneLocations returns the central point of every zone
+func (w *World) GetZoneLocations() map[string]gamecomm.Coordinates {
+	locations := make(map[string]gamecomm.Coordinates, len(w.Zones))
+	for _, zone := range w.Zones {
+		locations[zone.Name] = gamecomm.Coordinates(zone.CentralPoint)
+	}
+
+	return locations
+}
+
 func (w *World) GetZoneIds() []string {
 	ids := []string{}
 	for _, zone := range w.Zones {
@@ -33,6 +33,8 @@ func (w *World) worker(ch <-chan gamecomm.WorldCommand) {
 			amount, err := w.AddResourcesToPlanet(command.PlanetId, command.Resource, command.Amount)
 			if err != nil {
 				command.ResponseChannel <- gamecomm.ChanResponse{Err: err}
+			} else {
+				w.ledger.Transfer(commandMemo(command), command.Counterparty, gamecomm.NewPlanetAccount(command.PlanetId), command.Resource, float64(command.Amount))
 			}
 
 			command.ResponseChannel <- gamecomm.ChanResponse{
@@ -42,6 +44,9 @@ func (w *World) worker(ch <-chan gamecomm.WorldCommand) {
 
 		case gamecomm.RemoveResourcesFromPlanet:
 			amount, err := w.RemoveResourcesFromPlanet(command.PlanetId, command.Resource, command.Amount)
+			if err == nil {
+				w.ledger.Transfer(commandMemo(command), gamecomm.NewPlanetAccount(command.PlanetId), command.Counterparty, command.Resource, float64(command.Amount))
+			}
 
 			command.ResponseChannel <- gamecomm.ChanResponse{
 				Val: amount,
@@ -58,6 +63,21 @@ func (w *World) worker(ch <-chan gamecomm.WorldCommand) {
 				Err: nil,
 			}
 
+		case gamecomm.GetPlanetDemand:
+			demand, err := w.GetPlanetDemand(command.PlanetId)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{
+				Val: demand,
+				Err: err,
+			}
+		case gamecomm.GetZoneDemand:
+			demand, err := w.GetZoneDemand(command.ZoneId)
+
+			command.ResponseChannel <- gamecomm.ChanResponse{
+				Val: demand,
+				Err: err,
+			}
+
 		default:
 			command.ResponseChannel <- gamecomm.ChanResponse{Err: fmt.Errorf("error: wrong action")}
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/luisya22/galactic-exchange/internal/assert"
 	"github.com/luisya22/galactic-exchange/internal/gamecomm"
+	"github.com/luisya22/galactic-exchange/internal/world"
 )
 
 func TestGetPlanet(t *testing.T) {
@@ -470,3 +471,83 @@ func TestRemoveResourcesToPlanet(t *testing.T) {
 	})
 
 }
+
+func TestGetDemand(t *testing.T) {
+	gameChannels := &gamecomm.GameChannels{
+		WorldChannel: make(chan gamecomm.WorldCommand, 10),
+	}
+
+	w := createTestWorld(t, gameChannels)
+	w.Listen()
+
+	zone := w.Zones["Zone-1"]
+	createTestPlanet(w, zone, "Zone-1-Planet-2", true, world.Coordinates{30, 30}, 0, 1)
+
+	w.Planets[planet1Name].ResourceDemand = map[string]world.ResourceDemand{
+		"iron": {Quantity: 100, MaxPrice: 300},
+		"food": {Quantity: 50, MaxPrice: 12},
+	}
+	w.Planets["Zone-1-Planet-2"].ResourceDemand = map[string]world.ResourceDemand{
+		"iron": {Quantity: 200, MaxPrice: 250},
+	}
+
+	tests := []struct {
+		name        string
+		command     gamecomm.WorldCommand
+		wants       map[string]gamecomm.ResourceDemand
+		shouldError bool
+	}{
+		{
+			name:    "Planet Demand",
+			command: gamecomm.WorldCommand{Action: gamecomm.GetPlanetDemand, PlanetId: planet1Name},
+			wants: map[string]gamecomm.ResourceDemand{
+				"iron": {Quantity: 100, MaxPrice: 300},
+				"food": {Quantity: 50, MaxPrice: 12},
+			},
+		},
+		{
+			name:    "Zone Demand",
+			command: gamecomm.WorldCommand{Action: gamecomm.GetZoneDemand, ZoneId: "Zone-1"},
+			wants: map[string]gamecomm.ResourceDemand{
+				"iron": {Quantity: 300, MaxPrice: 300},
+				"food": {Quantity: 50, MaxPrice: 12},
+			},
+		},
+		{
+			name:        "Invalid Planet ID",
+			command:     gamecomm.WorldCommand{Action: gamecomm.GetPlanetDemand, PlanetId: "Wrong Planet Name"},
+			shouldError: true,
+		},
+		{
+			name:        "Invalid Zone ID",
+			command:     gamecomm.WorldCommand{Action: gamecomm.GetZoneDemand, ZoneId: "Wrong Zone Name"},
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resChan := make(chan gamecomm.ChanResponse)
+			tt.command.ResponseChannel = resChan
+
+			gameChannels.WorldChannel <- tt.command
+
+			res := <-resChan
+			if tt.shouldError {
+				assert.Error(t, res.Err)
+				return
+			}
+
+			assert.NilError(t, res.Err)
+
+			demand, ok := res.Val.(map[string]gamecomm.ResourceDemand)
+			if !ok {
+				t.Fatalf("type conversion failed - got: %v; expected: %v", reflect.TypeOf(res.Val), "map[string]gamecomm.ResourceDemand")
+			}
+
+			if !reflect.DeepEqual(demand, tt.wants) {
+				t.Errorf("got: %v; want: %v", demand, tt.wants)
+			}
+		})
+	}
+}
@@ -8,16 +8,17 @@ import (
 )
 
 type ZoneType struct {
-	Name                 LayerName
-	LowerDanger          int
-	HigherDanger         int
-	LowerPopulation      int
-	HigherPopulation     int
-	LowerPlanetsAmount   int
-	HigherPlanetsAmount  int
-	HabitableProbability float64
-	Index                int
-	MapPercentage        float64
+	Name                   LayerName
+	LowerDanger            int
+	HigherDanger           int
+	LowerPopulation        int
+	HigherPopulation       int
+	LowerPlanetsAmount     int
+	HigherPlanetsAmount    int
+	HabitableProbability   float64
+	Index                  int
+	MapPercentage          float64
+	RegenerationMultiplier float64
 }
 
 type Zone struct {